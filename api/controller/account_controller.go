@@ -0,0 +1,204 @@
+package controller
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type AccountController struct {
+	AccountUsecase domain.AccountUsecase
+	Env            *bootstrap.Env
+}
+
+// Export godoc
+// @Summary      导出个人数据
+// @Description  下载当前用户的账号资料、训练记录、健身计划、个人模板和反馈，支持 JSON 或 ZIP 格式
+// @Tags         用户信息
+// @Produce      json
+// @Produce      application/zip
+// @Security     BearerAuth
+// @Param        format query string false "导出格式(json/zip)" default(json)
+// @Success      200 {object} domain.AccountExport "导出文件"
+// @Failure      400 {object} domain.ErrorResponse "不支持的导出格式"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/account/export [get]
+func (ac *AccountController) Export(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	format := c.DefaultQuery("format", domain.ExportFormatJSON)
+	if format != domain.ExportFormatJSON && format != domain.ExportFormatZIP {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "不支持的导出格式"))
+		return
+	}
+
+	export, err := ac.AccountUsecase.Export(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "导出数据失败"))
+		return
+	}
+
+	filename := fmt.Sprintf("flow-link-export-%s-%s", export.User.Username, time.Now().Format("20060102"))
+
+	if format == domain.ExportFormatZIP {
+		data, err := buildExportZip(&export)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "导出数据失败"))
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".zip"))
+		c.Data(http.StatusOK, "application/zip", data)
+		return
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "导出数据失败"))
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename+".json"))
+	c.Data(http.StatusOK, "application/json; charset=utf-8", data)
+}
+
+// RequestDeletion godoc
+// @Summary      申请注销账号
+// @Description  校验密码后安排注销，宽限期结束后删除账号及全部个人数据，宽限期内可撤销
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.DeleteAccountRequest true "当前密码"
+// @Success      200 {object} domain.SuccessResponse{data=domain.AccountDeletionResponse} "已申请注销"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "密码错误"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/account/deletion [post]
+func (ac *AccountController) RequestDeletion(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	var request domain.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	scheduledAt, err := ac.AccountUsecase.RequestDeletion(c, userID, request.Password, ac.Env.AccountDeletionGraceDay)
+	if err != nil {
+		if errors.Is(err, domain.ErrPasswordIncorrect) {
+			c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "密码错误"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "申请注销失败"))
+		return
+	}
+
+	response := domain.AccountDeletionResponse{
+		ScheduledAt: scheduledAt.Format("2006-01-02 15:04:05"),
+	}
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(response, "已申请注销"))
+}
+
+// CancelDeletion godoc
+// @Summary      撤销注销申请
+// @Description  在宽限期内撤销账号注销申请
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SuccessResponse "已撤销"
+// @Failure      400 {object} domain.ErrorResponse "没有待执行的注销申请"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/account/deletion [delete]
+func (ac *AccountController) CancelDeletion(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	err := ac.AccountUsecase.CancelDeletion(c, userID)
+	if err != nil {
+		if errors.Is(err, domain.ErrAccountDeletionNotRequested) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "没有待执行的注销申请"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "撤销注销失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "已撤销"))
+}
+
+// buildExportZip 将导出内容按集合拆分为多个 JSON 文件打包
+func buildExportZip(export *domain.AccountExport) ([]byte, error) {
+	files := []struct {
+		name    string
+		content interface{}
+	}{
+		{"user.json", export.User},
+		{"training_records.json", export.TrainingRecords},
+		{"fitness_plans.json", export.FitnessPlans},
+		{"plan_templates.json", export.PlanTemplates},
+		{"feedbacks.json", export.Feedbacks},
+		{"body_metrics.json", export.BodyMetrics},
+		{"progress_photos.json", export.ProgressPhotos},
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, err
+		}
+		data, err := json.MarshalIndent(file.content, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,205 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type AdminUserController struct {
+	AdminUserUsecase domain.AdminUserUsecase
+}
+
+// GetList godoc
+// @Summary      获取用户列表（管理员）
+// @Description  按用户名、昵称、邮箱或手机号模糊搜索用户，分页返回
+// @Tags         管理员-用户
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        keyword query string false "搜索关键词"
+// @Param        page query int false "页码" default(1)
+// @Param        pageSize query int false "每页数量" default(20)
+// @Success      200 {object} domain.SuccessResponse{data=domain.PaginatedData} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/admin/users [get]
+func (auc *AdminUserController) GetList(c *gin.Context) {
+	// 解析分页参数
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	keyword := c.DefaultQuery("keyword", "")
+
+	users, total, err := auc.AdminUserUsecase.Search(c, keyword, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取用户列表失败"))
+		return
+	}
+
+	result := []domain.AdminUserResponse{}
+	for i := range users {
+		result = append(result, toAdminUserResponse(&users[i]))
+	}
+
+	paginatedData := domain.PaginatedData{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Users:    result,
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(paginatedData))
+}
+
+// GetByID godoc
+// @Summary      获取用户资料（管理员）
+// @Description  查看指定用户的资料、角色和账号状态
+// @Tags         管理员-用户
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userId path string true "用户ID"
+// @Success      200 {object} domain.SuccessResponse{data=domain.AdminUserResponse} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Router       /api/admin/users/{userId} [get]
+func (auc *AdminUserController) GetByID(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "用户ID不能为空"))
+		return
+	}
+
+	user, err := auc.AdminUserUsecase.GetByID(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "用户不存在"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(toAdminUserResponse(&user)))
+}
+
+// Disable godoc
+// @Summary      禁用账号（管理员）
+// @Description  禁用指定账号并吊销其所有登录会话，禁用期间无法登录或访问接口
+// @Tags         管理员-用户
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userId path string true "用户ID"
+// @Success      200 {object} domain.SuccessResponse "禁用成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Router       /api/admin/users/{userId}/disable [post]
+func (auc *AdminUserController) Disable(c *gin.Context) {
+	auc.setDisabled(c, true, "禁用成功")
+}
+
+// Enable godoc
+// @Summary      启用账号（管理员）
+// @Description  重新启用被禁用的账号，用户需重新登录
+// @Tags         管理员-用户
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userId path string true "用户ID"
+// @Success      200 {object} domain.SuccessResponse "启用成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Router       /api/admin/users/{userId}/enable [post]
+func (auc *AdminUserController) Enable(c *gin.Context) {
+	auc.setDisabled(c, false, "启用成功")
+}
+
+// ForceLogout godoc
+// @Summary      强制下线（管理员）
+// @Description  吊销指定用户所有设备的登录会话和刷新令牌
+// @Tags         管理员-用户
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userId path string true "用户ID"
+// @Success      200 {object} domain.SuccessResponse "下线成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Router       /api/admin/users/{userId}/logout [post]
+func (auc *AdminUserController) ForceLogout(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "用户ID不能为空"))
+		return
+	}
+
+	err := auc.AdminUserUsecase.ForceLogout(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "用户不存在"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "下线成功"))
+}
+
+func (auc *AdminUserController) setDisabled(c *gin.Context, disabled bool, message string) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "用户ID不能为空"))
+		return
+	}
+
+	// 禁止管理员禁用自己，避免失去管理入口
+	if disabled && c.GetString("x-user-id") == userID {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "不能禁用自己的账号"))
+		return
+	}
+
+	err := auc.AdminUserUsecase.SetDisabled(c, userID, disabled)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "用户不存在"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, message))
+}
+
+func toAdminUserResponse(user *domain.User) domain.AdminUserResponse {
+	response := domain.AdminUserResponse{
+		ID:            user.ID.Hex(),
+		Username:      user.Username,
+		Nickname:      user.Nickname,
+		AvatarUrl:     user.AvatarUrl,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		Phone:         user.Phone,
+		Gender:        user.Gender,
+		Age:           user.Age,
+		Height:        user.Height,
+		Weight:        user.Weight,
+		TargetWeight:  user.TargetWeight,
+		FitnessGoal:   user.FitnessGoal,
+		Role:          user.Role,
+		Roles:         user.Roles,
+		Disabled:      user.Disabled,
+		JoinDate:      user.JoinDate,
+	}
+	if response.Roles == nil {
+		response.Roles = []string{}
+	}
+	if user.DisabledAt != nil {
+		response.DisabledAt = user.DisabledAt.Time().Format("2006-01-02 15:04:05")
+	}
+	return response
+}
@@ -0,0 +1,258 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type BodyMetricController struct {
+	BodyMetricUsecase domain.BodyMetricUsecase
+}
+
+// Create godoc
+// @Summary      记录身体数据
+// @Description  记录某一天的体重、体脂率和围度，每天一条；包含体重时同步个人资料中的体重
+// @Tags         身体数据
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateBodyMetricRequest true "身体数据"
+// @Success      200 {object} domain.SuccessResponse{data=domain.BodyMetric} "记录成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      409 {object} domain.ErrorResponse "当天已有记录"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/body-metrics [post]
+func (bc *BodyMetricController) Create(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	var request domain.CreateBodyMetricRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	metric, err := bc.BodyMetricUsecase.Create(c, userID, &request)
+	if err != nil {
+		respondBodyMetricError(c, err, "记录身体数据失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(metric))
+}
+
+// GetList godoc
+// @Summary      获取身体数据列表
+// @Description  按日期升序返回身体数据记录，可按日期范围筛选
+// @Tags         身体数据
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        startDate query string false "开始日期" format(date)
+// @Param        endDate query string false "结束日期" format(date)
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.BodyMetric} "获取成功"
+// @Failure      400 {object} domain.ErrorResponse "日期格式错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/body-metrics [get]
+func (bc *BodyMetricController) GetList(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	metrics, err := bc.BodyMetricUsecase.GetList(c, userID, c.Query("startDate"), c.Query("endDate"))
+	if err != nil {
+		respondBodyMetricError(c, err, "获取身体数据失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(metrics))
+}
+
+// GetTrend godoc
+// @Summary      获取身体数据趋势
+// @Description  返回指标在日期范围内的记录、移动平均、变化量和每周变化速度
+// @Tags         身体数据
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        metric query string false "指标(weight/bodyFat/waist/chest/arm/thigh)" default(weight)
+// @Param        startDate query string false "开始日期，默认结束日期前 90 天" format(date)
+// @Param        endDate query string false "结束日期，默认今天" format(date)
+// @Param        window query int false "移动平均窗口(天)" default(7)
+// @Success      200 {object} domain.SuccessResponse{data=domain.BodyMetricTrend} "获取成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/body-metrics/trend [get]
+func (bc *BodyMetricController) GetTrend(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	metric := c.DefaultQuery("metric", domain.BodyMetricWeight)
+	window, _ := strconv.Atoi(c.DefaultQuery("window", strconv.Itoa(domain.DefaultTrendWindow)))
+
+	trend, err := bc.BodyMetricUsecase.GetTrend(c, userID, metric, c.Query("startDate"), c.Query("endDate"), window)
+	if err != nil {
+		respondBodyMetricError(c, err, "获取身体数据趋势失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(trend))
+}
+
+// GetByID godoc
+// @Summary      获取身体数据详情
+// @Tags         身体数据
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        metricId path string true "记录ID"
+// @Success      200 {object} domain.SuccessResponse{data=domain.BodyMetric} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "记录不存在"
+// @Router       /api/body-metrics/{metricId} [get]
+func (bc *BodyMetricController) GetByID(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	metric, err := bc.BodyMetricUsecase.GetByID(c, userID, c.Param("metricId"))
+	if err != nil {
+		respondBodyMetricError(c, err, "获取身体数据失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(metric))
+}
+
+// Update godoc
+// @Summary      更新身体数据
+// @Description  只修改请求中提供的字段
+// @Tags         身体数据
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        metricId path string true "记录ID"
+// @Param        request body domain.UpdateBodyMetricRequest true "身体数据"
+// @Success      200 {object} domain.SuccessResponse{data=domain.BodyMetric} "更新成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "记录不存在"
+// @Failure      409 {object} domain.ErrorResponse "当天已有记录"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/body-metrics/{metricId} [put]
+func (bc *BodyMetricController) Update(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	var request domain.UpdateBodyMetricRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	metric, err := bc.BodyMetricUsecase.Update(c, userID, c.Param("metricId"), &request)
+	if err != nil {
+		respondBodyMetricError(c, err, "更新身体数据失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(metric))
+}
+
+// Delete godoc
+// @Summary      删除身体数据
+// @Tags         身体数据
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        metricId path string true "记录ID"
+// @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "删除成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "记录不存在"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/body-metrics/{metricId} [delete]
+func (bc *BodyMetricController) Delete(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	if err := bc.BodyMetricUsecase.Delete(c, userID, c.Param("metricId")); err != nil {
+		respondBodyMetricError(c, err, "删除身体数据失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(map[string]interface{}{
+		"message": "身体数据删除成功",
+	}))
+}
+
+func respondBodyMetricError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrBodyMetricNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "身体数据不存在"))
+	case errors.Is(err, domain.ErrBodyMetricConflict):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "当天已有身体数据记录，请修改已有记录"))
+	case errors.Is(err, domain.ErrInvalidBodyMetric):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "日期应为 YYYY-MM-DD，指标至少填写一项且为支持的类型"))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, message))
+	}
+}
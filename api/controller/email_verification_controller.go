@@ -0,0 +1,104 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type EmailVerificationController struct {
+	EmailVerificationUsecase domain.EmailVerificationUsecase
+	Env                      *bootstrap.Env
+}
+
+// SendCode godoc
+// @Summary      发送邮箱验证码
+// @Description  向当前用户绑定的邮箱发送 6 位验证码，每分钟最多发送一次
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SuccessResponse "已发送"
+// @Failure      400 {object} domain.ErrorResponse "未绑定邮箱或邮箱已验证"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      429 {object} domain.ErrorResponse "发送过于频繁"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/email/verification-code [post]
+func (ec *EmailVerificationController) SendCode(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	err := ec.EmailVerificationUsecase.SendCode(c, userID, ec.Env.EmailVerificationExpiryMinute)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrEmailNotSet):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "请先绑定邮箱"))
+		case errors.Is(err, domain.ErrEmailAlreadyVerified):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "邮箱已验证"))
+		case errors.Is(err, domain.ErrVerificationCodeTooFrequent):
+			c.JSON(http.StatusTooManyRequests, domain.NewErrorResponse(429, "验证码发送过于频繁，请稍后再试"))
+		default:
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "发送验证码失败"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "验证码已发送"))
+}
+
+// Verify godoc
+// @Summary      验证邮箱
+// @Description  提交邮箱收到的验证码完成验证，同一验证码最多尝试 5 次
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.VerifyEmailRequest true "验证码"
+// @Success      200 {object} domain.SuccessResponse "验证成功"
+// @Failure      400 {object} domain.ErrorResponse "验证码错误或已过期"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/email/verify [post]
+func (ec *EmailVerificationController) Verify(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	var request domain.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	err := ec.EmailVerificationUsecase.Verify(c, userID, request.Code)
+	if err != nil {
+		if errors.Is(err, domain.ErrVerificationCodeInvalid) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "验证码错误或已过期"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "验证邮箱失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "验证成功"))
+}
@@ -0,0 +1,203 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type ExerciseCatalogController struct {
+	ExerciseCatalogUsecase domain.ExerciseCatalogUsecase
+}
+
+// Search godoc
+// @Summary      检索动作库
+// @Description  按名称或别名关键词、肌群、器械筛选动作，分页返回
+// @Tags         动作库
+// @Accept       json
+// @Produce      json
+// @Param        keyword query string false "名称或别名关键词"
+// @Param        muscle query string false "肌群（匹配主要或次要肌群）"
+// @Param        equipment query string false "器械"
+// @Param        page query int false "页码" default(1)
+// @Param        pageSize query int false "每页数量" default(20)
+// @Success      200 {object} domain.SuccessResponse{data=domain.PaginatedData} "获取成功"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/exercises [get]
+func (ec *ExerciseCatalogController) Search(c *gin.Context) {
+	// 解析分页参数
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	keyword := c.DefaultQuery("keyword", "")
+	muscle := c.DefaultQuery("muscle", "")
+	equipment := c.DefaultQuery("equipment", "")
+
+	exercises, total, err := ec.ExerciseCatalogUsecase.Search(c, keyword, muscle, equipment, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取动作列表失败"))
+		return
+	}
+
+	paginatedData := domain.PaginatedData{
+		Total:     total,
+		Page:      page,
+		PageSize:  pageSize,
+		Exercises: exercises,
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(paginatedData))
+}
+
+// Autocomplete godoc
+// @Summary      动作名称自动补全
+// @Description  按名称或别名前缀匹配动作，用于输入框联想
+// @Tags         动作库
+// @Accept       json
+// @Produce      json
+// @Param        q query string true "输入前缀"
+// @Param        limit query int false "返回数量" default(10)
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.CatalogExercise} "获取成功"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/exercises/autocomplete [get]
+func (ec *ExerciseCatalogController) Autocomplete(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 || limit > 50 {
+		limit = 10
+	}
+
+	exercises, err := ec.ExerciseCatalogUsecase.Autocomplete(c, c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取动作列表失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(exercises))
+}
+
+// GetByID godoc
+// @Summary      获取动作详情
+// @Description  根据规范动作ID获取动作信息
+// @Tags         动作库
+// @Accept       json
+// @Produce      json
+// @Param        exerciseId path string true "动作ID"
+// @Success      200 {object} domain.SuccessResponse{data=domain.CatalogExercise} "获取成功"
+// @Failure      404 {object} domain.ErrorResponse "动作不存在"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/exercises/{exerciseId} [get]
+func (ec *ExerciseCatalogController) GetByID(c *gin.Context) {
+	exercise, err := ec.ExerciseCatalogUsecase.GetByID(c, c.Param("exerciseId"))
+	if err != nil {
+		respondExerciseCatalogError(c, err, "获取动作失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(exercise))
+}
+
+// Create godoc
+// @Summary      新增动作（管理员）
+// @Description  向动作库新增动作，名称唯一；名称和别名用于检索和自由文本关联
+// @Tags         管理员-动作库
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.CreateCatalogExerciseRequest true "动作信息"
+// @Success      200 {object} domain.SuccessResponse{data=domain.CatalogExercise} "创建成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      409 {object} domain.ErrorResponse "动作名称已存在"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/admin/exercises [post]
+func (ec *ExerciseCatalogController) Create(c *gin.Context) {
+	var request domain.CreateCatalogExerciseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	exercise, err := ec.ExerciseCatalogUsecase.Create(c, &request)
+	if err != nil {
+		respondExerciseCatalogError(c, err, "创建动作失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(exercise, "创建成功"))
+}
+
+// Update godoc
+// @Summary      更新动作（管理员）
+// @Description  更新动作信息，未提供的字段保持不变；动作ID不变，已关联的记录不受影响
+// @Tags         管理员-动作库
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        exerciseId path string true "动作ID"
+// @Param        request body domain.UpdateCatalogExerciseRequest true "动作信息"
+// @Success      200 {object} domain.SuccessResponse{data=domain.CatalogExercise} "更新成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "动作不存在"
+// @Failure      409 {object} domain.ErrorResponse "动作名称已存在"
+// @Router       /api/admin/exercises/{exerciseId} [put]
+func (ec *ExerciseCatalogController) Update(c *gin.Context) {
+	var request domain.UpdateCatalogExerciseRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	exercise, err := ec.ExerciseCatalogUsecase.Update(c, c.Param("exerciseId"), &request)
+	if err != nil {
+		respondExerciseCatalogError(c, err, "更新动作失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(exercise, "更新成功"))
+}
+
+// Delete godoc
+// @Summary      删除动作（管理员）
+// @Description  从动作库删除动作，已关联该动作的记录保留原名称
+// @Tags         管理员-动作库
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        exerciseId path string true "动作ID"
+// @Success      200 {object} domain.SuccessResponse "删除成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "动作不存在"
+// @Router       /api/admin/exercises/{exerciseId} [delete]
+func (ec *ExerciseCatalogController) Delete(c *gin.Context) {
+	err := ec.ExerciseCatalogUsecase.Delete(c, c.Param("exerciseId"))
+	if err != nil {
+		respondExerciseCatalogError(c, err, "删除动作失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "删除成功"))
+}
+
+func respondExerciseCatalogError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrExerciseNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "动作不存在"))
+	case errors.Is(err, domain.ErrExerciseConflict):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "动作名称已存在"))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, message))
+	}
+}
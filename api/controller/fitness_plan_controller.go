@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -47,6 +48,22 @@ func (fc *FitnessPlanController) CreateFromTemplate(c *gin.Context) {
 
 	result, err := fc.FitnessPlanUsecase.CreateFromTemplate(c, userID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProgression) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "进阶规则参数无效"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanPhases) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划阶段需从第 1 周起连续覆盖整个计划周期，且每个阶段每周有 1-7 个训练日"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTrainingWeekdays) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "训练星期应为 1（周一）到 7（周日）且不重复"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "创建健身计划失败"))
 		return
 	}
@@ -89,6 +106,22 @@ func (fc *FitnessPlanController) CreateCustom(c *gin.Context) {
 
 	result, err := fc.FitnessPlanUsecase.CreateCustom(c, userID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProgression) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "进阶规则参数无效"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanPhases) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划阶段需从第 1 周起连续覆盖整个计划周期，且每个阶段每周有 1-7 个训练日"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTrainingWeekdays) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "训练星期应为 1（周一）到 7（周日）且不重复"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "创建自定义计划失败"))
 		return
 	}
@@ -240,7 +273,7 @@ func (fc *FitnessPlanController) UpdateStatus(c *gin.Context) {
 
 // CompleteDay godoc
 // @Summary      完成训练日
-// @Description  标记健身计划中的某一天为已完成
+// @Description  标记健身计划中的某一天为已完成；传入 recordId 时将该训练记录关联到此计划日，并重新汇总计划累计数据
 // @Tags         健身计划
 // @Accept       json
 // @Produce      json
@@ -250,6 +283,7 @@ func (fc *FitnessPlanController) UpdateStatus(c *gin.Context) {
 // @Success      200 {object} domain.SuccessResponse{data=domain.FitnessPlan} "完成成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "计划或训练记录不存在"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/plans/{planId}/complete-day [post]
 func (fc *FitnessPlanController) CompleteDay(c *gin.Context) {
@@ -278,9 +312,18 @@ func (fc *FitnessPlanController) CompleteDay(c *gin.Context) {
 		return
 	}
 
-	result, err := fc.FitnessPlanUsecase.CompleteDay(c, userID, planID, request.DayNumber, request.RecordID)
+	result, err := fc.FitnessPlanUsecase.CompleteDay(c, userID, planID, request.PlanDay(), request.RecordID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "完成训练日失败"))
+		switch {
+		case errors.Is(err, domain.ErrFitnessPlanNotFound):
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "健身计划不存在"))
+		case errors.Is(err, domain.ErrTrainingRecordNotFound):
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "训练记录不存在"))
+		case errors.Is(err, domain.ErrInvalidPlanDay):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划日超出计划范围"))
+		default:
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "完成训练日失败"))
+		}
 		return
 	}
 
@@ -327,7 +370,7 @@ func (fc *FitnessPlanController) UncompleteDay(c *gin.Context) {
 		return
 	}
 
-	result, err := fc.FitnessPlanUsecase.UncompleteDay(c, userID, planID, request.DayNumber)
+	result, err := fc.FitnessPlanUsecase.UncompleteDay(c, userID, planID, request.PlanDay())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
 		return
@@ -419,6 +462,98 @@ func (fc *FitnessPlanController) GetProgress(c *gin.Context) {
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(progress))
 }
 
+// GetUpcomingSessions godoc
+// @Summary      获取近期训练安排
+// @Description  将进行中的计划按开始日期和训练星期排到日历上，返回从今天（用户时区）起若干天内的训练，按日期排序
+// @Tags         健身计划
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        days query int false "查看天数，默认 7，最多 60"
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.PlanSession} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/plans/upcoming [get]
+func (fc *FitnessPlanController) GetUpcomingSessions(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(domain.DefaultUpcomingDays)))
+
+	sessions, err := fc.FitnessPlanUsecase.GetUpcomingSessions(c, userID, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取近期训练安排失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(sessions))
+}
+
+// GetWeek godoc
+// @Summary      获取计划某一周的训练安排
+// @Description  返回计划第 week 周的训练安排，带进阶规则的动作按规则和用户在该计划中的成绩替换为当周目标
+// @Tags         健身计划
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        planId path string true "计划ID"
+// @Param        week path int true "计划周，从 1 开始"
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.PlanSession} "获取成功"
+// @Failure      400 {object} domain.ErrorResponse "计划周超出计划周期"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "计划不存在"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/plans/{planId}/weeks/{week} [get]
+func (fc *FitnessPlanController) GetWeek(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	planID := c.Param("planId")
+	if planID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划ID不能为空"))
+		return
+	}
+
+	week, err := strconv.Atoi(c.Param("week"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划周格式错误"))
+		return
+	}
+
+	sessions, err := fc.FitnessPlanUsecase.GetWeek(c, userID, planID, week)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrFitnessPlanNotFound):
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "健身计划不存在"))
+		case errors.Is(err, domain.ErrInvalidPlanWeek):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划周超出计划周期"))
+		default:
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取计划训练安排失败"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(sessions))
+}
+
 // SkipDay godoc
 // @Summary      跳过计划日
 // @Description  跳过健身计划中的某一天
@@ -459,8 +594,12 @@ func (fc *FitnessPlanController) SkipDay(c *gin.Context) {
 		return
 	}
 
-	result, err := fc.FitnessPlanUsecase.SkipDay(c, userID, planID, request.DayNumber, request.Reason)
+	result, err := fc.FitnessPlanUsecase.SkipDay(c, userID, planID, request.PlanDay(), request.Reason)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidPlanDay) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划日超出计划范围"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
 		return
 	}
@@ -510,9 +649,189 @@ func (fc *FitnessPlanController) AdjustDay(c *gin.Context) {
 
 	err = fc.FitnessPlanUsecase.AdjustDay(c, userID, planID, request.DayNumber, request.Exercises, request.Notes)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProgression) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "进阶规则参数无效"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
 		return
 	}
 
 	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "调整成功"))
 }
+
+// ShiftDays godoc
+// @Summary      顺延计划日
+// @Description  将计划中某一天起尚未完成的训练整体顺延若干天，计划结束日期随之延后；不指定起始日时从最近一次完成之后最早错过的训练开始
+// @Tags         健身计划
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        planId path string true "计划ID"
+// @Param        request body domain.ShiftDaysRequest true "顺延信息"
+// @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "已顺延"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "计划不存在"
+// @Failure      409 {object} domain.ErrorResponse "训练日已完成或目标日期已有训练"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/plans/{planId}/shift-days [post]
+func (fc *FitnessPlanController) ShiftDays(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	planID := c.Param("planId")
+	if planID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划ID不能为空"))
+		return
+	}
+
+	var request domain.ShiftDaysRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	result, err := fc.FitnessPlanUsecase.ShiftDays(c, userID, planID, &request)
+	if err != nil {
+		respondRescheduleError(c, err, "顺延计划日失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(result, "已顺延"))
+}
+
+// SwapDays godoc
+// @Summary      交换计划日
+// @Description  交换计划中两个尚未完成的训练日
+// @Tags         健身计划
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        planId path string true "计划ID"
+// @Param        request body domain.SwapDaysRequest true "交换信息"
+// @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "已交换"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "计划不存在"
+// @Failure      409 {object} domain.ErrorResponse "训练日已完成或目标日期已有训练"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/plans/{planId}/swap-days [post]
+func (fc *FitnessPlanController) SwapDays(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	planID := c.Param("planId")
+	if planID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划ID不能为空"))
+		return
+	}
+
+	var request domain.SwapDaysRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	result, err := fc.FitnessPlanUsecase.SwapDays(c, userID, planID, &request)
+	if err != nil {
+		respondRescheduleError(c, err, "交换计划日失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(result, "已交换"))
+}
+
+// MoveDay godoc
+// @Summary      移动计划日
+// @Description  将计划中尚未完成的训练移动到今天及之后没有安排训练的日期
+// @Tags         健身计划
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        planId path string true "计划ID"
+// @Param        request body domain.MoveDayRequest true "移动信息"
+// @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "已移动"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "计划不存在"
+// @Failure      409 {object} domain.ErrorResponse "训练日已完成或目标日期已有训练"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/plans/{planId}/move-day [post]
+func (fc *FitnessPlanController) MoveDay(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	planID := c.Param("planId")
+	if planID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划ID不能为空"))
+		return
+	}
+
+	var request domain.MoveDayRequest
+	err := c.ShouldBindJSON(&request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	result, err := fc.FitnessPlanUsecase.MoveDay(c, userID, planID, &request)
+	if err != nil {
+		respondRescheduleError(c, err, "移动计划日失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(result, "已移动"))
+}
+
+func respondRescheduleError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrFitnessPlanNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "健身计划不存在"))
+	case errors.Is(err, domain.ErrInvalidPlanDay):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划日或日期超出计划范围"))
+	case errors.Is(err, domain.ErrInvalidShiftDays):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "顺延天数应为 1-90 天"))
+	case errors.Is(err, domain.ErrPlanDayNotScheduled):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "该计划日没有安排训练"))
+	case errors.Is(err, domain.ErrPlanDayCompleted):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "已完成的训练日不能调整"))
+	case errors.Is(err, domain.ErrPlanDayOccupied):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "目标日期已安排训练，请使用交换"))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, message))
+	}
+}
@@ -0,0 +1,25 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type JwksController struct {
+	Env *bootstrap.Env
+}
+
+// GetJWKS godoc
+// @Summary      获取令牌校验公钥
+// @Description  以 JWKS 格式发布访问令牌的校验公钥，其它服务可据此按 kid 校验令牌；HS256 模式下返回空列表
+// @Tags         认证
+// @Produce      json
+// @Success      200 {object} domain.JWKSResponse "公钥列表"
+// @Router       /.well-known/jwks.json [get]
+func (jc *JwksController) GetJWKS(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, domain.JWKSResponse{Keys: jc.Env.AccessTokenKeys.PublicKeys()})
+}
@@ -0,0 +1,42 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type LoginAttemptController struct {
+	LoginAttemptUsecase domain.LoginAttemptUsecase
+}
+
+// Unlock godoc
+// @Summary      解除账号锁定（管理员）
+// @Description  清除用户的登录失败记录，立即解除因多次登录失败导致的锁定；按来源IP的锁定不受影响
+// @Tags         管理员-用户
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userId path string true "用户ID"
+// @Success      200 {object} domain.SuccessResponse "解锁成功"
+// @Failure      400 {object} domain.ErrorResponse "用户ID不能为空"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Router       /api/admin/users/{userId}/unlock [post]
+func (lc *LoginAttemptController) Unlock(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "用户ID不能为空"))
+		return
+	}
+
+	err := lc.LoginAttemptUsecase.UnlockUser(c, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "用户不存在"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "解锁成功"))
+}
@@ -1,9 +1,11 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
-
-	"golang.org/x/crypto/bcrypt"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhengshui/flow-link-server/bootstrap"
@@ -11,21 +13,27 @@ import (
 )
 
 type LoginController struct {
-	LoginUsecase domain.LoginUsecase
-	Env          *bootstrap.Env
+	LoginUsecase        domain.LoginUsecase
+	RefreshTokenUsecase domain.RefreshTokenUsecase
+	SessionUsecase      domain.SessionUsecase
+	LoginAttemptUsecase domain.LoginAttemptUsecase
+	Env                 *bootstrap.Env
 }
 
 // Login godoc
 // @Summary      用户登录
-// @Description  用户通过用户名和密码登录，返回JWT token
+// @Description  用户通过用户名、已验证邮箱或手机号加密码登录，返回访问令牌和刷新令牌；连续失败过多会被临时锁定
 // @Tags         认证
 // @Accept       json
 // @Produce      json
 // @Param        request body domain.LoginRequest true "登录信息"
 // @Success      200 {object} domain.SuccessResponse{data=domain.LoginResponse} "登录成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
-// @Failure      401 {object} domain.ErrorResponse "用户名或密码错误"
-// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Failure      401 {object} domain.ApiResponse{data=domain.LoginFailureResponse} "用户名或密码错误"
+// @Failure      403 {object} domain.ErrorResponse "账号已被禁用或邮箱未验证"
+// @Failure      404 {object} domain.ApiResponse{data=domain.LoginFailureResponse} "用户不存在"
+// @Failure      409 {object} domain.ErrorResponse "邮箱或手机号对应的多个账号密码相同"
+// @Failure      429 {object} domain.ApiResponse{data=domain.LoginFailureResponse} "登录失败次数过多，账号已临时锁定"
 // @Router       /api/auth/login [post]
 func (lc *LoginController) Login(c *gin.Context) {
 	var request domain.LoginRequest
@@ -36,27 +44,125 @@ func (lc *LoginController) Login(c *gin.Context) {
 		return
 	}
 
-	user, err := lc.LoginUsecase.GetUserByUsername(c, request.Username)
+	clientIP := c.ClientIP()
+	identifier := request.LoginIdentifier()
+
+	candidates, resolveErr := lc.LoginUsecase.ResolveUser(c, identifier)
+
+	// 失败计数按账号的用户名记录，使用邮箱/手机号登录失败同样计入匹配到的每个账号；
+	// 未匹配到账号时按标识本身记录，邮箱统一小写，便于之后按账号的邮箱找到这些记录
+	unmatchedKey := identifier
+	if strings.Contains(identifier, "@") {
+		unmatchedKey = domain.NormalizeEmail(identifier)
+	}
+	attemptKeys := []string{unmatchedKey}
+	if len(candidates) > 0 {
+		attemptKeys = attemptKeys[:0]
+		for _, candidate := range candidates {
+			attemptKeys = append(attemptKeys, candidate.User.Username)
+		}
+	}
+
+	// 锁定期间不再校验密码
+	lockStatus, err := lc.LoginAttemptUsecase.CheckLocked(c, attemptKeys, clientIP)
 	if err != nil {
-		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "用户名或密码错误"))
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+	if lockStatus.Locked {
+		lc.respondLoginFailure(c, http.StatusTooManyRequests, lockStatus)
 		return
 	}
 
-	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.Password)) != nil {
-		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户名或密码错误"))
+	if resolveErr != nil {
+		lc.recordLoginFailure(c, http.StatusNotFound, attemptKeys, clientIP)
 		return
 	}
 
-	accessToken, err := lc.LoginUsecase.CreateAccessToken(&user, lc.Env.AccessTokenSecret, lc.Env.AccessTokenExpiryHour)
+	candidate, err := lc.LoginUsecase.Authenticate(candidates, request.Password)
+	switch {
+	case errors.Is(err, domain.ErrPasswordIncorrect):
+		lc.recordLoginFailure(c, http.StatusUnauthorized, attemptKeys, clientIP)
+		return
+	case errors.Is(err, domain.ErrLoginIdentifierAmbiguous):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "该邮箱或手机号对应多个账号，请使用用户名登录"))
+		return
+	case errors.Is(err, domain.ErrLoginIdentifierUnverified):
+		c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "邮箱未验证，请使用用户名或手机号登录"))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+	user := candidate.User
+
+	_ = lc.LoginAttemptUsecase.RecordSuccess(c, user.Username)
+
+	if user.Disabled {
+		c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "账号已被禁用"))
+		return
+	}
+
+	// 每次登录创建一个设备会话
+	session, err := lc.SessionUsecase.Create(c, user.ID, c.Request.UserAgent(), c.ClientIP(), lc.Env.RefreshTokenExpiryHour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+
+	accessToken, err := lc.LoginUsecase.CreateAccessToken(c, &user, session.ID.Hex(), lc.Env.AccessTokenKeys, lc.Env.AccessTokenExpiryHour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+
+	refreshToken, err := lc.RefreshTokenUsecase.IssueRefreshToken(c, &user, session.ID.Hex(), lc.Env.RefreshTokenKeys, lc.Env.RefreshTokenExpiryHour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
 		return
 	}
 
 	loginResponse := domain.LoginResponse{
-		Token: accessToken,
-		Role:  user.Role,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		Role:         user.Role,
 	}
 
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(loginResponse))
 }
+
+// recordLoginFailure 记录失败登录并返回剩余次数或锁定信息
+func (lc *LoginController) recordLoginFailure(c *gin.Context, status int, usernames []string, ip string) {
+	lockStatus, err := lc.LoginAttemptUsecase.RecordFailure(c, usernames, ip)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+	lc.respondLoginFailure(c, status, lockStatus)
+}
+
+// respondLoginFailure 登录失败响应，锁定时统一返回 429
+func (lc *LoginController) respondLoginFailure(c *gin.Context, status int, lockStatus domain.LoginLockStatus) {
+	if lockStatus.Locked {
+		retryAfter := int(time.Until(lockStatus.LockedUntil).Seconds()) + 1
+		c.Header("Retry-After", strconv.Itoa(retryAfter))
+		c.JSON(http.StatusTooManyRequests, domain.ApiResponse{
+			Code:    429,
+			Message: "登录失败次数过多，请稍后再试",
+			Data: domain.LoginFailureResponse{
+				RemainingAttempts: 0,
+				LockedUntil:       lockStatus.LockedUntil.Format("2006-01-02 15:04:05"),
+				RetryAfterSeconds: retryAfter,
+			},
+		})
+		return
+	}
+
+	c.JSON(status, domain.ApiResponse{
+		Code:    status,
+		Message: "用户名或密码错误",
+		Data: domain.LoginFailureResponse{
+			RemainingAttempts: lockStatus.RemainingAttempts,
+		},
+	})
+}
@@ -0,0 +1,119 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type PasswordController struct {
+	PasswordUsecase domain.PasswordUsecase
+	Env             *bootstrap.Env
+}
+
+// ChangePassword godoc
+// @Summary      修改密码
+// @Description  校验原密码后设置新密码
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        request body domain.ChangePasswordRequest true "密码信息"
+// @Success      200 {object} domain.SuccessResponse "修改成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "原密码错误"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/password [put]
+func (pc *PasswordController) ChangePassword(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	var request domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	err := pc.PasswordUsecase.ChangePassword(c, userID, &request)
+	if err != nil {
+		if errors.Is(err, domain.ErrPasswordIncorrect) {
+			c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "原密码错误"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "修改密码失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "修改成功"))
+}
+
+// ForgotPassword godoc
+// @Summary      申请重置密码
+// @Description  向账号绑定的邮箱发送一次性密码重置令牌，账号是否存在均返回成功
+// @Tags         认证
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.ForgotPasswordRequest true "账号信息"
+// @Success      200 {object} domain.SuccessResponse "已发送"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/auth/password/forgot [post]
+func (pc *PasswordController) ForgotPassword(c *gin.Context) {
+	var request domain.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	err := pc.PasswordUsecase.RequestPasswordReset(c, request.Account, pc.Env.PasswordResetExpiryMinute)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "发送重置令牌失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "如果账号存在，重置令牌已发送"))
+}
+
+// ResetPassword godoc
+// @Summary      重置密码
+// @Description  使用重置令牌设置新密码，成功后所有设备需要重新登录
+// @Tags         认证
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.ResetPasswordRequest true "重置信息"
+// @Success      200 {object} domain.SuccessResponse "重置成功"
+// @Failure      400 {object} domain.ErrorResponse "重置令牌无效或已过期"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/auth/password/reset [post]
+func (pc *PasswordController) ResetPassword(c *gin.Context) {
+	var request domain.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	err := pc.PasswordUsecase.ResetPassword(c, &request)
+	if err != nil {
+		if errors.Is(err, domain.ErrPasswordResetTokenInvalid) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "重置令牌无效或已过期"))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "重置密码失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "重置成功"))
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -96,7 +97,7 @@ func (pc *PlanTemplateController) GetList(c *gin.Context) {
 // @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "创建成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
-// @Failure      403 {object} domain.ErrorResponse "需要管理员权限"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/admin/templates [post]
 func (pc *PlanTemplateController) CreateOfficial(c *gin.Context) {
@@ -109,6 +110,18 @@ func (pc *PlanTemplateController) CreateOfficial(c *gin.Context) {
 
 	result, err := pc.PlanTemplateUsecase.CreateOfficial(c, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProgression) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "进阶规则参数无效"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanPhases) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划阶段需从第 1 周起连续覆盖整个计划周期，且每个阶段每周有 1-7 个训练日"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "创建官方模板失败"))
 		return
 	}
@@ -151,6 +164,18 @@ func (pc *PlanTemplateController) CreateCustom(c *gin.Context) {
 
 	result, err := pc.PlanTemplateUsecase.CreateCustom(c, userID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProgression) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "进阶规则参数无效"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanPhases) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划阶段需从第 1 周起连续覆盖整个计划周期，且每个阶段每周有 1-7 个训练日"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "创建个人模板失败"))
 		return
 	}
@@ -244,6 +269,18 @@ func (pc *PlanTemplateController) Update(c *gin.Context) {
 
 	err = pc.PlanTemplateUsecase.Update(c, userID, templateID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidProgression) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "进阶规则参数无效"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanPhases) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划阶段需从第 1 周起连续覆盖整个计划周期，且每个阶段每周有 1-7 个训练日"))
+			return
+		}
 		if err.Error() == "unauthorized: you can only update your own templates" {
 			c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "无权限修改该模板"))
 			return
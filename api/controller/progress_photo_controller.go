@@ -0,0 +1,287 @@
+package controller
+
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type ProgressPhotoController struct {
+	ProgressPhotoUsecase domain.ProgressPhotoUsecase
+	MaxUploadSize        int64
+}
+
+// Upload godoc
+// @Summary      上传进度照片
+// @Description  上传 JPEG 或 PNG 照片，服务端按 EXIF 方向摆正、去除拍摄位置等元数据并生成缩略图；照片仅本人可见
+// @Tags         进度照片
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file formData file true "照片(JPEG/PNG)"
+// @Param        date formData string false "拍摄日期 YYYY-MM-DD，默认今天"
+// @Param        notes formData string false "备注"
+// @Success      200 {object} domain.SuccessResponse{data=domain.ProgressPhoto} "上传成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      413 {object} domain.ErrorResponse "图片超过大小限制"
+// @Failure      415 {object} domain.ErrorResponse "不支持的图片格式"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/progress-photos [post]
+func (pc *ProgressPhotoController) Upload(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	file, err := openUploadedFile(c, pc.MaxUploadSize)
+	if err != nil {
+		respondProgressPhotoError(c, err, "上传进度照片失败")
+		return
+	}
+	defer file.Close()
+
+	var notes *string
+	if value, ok := c.GetPostForm("notes"); ok && value != "" {
+		notes = &value
+	}
+
+	photo, err := pc.ProgressPhotoUsecase.Upload(c, userID, c.PostForm("date"), notes, file)
+	if err != nil {
+		respondProgressPhotoError(c, err, "上传进度照片失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(photo))
+}
+
+// GetList godoc
+// @Summary      获取进度照片列表
+// @Description  按拍摄日期倒序返回照片信息，可按日期范围筛选；照片文件通过 url/thumbnailUrl 下载（需要认证）
+// @Tags         进度照片
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        startDate query string false "开始日期" format(date)
+// @Param        endDate query string false "结束日期" format(date)
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.ProgressPhoto} "获取成功"
+// @Failure      400 {object} domain.ErrorResponse "日期格式错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/progress-photos [get]
+func (pc *ProgressPhotoController) GetList(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	photos, err := pc.ProgressPhotoUsecase.GetList(c, userID, c.Query("startDate"), c.Query("endDate"))
+	if err != nil {
+		respondProgressPhotoError(c, err, "获取进度照片失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(photos))
+}
+
+// GetByID godoc
+// @Summary      获取进度照片信息
+// @Tags         进度照片
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        photoId path string true "照片ID"
+// @Success      200 {object} domain.SuccessResponse{data=domain.ProgressPhoto} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "照片不存在"
+// @Router       /api/progress-photos/{photoId} [get]
+func (pc *ProgressPhotoController) GetByID(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	photo, err := pc.ProgressPhotoUsecase.GetByID(c, userID, c.Param("photoId"))
+	if err != nil {
+		respondProgressPhotoError(c, err, "获取进度照片失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(photo))
+}
+
+// GetFile godoc
+// @Summary      下载进度照片
+// @Tags         进度照片
+// @Produce      image/jpeg
+// @Produce      image/png
+// @Security     BearerAuth
+// @Param        photoId path string true "照片ID"
+// @Success      200 {file} binary "照片"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "照片不存在"
+// @Router       /api/progress-photos/{photoId}/file [get]
+func (pc *ProgressPhotoController) GetFile(c *gin.Context) {
+	pc.serve(c, false)
+}
+
+// GetThumbnail godoc
+// @Summary      下载进度照片缩略图
+// @Description  长边不超过 320px 的 JPEG
+// @Tags         进度照片
+// @Produce      image/jpeg
+// @Security     BearerAuth
+// @Param        photoId path string true "照片ID"
+// @Success      200 {file} binary "缩略图"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "照片不存在"
+// @Router       /api/progress-photos/{photoId}/thumbnail [get]
+func (pc *ProgressPhotoController) GetThumbnail(c *gin.Context) {
+	pc.serve(c, true)
+}
+
+func (pc *ProgressPhotoController) serve(c *gin.Context, thumbnail bool) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	body, info, err := pc.ProgressPhotoUsecase.Open(c, userID, c.Param("photoId"), thumbnail)
+	if err != nil {
+		respondProgressPhotoError(c, err, "下载进度照片失败")
+		return
+	}
+
+	// 照片仅本人可见，不允许共享缓存保存
+	serveBlob(c, body, info, "private, max-age=86400")
+}
+
+// Delete godoc
+// @Summary      删除进度照片
+// @Description  同时删除照片文件和缩略图
+// @Tags         进度照片
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        photoId path string true "照片ID"
+// @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "删除成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "照片不存在"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/progress-photos/{photoId} [delete]
+func (pc *ProgressPhotoController) Delete(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	if err := pc.ProgressPhotoUsecase.Delete(c, userID, c.Param("photoId")); err != nil {
+		respondProgressPhotoError(c, err, "删除进度照片失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(map[string]interface{}{
+		"message": "进度照片删除成功",
+	}))
+}
+
+func respondProgressPhotoError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, domain.ErrProgressPhotoNotFound), errors.Is(err, domain.ErrBlobNotFound):
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "进度照片不存在"))
+	case errors.Is(err, domain.ErrInvalidProgressPhoto):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "日期格式应为 YYYY-MM-DD"))
+	case !respondUploadError(c, err):
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, message))
+	}
+}
+
+// openUploadedFile 打开 multipart 表单中的 file 字段，请求体超过上限时返回 ErrImageTooLarge
+func openUploadedFile(c *gin.Context, maxSize int64) (multipart.File, error) {
+	// 为表单中其他字段和分隔符多留 1MB
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize+1<<20)
+
+	header, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			return nil, domain.ErrImageTooLarge
+		}
+		return nil, errMissingUploadFile
+	}
+	if header.Size > maxSize {
+		return nil, domain.ErrImageTooLarge
+	}
+	return header.Open()
+}
+
+// errMissingUploadFile 请求不是 multipart 表单或缺少 file 字段
+var errMissingUploadFile = errors.New("missing upload file")
+
+// respondUploadError 处理上传图片的通用错误，已处理时返回 true
+func respondUploadError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, errMissingUploadFile):
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "请通过 multipart/form-data 的 file 字段上传图片"))
+	case errors.Is(err, domain.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, domain.NewErrorResponse(413, "图片超过大小限制"))
+	case errors.Is(err, domain.ErrUnsupportedImage):
+		c.JSON(http.StatusUnsupportedMediaType, domain.NewErrorResponse(415, "仅支持 JPEG 或 PNG 图片"))
+	default:
+		return false
+	}
+	return true
+}
+
+// serveBlob 输出存储中的文件并关闭 body
+func serveBlob(c *gin.Context, body io.ReadCloser, info domain.BlobInfo, cacheControl string) {
+	defer body.Close()
+
+	size := info.Size
+	if size <= 0 {
+		size = -1
+	}
+	c.DataFromReader(http.StatusOK, size, info.ContentType, body, map[string]string{
+		"Cache-Control":          cacheControl,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
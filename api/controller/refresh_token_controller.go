@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -15,7 +16,7 @@ type RefreshTokenController struct {
 
 // RefreshToken godoc
 // @Summary      刷新访问令牌
-// @Description  使用刷新令牌获取新的访问令牌和刷新令牌
+// @Description  使用刷新令牌获取新的访问令牌和刷新令牌，旧刷新令牌随即失效；重复使用已轮换的令牌会吊销该登录的所有令牌
 // @Tags         认证
 // @Accept       json
 // @Produce      json
@@ -23,6 +24,7 @@ type RefreshTokenController struct {
 // @Success      200 {object} domain.RefreshTokenResponse "刷新成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
 // @Failure      401 {object} domain.ErrorResponse "令牌无效或已过期"
+// @Failure      403 {object} domain.ErrorResponse "账号已被禁用"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/auth/refresh [post]
 func (rtc *RefreshTokenController) RefreshToken(c *gin.Context) {
@@ -34,34 +36,59 @@ func (rtc *RefreshTokenController) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	id, err := rtc.RefreshTokenUsecase.ExtractIDFromToken(request.RefreshToken, rtc.Env.RefreshTokenSecret)
+	rotated, err := rtc.RefreshTokenUsecase.RotateRefreshToken(c, request.RefreshToken, rtc.Env.RefreshTokenKeys, rtc.Env.RefreshTokenExpiryHour)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "User not found"})
+		if errors.Is(err, domain.ErrRefreshTokenReused) {
+			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Refresh token reuse detected, please login again"})
+			return
+		}
+		if errors.Is(err, domain.ErrUserDisabled) {
+			c.JSON(http.StatusForbidden, domain.ErrorResponse{Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Invalid refresh token"})
 		return
 	}
 
-	user, err := rtc.RefreshTokenUsecase.GetUserByID(c, id)
+	accessToken, err := rtc.RefreshTokenUsecase.CreateAccessToken(c, &rotated.User, rotated.SessionID, rtc.Env.AccessTokenKeys, rtc.Env.AccessTokenExpiryHour)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "User not found"})
+		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
 		return
 	}
 
-	accessToken, err := rtc.RefreshTokenUsecase.CreateAccessToken(&user, rtc.Env.AccessTokenSecret, rtc.Env.AccessTokenExpiryHour)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
-		return
+	refreshTokenResponse := domain.RefreshTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: rotated.RefreshToken,
 	}
 
-	refreshToken, err := rtc.RefreshTokenUsecase.CreateRefreshToken(&user, rtc.Env.RefreshTokenSecret, rtc.Env.RefreshTokenExpiryHour)
+	c.JSON(http.StatusOK, refreshTokenResponse)
+}
+
+// Logout godoc
+// @Summary      退出登录
+// @Description  吊销刷新令牌及其轮换出的全部令牌，并结束对应的登录会话
+// @Tags         认证
+// @Accept       json
+// @Produce      json
+// @Param        request body domain.RefreshTokenRequest true "刷新令牌"
+// @Success      200 {object} domain.SuccessResponse "退出成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "令牌无效或已过期"
+// @Router       /api/auth/logout [post]
+func (rtc *RefreshTokenController) Logout(c *gin.Context) {
+	var request domain.RefreshTokenRequest
+
+	err := c.ShouldBind(&request)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, domain.ErrorResponse{Message: err.Error()})
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
 		return
 	}
 
-	refreshTokenResponse := domain.RefreshTokenResponse{
-		AccessToken:  accessToken,
-		RefreshToken: refreshToken,
+	err = rtc.RefreshTokenUsecase.RevokeRefreshToken(c, request.RefreshToken, rtc.Env.RefreshTokenKeys)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "令牌无效或已过期"))
+		return
 	}
 
-	c.JSON(http.StatusOK, refreshTokenResponse)
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "退出成功"))
 }
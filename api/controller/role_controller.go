@@ -0,0 +1,120 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type RoleController struct {
+	RoleUsecase domain.RoleUsecase
+}
+
+// FetchRoles godoc
+// @Summary      获取角色列表（管理员）
+// @Description  获取所有角色及其权限
+// @Tags         管理员-角色
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.Role} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/admin/roles [get]
+func (rc *RoleController) FetchRoles(c *gin.Context) {
+	roles, err := rc.RoleUsecase.Fetch(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取角色列表失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(roles))
+}
+
+// UpsertRole godoc
+// @Summary      创建或更新角色（管理员）
+// @Description  设置角色的描述和权限，角色不存在时创建；admin 角色不可修改。已签发的令牌在刷新后获得新权限
+// @Tags         管理员-角色
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        name path string true "角色名称"
+// @Param        request body domain.UpsertRoleRequest true "角色信息"
+// @Success      200 {object} domain.SuccessResponse{data=domain.Role} "保存成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/admin/roles/{name} [put]
+func (rc *RoleController) UpsertRole(c *gin.Context) {
+	name := c.Param("name")
+	if name == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "角色名称不能为空"))
+		return
+	}
+
+	var request domain.UpsertRoleRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	role, err := rc.RoleUsecase.Upsert(c, name, &request)
+	if err != nil {
+		switch {
+		case errors.Is(err, domain.ErrRoleProtected):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "管理员角色不可修改"))
+		case errors.Is(err, domain.ErrPermissionUnknown):
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "包含未知的权限"))
+		default:
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "保存角色失败"))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(role, "保存成功"))
+}
+
+// AssignRoles godoc
+// @Summary      分配用户角色（管理员）
+// @Description  替换用户的附加角色列表，用户需重新登录以获得新权限
+// @Tags         管理员-角色
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        userId path string true "用户ID"
+// @Param        request body domain.AssignRolesRequest true "角色列表"
+// @Success      200 {object} domain.SuccessResponse "分配成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      403 {object} domain.ErrorResponse "权限不足"
+// @Failure      404 {object} domain.ErrorResponse "用户不存在"
+// @Router       /api/admin/users/{userId}/roles [put]
+func (rc *RoleController) AssignRoles(c *gin.Context) {
+	userID := c.Param("userId")
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "用户ID不能为空"))
+		return
+	}
+
+	var request domain.AssignRolesRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, err.Error()))
+		return
+	}
+
+	err := rc.RoleUsecase.AssignRoles(c, userID, request.Roles)
+	if err != nil {
+		if errors.Is(err, domain.ErrRoleNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "角色不存在"))
+			return
+		}
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "用户不存在"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "分配成功"))
+}
@@ -0,0 +1,99 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type SessionController struct {
+	SessionUsecase domain.SessionUsecase
+}
+
+// GetSessions godoc
+// @Summary      获取登录设备列表
+// @Description  获取当前用户所有有效的登录会话
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SuccessResponse{data=[]domain.SessionResponse} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/sessions [get]
+func (sc *SessionController) GetSessions(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	sessions, err := sc.SessionUsecase.GetActiveSessions(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取登录设备失败"))
+		return
+	}
+
+	currentSessionID := c.GetString("x-session-id")
+	result := []domain.SessionResponse{}
+	for _, session := range sessions {
+		result = append(result, domain.SessionResponse{
+			ID:         session.ID.Hex(),
+			UserAgent:  session.UserAgent,
+			IP:         session.IP,
+			CreatedAt:  session.CreatedAt.Time().Format("2006-01-02 15:04:05"),
+			LastUsedAt: session.LastUsedAt.Time().Format("2006-01-02 15:04:05"),
+			Current:    session.ID.Hex() == currentSessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(result))
+}
+
+// RevokeSession godoc
+// @Summary      下线登录设备
+// @Description  吊销指定会话，该设备的访问令牌和刷新令牌立即失效
+// @Tags         用户信息
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        sessionId path string true "会话ID"
+// @Success      200 {object} domain.SuccessResponse "下线成功"
+// @Failure      400 {object} domain.ErrorResponse "会话ID不能为空"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "会话不存在"
+// @Router       /api/user/sessions/{sessionId} [delete]
+func (sc *SessionController) RevokeSession(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	sessionID := c.Param("sessionId")
+	if sessionID == "" {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "会话ID不能为空"))
+		return
+	}
+
+	err := sc.SessionUsecase.Revoke(c, userID, sessionID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "会话不存在"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponseWithMessage(nil, "下线成功"))
+}
@@ -1,7 +1,9 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,8 +14,10 @@ import (
 )
 
 type SignupController struct {
-	SignupUsecase domain.SignupUsecase
-	Env           *bootstrap.Env
+	SignupUsecase       domain.SignupUsecase
+	RefreshTokenUsecase domain.RefreshTokenUsecase
+	SessionUsecase      domain.SessionUsecase
+	Env                 *bootstrap.Env
 }
 
 // Signup godoc
@@ -25,7 +29,7 @@ type SignupController struct {
 // @Param        request body domain.SignupRequest true "注册信息"
 // @Success      200 {object} domain.SuccessResponse{data=domain.SignupResponse} "注册成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
-// @Failure      409 {object} domain.ErrorResponse "用户名已存在"
+// @Failure      409 {object} domain.ErrorResponse "用户名、邮箱或手机号已存在"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/auth/register [post]
 func (sc *SignupController) Signup(c *gin.Context) {
@@ -37,6 +41,11 @@ func (sc *SignupController) Signup(c *gin.Context) {
 		return
 	}
 
+	if err := domain.ValidateUsername(request.Username); err != nil {
+		c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "用户名不能是邮箱或手机号格式"))
+		return
+	}
+
 	// 检查用户名是否已存在
 	_, err = sc.SignupUsecase.GetUserByUsername(c, request.Username)
 	if err == nil {
@@ -44,6 +53,15 @@ func (sc *SignupController) Signup(c *gin.Context) {
 		return
 	}
 
+	// 检查邮箱和手机号是否已被注册
+	request.Email = domain.NormalizeEmail(request.Email)
+	request.Phone = strings.TrimSpace(request.Phone)
+	err = sc.SignupUsecase.CheckContactAvailable(c, request.Email, request.Phone)
+	if err != nil {
+		respondContactConflict(c, err)
+		return
+	}
+
 	// 加密密码
 	encryptedPassword, err := bcrypt.GenerateFromPassword(
 		[]byte(request.Password),
@@ -76,22 +94,54 @@ func (sc *SignupController) Signup(c *gin.Context) {
 	}
 
 	err = sc.SignupUsecase.Create(c, &user)
+	if err != nil {
+		if errors.Is(err, domain.ErrUserConflict) {
+			respondContactConflict(c, err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+
+	// 注册即登录，创建设备会话
+	session, err := sc.SessionUsecase.Create(c, user.ID, c.Request.UserAgent(), c.ClientIP(), sc.Env.RefreshTokenExpiryHour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
 		return
 	}
 
 	// 生成token
-	accessToken, err := sc.SignupUsecase.CreateAccessToken(&user, sc.Env.AccessTokenSecret, sc.Env.AccessTokenExpiryHour)
+	accessToken, err := sc.SignupUsecase.CreateAccessToken(c, &user, session.ID.Hex(), sc.Env.AccessTokenKeys, sc.Env.AccessTokenExpiryHour)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+		return
+	}
+
+	refreshToken, err := sc.RefreshTokenUsecase.IssueRefreshToken(c, &user, session.ID.Hex(), sc.Env.RefreshTokenKeys, sc.Env.RefreshTokenExpiryHour)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
 		return
 	}
 
 	signupResponse := domain.SignupResponse{
-		Token: accessToken,
-		Role:  user.Role,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		Role:         user.Role,
 	}
 
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(signupResponse))
 }
+
+// respondContactConflict 邮箱/手机号冲突统一返回 409
+func respondContactConflict(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrEmailTaken):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "邮箱已被注册"))
+	case errors.Is(err, domain.ErrPhoneTaken):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "手机号已被注册"))
+	case errors.Is(err, domain.ErrUserConflict):
+		c.JSON(http.StatusConflict, domain.NewErrorResponse(409, "用户名、邮箱或手机号已存在"))
+	default:
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, err.Error()))
+	}
+}
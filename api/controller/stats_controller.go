@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -92,12 +93,14 @@ func (sc *StatsController) GetMuscleGroupStats(c *gin.Context) {
 
 // GetPersonalRecords godoc
 // @Summary      获取个人记录
-// @Description  获取用户的个人最佳记录
+// @Description  按已完成的非热身组统计每个动作的最大重量、估算 1RM、1/3/5/10RM 和单次训练量最佳，每项记录关联训练记录ID和组下标
 // @Tags         统计
 // @Accept       json
 // @Produce      json
 // @Security     BearerAuth
+// @Param        formula query string false "1RM 估算公式(epley/brzycki)，默认使用服务端配置"
 // @Success      200 {object} domain.SuccessResponse{data=[]domain.PersonalRecord} "获取成功"
+// @Failure      400 {object} domain.ErrorResponse "不支持的估算公式"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/stats/personal-records [get]
@@ -114,8 +117,12 @@ func (sc *StatsController) GetPersonalRecords(c *gin.Context) {
 		return
 	}
 
-	records, err := sc.StatsUsecase.GetPersonalRecords(c, userID)
+	records, err := sc.StatsUsecase.GetPersonalRecords(c, userID, c.Query("formula"))
 	if err != nil {
+		if errors.Is(err, domain.ErrOneRMFormulaUnknown) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "不支持的估算公式"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取个人记录失败"))
 		return
 	}
@@ -123,6 +130,59 @@ func (sc *StatsController) GetPersonalRecords(c *gin.Context) {
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(records))
 }
 
+// GetPersonalRecordHistory godoc
+// @Summary      获取个人记录时间线
+// @Description  按时间倒序返回每次打破个人记录的历史，可按动作筛选
+// @Tags         统计
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Param        exercise query string false "动作库ID或动作名称"
+// @Param        page query int false "页码" default(1)
+// @Param        pageSize query int false "每页数量" default(20)
+// @Success      200 {object} domain.SuccessResponse{data=domain.PaginatedData} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/stats/personal-records/history [get]
+func (sc *StatsController) GetPersonalRecordHistory(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	// 解析分页参数
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(c.DefaultQuery("pageSize", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 || pageSize > 100 {
+		pageSize = 20
+	}
+
+	events, total, err := sc.StatsUsecase.GetPersonalRecordHistory(c, userID, c.Query("exercise"), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取个人记录历史失败"))
+		return
+	}
+
+	paginatedData := domain.PaginatedData{
+		Total:    total,
+		Page:     page,
+		PageSize: pageSize,
+		Records:  events,
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(paginatedData))
+}
+
 // GetCalendar godoc
 // @Summary      获取日历数据
 // @Description  获取用户指定月份的训练日历数据
@@ -252,3 +312,36 @@ func (sc *StatsController) GetPlanProgressList(c *gin.Context) {
 
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(paginatedData))
 }
+
+// GetWeightProgress godoc
+// @Summary      获取目标体重进度
+// @Description  根据身体数据中的体重记录计算距目标体重的进度、每周变化速度和预计达成日期
+// @Tags         统计
+// @Accept       json
+// @Produce      json
+// @Security     BearerAuth
+// @Success      200 {object} domain.SuccessResponse{data=domain.WeightProgress} "获取成功"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/stats/weight-progress [get]
+func (sc *StatsController) GetWeightProgress(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	progress, err := sc.StatsUsecase.GetWeightProgress(c, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取体重进度失败"))
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(progress))
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"strconv"
@@ -16,7 +17,7 @@ type TrainingRecordController struct {
 
 // Create godoc
 // @Summary      创建训练记录
-// @Description  创建新的训练记录
+// @Description  创建新的训练记录，响应中的 newPersonalRecords 为本次打破的个人记录；带 planId 和 planDayId 时该计划日标记为完成
 // @Tags         训练记录
 // @Accept       json
 // @Produce      json
@@ -53,6 +54,22 @@ func (tc *TrainingRecordController) Create(c *gin.Context) {
 
 	result, err := tc.TrainingRecordUsecase.Create(c, userID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTrainingTime) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "训练时间格式错误，应为 RFC3339 或 YYYY-MM-DD HH:mm:ss"))
+			return
+		}
+		if errors.Is(err, domain.ErrFitnessPlanNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "关联的健身计划不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanDay) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划日超出计划范围"))
+			return
+		}
 		log.Printf("[Create] 返回错误 - error: %v", err)
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "创建训练记录失败"))
 		return
@@ -119,6 +136,7 @@ func (tc *TrainingRecordController) GetByID(c *gin.Context) {
 // @Param        endDate query string false "结束日期" format(date)
 // @Param        planId query string false "计划ID"
 // @Success      200 {object} domain.SuccessResponse{data=domain.PaginatedData} "获取成功"
+// @Failure      400 {object} domain.ErrorResponse "日期格式错误"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/training/records [get]
@@ -148,6 +166,10 @@ func (tc *TrainingRecordController) GetList(c *gin.Context) {
 
 	records, total, err := tc.TrainingRecordUsecase.GetList(c, userID, page, pageSize, startDate, endDate, planID)
 	if err != nil {
+		if errors.Is(err, domain.ErrInvalidTrainingTime) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "日期格式错误，应为 YYYY-MM-DD"))
+			return
+		}
 		log.Printf("[GetList] 返回错误 - error: %v", err)
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "获取训练记录列表失败"))
 		return
@@ -169,7 +191,7 @@ func (tc *TrainingRecordController) GetList(c *gin.Context) {
 
 // Update godoc
 // @Summary      更新训练记录
-// @Description  更新指定ID的训练记录
+// @Description  更新指定ID的训练记录，响应中的 newPersonalRecords 为本次打破的个人记录；修改关联的计划日时同步新旧计划日的完成状态
 // @Tags         训练记录
 // @Accept       json
 // @Produce      json
@@ -179,6 +201,7 @@ func (tc *TrainingRecordController) GetList(c *gin.Context) {
 // @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "更新成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "训练记录不存在"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/training/records/{recordId} [put]
 func (tc *TrainingRecordController) Update(c *gin.Context) {
@@ -211,8 +234,28 @@ func (tc *TrainingRecordController) Update(c *gin.Context) {
 	requestJSON, _ := json.Marshal(request)
 	log.Printf("[Update] 入参 - userID: %s, recordID: %s, request: %s", userID, recordID, string(requestJSON))
 
-	err = tc.TrainingRecordUsecase.Update(c, userID, recordID, &request)
+	newPersonalRecords, err := tc.TrainingRecordUsecase.Update(c, userID, recordID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrExerciseNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "引用的动作不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTrainingTime) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "训练时间格式错误，应为 RFC3339 或 YYYY-MM-DD HH:mm:ss"))
+			return
+		}
+		if errors.Is(err, domain.ErrFitnessPlanNotFound) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "关联的健身计划不存在"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidPlanDay) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "计划日超出计划范围"))
+			return
+		}
+		if errors.Is(err, domain.ErrTrainingRecordNotFound) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "训练记录不存在"))
+			return
+		}
 		log.Printf("[Update] 返回错误 - error: %v", err)
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "更新训练记录失败"))
 		return
@@ -220,7 +263,8 @@ func (tc *TrainingRecordController) Update(c *gin.Context) {
 
 	// 打印返回值日志
 	response := map[string]interface{}{
-		"message": "训练记录更新成功",
+		"message":            "训练记录更新成功",
+		"newPersonalRecords": newPersonalRecords,
 	}
 	responseJSON, _ := json.Marshal(response)
 	log.Printf("[Update] 返回成功 - response: %s", string(responseJSON))
@@ -230,7 +274,7 @@ func (tc *TrainingRecordController) Update(c *gin.Context) {
 
 // Delete godoc
 // @Summary      删除训练记录
-// @Description  删除指定ID的训练记录
+// @Description  删除指定ID的训练记录，计划日没有其他关联记录时恢复为未完成
 // @Tags         训练记录
 // @Accept       json
 // @Produce      json
@@ -239,6 +283,7 @@ func (tc *TrainingRecordController) Update(c *gin.Context) {
 // @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "删除成功"
 // @Failure      400 {object} domain.ErrorResponse "记录ID不能为空"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      404 {object} domain.ErrorResponse "训练记录不存在"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/training/records/{recordId} [delete]
 func (tc *TrainingRecordController) Delete(c *gin.Context) {
@@ -262,6 +307,10 @@ func (tc *TrainingRecordController) Delete(c *gin.Context) {
 
 	err := tc.TrainingRecordUsecase.Delete(c, userID, recordID)
 	if err != nil {
+		if errors.Is(err, domain.ErrTrainingRecordNotFound) {
+			c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "训练记录不存在"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "删除训练记录失败"))
 		return
 	}
@@ -1,14 +1,17 @@
 package controller
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
 )
 
 type UserInfoController struct {
 	UserInfoUsecase domain.UserInfoUsecase
+	MaxUploadSize   int64
 }
 
 // GetUserInfo godoc
@@ -43,19 +46,28 @@ func (uc *UserInfoController) GetUserInfo(c *gin.Context) {
 
 	// 构建响应数据
 	userInfo := domain.UserInfoResponse{
-		ID:           0, // MongoDB ObjectID, frontend uses string
-		Username:     user.Username,
-		Nickname:     user.Nickname,
-		AvatarUrl:    user.AvatarUrl,
-		Email:        user.Email,
-		Phone:        user.Phone,
-		Gender:       user.Gender,
-		Age:          user.Age,
-		Height:       user.Height,
-		Weight:       user.Weight,
-		TargetWeight: user.TargetWeight,
-		FitnessGoal:  user.FitnessGoal,
-		JoinDate:     user.JoinDate,
+		ID:               0, // MongoDB ObjectID, frontend uses string
+		Username:         user.Username,
+		Nickname:         user.Nickname,
+		AvatarUrl:        user.AvatarUrl,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		Phone:            user.Phone,
+		Gender:           user.Gender,
+		Age:              user.Age,
+		Height:           user.Height,
+		Weight:           user.Weight,
+		TargetWeight:     user.TargetWeight,
+		FitnessGoal:      user.FitnessGoal,
+		TimeZone:         timeutil.Location(user.TimeZone).String(),
+		TrainingWeekdays: user.TrainingWeekdays,
+		JoinDate:         user.JoinDate,
+	}
+	if userInfo.TrainingWeekdays == nil {
+		userInfo.TrainingWeekdays = []int{}
+	}
+	if user.DeletionScheduledAt != nil {
+		userInfo.DeletionScheduledAt = user.DeletionScheduledAt.Time().Format("2006-01-02 15:04:05")
 	}
 
 	c.JSON(http.StatusOK, domain.NewSuccessResponse(userInfo))
@@ -72,6 +84,7 @@ func (uc *UserInfoController) GetUserInfo(c *gin.Context) {
 // @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "更新成功"
 // @Failure      400 {object} domain.ErrorResponse "请求参数错误"
 // @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      409 {object} domain.ErrorResponse "邮箱或手机号已被注册"
 // @Failure      500 {object} domain.ErrorResponse "服务器错误"
 // @Router       /api/user/info [put]
 func (uc *UserInfoController) UpdateUserInfo(c *gin.Context) {
@@ -96,6 +109,18 @@ func (uc *UserInfoController) UpdateUserInfo(c *gin.Context) {
 
 	err = uc.UserInfoUsecase.UpdateUserInfo(c, userID, &request)
 	if err != nil {
+		if errors.Is(err, domain.ErrEmailTaken) || errors.Is(err, domain.ErrPhoneTaken) || errors.Is(err, domain.ErrUserConflict) {
+			respondContactConflict(c, err)
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTimeZone) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "时区无效，请使用 IANA 时区名称，如 Asia/Shanghai"))
+			return
+		}
+		if errors.Is(err, domain.ErrInvalidTrainingWeekdays) {
+			c.JSON(http.StatusBadRequest, domain.NewErrorResponse(400, "训练星期应为 1（周一）到 7（周日）且不重复"))
+			return
+		}
 		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "更新用户信息失败"))
 		return
 	}
@@ -104,3 +129,74 @@ func (uc *UserInfoController) UpdateUserInfo(c *gin.Context) {
 		"message": "用户信息更新成功",
 	}))
 }
+
+// UploadAvatar godoc
+// @Summary      上传头像
+// @Description  上传 JPEG 或 PNG 图片，居中裁剪并缩放为 256x256 的 JPEG 后替换当前头像，返回新的头像地址
+// @Tags         用户信息
+// @Accept       multipart/form-data
+// @Produce      json
+// @Security     BearerAuth
+// @Param        file formData file true "头像图片(JPEG/PNG)"
+// @Success      200 {object} domain.SuccessResponse{data=map[string]interface{}} "上传成功"
+// @Failure      400 {object} domain.ErrorResponse "请求参数错误"
+// @Failure      401 {object} domain.ErrorResponse "未授权访问"
+// @Failure      413 {object} domain.ErrorResponse "图片超过大小限制"
+// @Failure      415 {object} domain.ErrorResponse "不支持的图片格式"
+// @Failure      500 {object} domain.ErrorResponse "服务器错误"
+// @Router       /api/user/avatar [post]
+func (uc *UserInfoController) UploadAvatar(c *gin.Context) {
+	userIDValue, exists := c.Get("x-user-id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "未授权访问"))
+		return
+	}
+
+	userID, ok := userIDValue.(string)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, domain.NewErrorResponse(401, "用户ID格式错误"))
+		return
+	}
+
+	file, err := openUploadedFile(c, uc.MaxUploadSize)
+	if err != nil {
+		respondAvatarError(c, err)
+		return
+	}
+	defer file.Close()
+
+	avatarUrl, err := uc.UserInfoUsecase.UploadAvatar(c, userID, file)
+	if err != nil {
+		respondAvatarError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.NewSuccessResponse(map[string]interface{}{
+		"avatarUrl": avatarUrl,
+	}))
+}
+
+// GetAvatar godoc
+// @Summary      获取用户头像
+// @Description  返回通过上传接口设置的头像图片，无需认证；头像地址随每次上传变化，可长期缓存
+// @Tags         用户信息
+// @Produce      image/jpeg
+// @Param        userId path string true "用户ID"
+// @Success      200 {file} binary "头像"
+// @Failure      404 {object} domain.ErrorResponse "头像不存在"
+// @Router       /api/avatars/{userId} [get]
+func (uc *UserInfoController) GetAvatar(c *gin.Context) {
+	body, info, err := uc.UserInfoUsecase.OpenAvatar(c, c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, domain.NewErrorResponse(404, "头像不存在"))
+		return
+	}
+
+	serveBlob(c, body, info, "public, max-age=604800")
+}
+
+func respondAvatarError(c *gin.Context, err error) {
+	if !respondUploadError(c, err) {
+		c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "上传头像失败"))
+	}
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// RequireEmailVerified 要求当前用户已验证邮箱，需要在 JwtAuthMiddleware 之后使用
+func RequireEmailVerified(checker domain.EmailVerifiedChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		verified, err := checker.IsEmailVerified(c, c.GetString("x-user-id"))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, domain.NewErrorResponse(500, "校验邮箱状态失败"))
+			c.Abort()
+			return
+		}
+
+		if !verified {
+			c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "请先验证邮箱"))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -9,26 +9,46 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func JwtAuthMiddleware(secret string) gin.HandlerFunc {
+// JwtAuthMiddleware 访问令牌校验中间件
+// 令牌带有会话ID时，会话被吊销或过期后令牌立即失效，禁用账号时会吊销其全部会话；
+// 没有会话ID的令牌逐次检查账号是否被禁用
+func JwtAuthMiddleware(keys domain.JwtKeySet, sessionUsecase domain.SessionUsecase, userStatus domain.UserStatusChecker) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.Request.Header.Get("Authorization")
 		t := strings.Split(authHeader, " ")
 		if len(t) == 2 {
 			authToken := t[1]
-			authorized, err := tokenutil.IsAuthorized(authToken, secret)
-			if authorized {
-				userID, err := tokenutil.ExtractIDFromToken(authToken, secret)
+			claims, err := tokenutil.ParseAccessToken(authToken, keys)
+			if err != nil {
+				c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: err.Error()})
+				c.Abort()
+				return
+			}
+			if claims.SessionID != "" {
+				active, err := sessionUsecase.IsActive(c, claims.SessionID)
+				if err != nil || !active {
+					c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Session has been revoked"})
+					c.Abort()
+					return
+				}
+				c.Set("x-session-id", claims.SessionID)
+			} else {
+				disabled, err := userStatus.IsDisabled(c, claims.ID)
 				if err != nil {
-					c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: err.Error()})
+					c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "User not found"})
+					c.Abort()
+					return
+				}
+				if disabled {
+					c.JSON(http.StatusForbidden, domain.ErrorResponse{Message: domain.ErrUserDisabled.Error()})
 					c.Abort()
 					return
 				}
-				c.Set("x-user-id", userID)
-				c.Next()
-				return
 			}
-			c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: err.Error()})
-			c.Abort()
+			c.Set("x-user-id", claims.ID)
+			c.Set("x-user-roles", claims.Roles)
+			c.Set("x-user-permissions", claims.Permissions)
+			c.Next()
 			return
 		}
 		c.JSON(http.StatusUnauthorized, domain.ErrorResponse{Message: "Not authorized"})
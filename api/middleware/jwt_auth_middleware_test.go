@@ -0,0 +1,116 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/api/middleware"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/internal/tokenutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// serve 依次执行中间件，全部通过时返回 200 和写入上下文的用户ID
+func serve(authorization string, handlers ...gin.HandlerFunc) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handlers = append(handlers, func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("x-user-id"))
+	})
+	router.GET("/", handlers...)
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestJwtAuthMiddleware(t *testing.T) {
+	keys := tokenutil.NewHMACKeySet("access-secret")
+	user := domain.User{ID: primitive.NewObjectID(), Username: "alice"}
+	sessionID := primitive.NewObjectID().Hex()
+
+	accessToken := func(t *testing.T, sessionID string) string {
+		token, err := tokenutil.CreateAccessToken(&user, sessionID, nil, keys, 1)
+		require.NoError(t, err)
+		return "Bearer " + token
+	}
+
+	t.Run("active session", func(t *testing.T) {
+		sessions := mocks.NewSessionUsecase(t)
+		status := mocks.NewUserStatusChecker(t)
+		// 会话有效时不再查询账号状态，禁用账号会吊销其会话
+		sessions.On("IsActive", mock.Anything, sessionID).Return(true, nil).Once()
+
+		w := serve(accessToken(t, sessionID), middleware.JwtAuthMiddleware(keys, sessions, status))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, user.ID.Hex(), w.Body.String())
+	})
+
+	t.Run("revoked session", func(t *testing.T) {
+		sessions := mocks.NewSessionUsecase(t)
+		status := mocks.NewUserStatusChecker(t)
+		sessions.On("IsActive", mock.Anything, sessionID).Return(false, nil).Once()
+
+		w := serve(accessToken(t, sessionID), middleware.JwtAuthMiddleware(keys, sessions, status))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("session lookup fails", func(t *testing.T) {
+		sessions := mocks.NewSessionUsecase(t)
+		status := mocks.NewUserStatusChecker(t)
+		sessions.On("IsActive", mock.Anything, sessionID).Return(false, assert.AnError).Once()
+
+		w := serve(accessToken(t, sessionID), middleware.JwtAuthMiddleware(keys, sessions, status))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("token without session checks the account status", func(t *testing.T) {
+		sessions := mocks.NewSessionUsecase(t)
+		status := mocks.NewUserStatusChecker(t)
+		status.On("IsDisabled", mock.Anything, user.ID.Hex()).Return(false, nil).Once()
+
+		w := serve(accessToken(t, ""), middleware.JwtAuthMiddleware(keys, sessions, status))
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("disabled user without session", func(t *testing.T) {
+		sessions := mocks.NewSessionUsecase(t)
+		status := mocks.NewUserStatusChecker(t)
+		status.On("IsDisabled", mock.Anything, user.ID.Hex()).Return(true, nil).Once()
+
+		w := serve(accessToken(t, ""), middleware.JwtAuthMiddleware(keys, sessions, status))
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("unknown user without session", func(t *testing.T) {
+		sessions := mocks.NewSessionUsecase(t)
+		status := mocks.NewUserStatusChecker(t)
+		status.On("IsDisabled", mock.Anything, user.ID.Hex()).Return(false, assert.AnError).Once()
+
+		w := serve(accessToken(t, ""), middleware.JwtAuthMiddleware(keys, sessions, status))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("token signed with another key", func(t *testing.T) {
+		token, err := tokenutil.CreateAccessToken(&user, sessionID, nil, tokenutil.NewHMACKeySet("other-secret"), 1)
+		require.NoError(t, err)
+
+		w := serve("Bearer "+token, middleware.JwtAuthMiddleware(keys, mocks.NewSessionUsecase(t), mocks.NewUserStatusChecker(t)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("missing token", func(t *testing.T) {
+		w := serve("", middleware.JwtAuthMiddleware(keys, mocks.NewSessionUsecase(t), mocks.NewUserStatusChecker(t)))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// RequirePermission 权限校验中间件，要求访问令牌中包含全部指定权限
+// 需要在 JwtAuthMiddleware 之后使用；管理员角色拥有全部权限
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if domain.HasRole(c.GetStringSlice("x-user-roles"), domain.RoleAdmin) {
+			c.Next()
+			return
+		}
+
+		granted := map[string]bool{}
+		for _, permission := range c.GetStringSlice("x-user-permissions") {
+			granted[permission] = true
+		}
+
+		for _, permission := range permissions {
+			if !granted[permission] {
+				c.JSON(http.StatusForbidden, domain.NewErrorResponse(403, "权限不足"))
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhengshui/flow-link-server/api/middleware"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// authenticated 模拟 JwtAuthMiddleware 写入上下文的角色和权限
+func authenticated(roles []string, permissions []string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set("x-user-id", "user")
+		c.Set("x-user-roles", roles)
+		c.Set("x-user-permissions", permissions)
+	}
+}
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name        string
+		roles       []string
+		permissions []string
+		required    []string
+		want        int
+	}{
+		{"granted", []string{"user"}, []string{domain.PermissionUserRead}, []string{domain.PermissionUserRead}, http.StatusOK},
+		{"all granted", []string{"user"}, []string{domain.PermissionUserRead, domain.PermissionUserManage}, []string{domain.PermissionUserRead, domain.PermissionUserManage}, http.StatusOK},
+		{"one missing", []string{"user"}, []string{domain.PermissionUserRead}, []string{domain.PermissionUserRead, domain.PermissionUserManage}, http.StatusForbidden},
+		{"no permissions", []string{"user"}, nil, []string{domain.PermissionRoleManage}, http.StatusForbidden},
+		{"admin has every permission", []string{"user", domain.RoleAdmin}, nil, []string{domain.PermissionRoleManage}, http.StatusOK},
+		{"nothing required", []string{"user"}, nil, nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := serve("", authenticated(tt.roles, tt.permissions), middleware.RequirePermission(tt.required...))
+			assert.Equal(t, tt.want, w.Code)
+		})
+	}
+}
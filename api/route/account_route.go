@@ -0,0 +1,31 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+func newAccountUsecase(timeout time.Duration, db mongo.Database, blobStore domain.BlobStore) domain.AccountUsecase {
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	ar := repository.NewAccountRepository(db)
+	lr := repository.NewLoginAttemptRepository(db, domain.CollectionLoginAttempt)
+	return usecase.NewAccountUsecase(ur, ar, lr, blobStore, timeout)
+}
+
+// NewAccountRouter 个人数据导出与账号注销（需要认证）
+func NewAccountRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, blobStore domain.BlobStore, group *gin.RouterGroup) {
+	ac := &controller.AccountController{
+		AccountUsecase: newAccountUsecase(timeout, db, blobStore),
+		Env:            env,
+	}
+	group.GET("/user/account/export", ac.Export)
+	group.POST("/user/account/deletion", ac.RequestDeletion)
+	group.DELETE("/user/account/deletion", ac.CancelDeletion)
+}
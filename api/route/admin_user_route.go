@@ -0,0 +1,33 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/api/middleware"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+// NewAdminUserRouter 管理员路由 - 用户查询（user:read）与账号管理（user:manage）
+func NewAdminUserRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	auc := &controller.AdminUserController{
+		AdminUserUsecase: usecase.NewAdminUserUsecase(ur, sr, rr, timeout),
+	}
+
+	readGroup := group.Group("", middleware.RequirePermission(domain.PermissionUserRead))
+	readGroup.GET("/users", auc.GetList)
+	readGroup.GET("/users/:userId", auc.GetByID)
+
+	manageGroup := group.Group("", middleware.RequirePermission(domain.PermissionUserManage))
+	manageGroup.POST("/users/:userId/disable", auc.Disable)
+	manageGroup.POST("/users/:userId/enable", auc.Enable)
+	manageGroup.POST("/users/:userId/logout", auc.ForceLogout)
+}
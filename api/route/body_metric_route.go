@@ -0,0 +1,27 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+func NewBodyMetricRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	br := repository.NewBodyMetricRepository(db, domain.CollectionBodyMetric)
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	bc := &controller.BodyMetricController{
+		BodyMetricUsecase: usecase.NewBodyMetricUsecase(br, ur, timeout),
+	}
+	group.POST("/body-metrics", bc.Create)
+	group.GET("/body-metrics", bc.GetList)
+	group.GET("/body-metrics/trend", bc.GetTrend)
+	group.GET("/body-metrics/:metricId", bc.GetByID)
+	group.PUT("/body-metrics/:metricId", bc.Update)
+	group.DELETE("/body-metrics/:metricId", bc.Delete)
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/notifier"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+func newEmailVerificationUsecase(env *bootstrap.Env, timeout time.Duration, db mongo.Database) domain.EmailVerificationUsecase {
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	er := repository.NewEmailVerificationRepository(db, domain.CollectionEmailVerification)
+	return usecase.NewEmailVerificationUsecase(ur, er, notifier.New(env.NotifierType, env.NotifierFilePath), timeout)
+}
+
+// NewEmailVerificationRouter 邮箱验证（需要认证）
+func NewEmailVerificationRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	ec := &controller.EmailVerificationController{
+		EmailVerificationUsecase: newEmailVerificationUsecase(env, timeout, db),
+		Env:                      env,
+	}
+	group.POST("/user/email/verification-code", ec.SendCode)
+	group.POST("/user/email/verify", ec.Verify)
+}
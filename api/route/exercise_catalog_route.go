@@ -0,0 +1,41 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/api/middleware"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+func newExerciseCatalogUsecase(timeout time.Duration, db mongo.Database) domain.ExerciseCatalogUsecase {
+	er := repository.NewExerciseCatalogRepository(db, domain.CollectionExerciseCatalog)
+	return usecase.NewExerciseCatalogUsecase(er, timeout)
+}
+
+// NewExerciseCatalogRouter 公开路由（无需认证）- 动作库检索与自动补全
+func NewExerciseCatalogRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	ec := &controller.ExerciseCatalogController{
+		ExerciseCatalogUsecase: newExerciseCatalogUsecase(timeout, db),
+	}
+	group.GET("/exercises", ec.Search)
+	group.GET("/exercises/autocomplete", ec.Autocomplete)
+	group.GET("/exercises/:exerciseId", ec.GetByID)
+}
+
+// NewAdminExerciseCatalogRouter 管理员路由（需要 exercise:manage 权限）- 动作库维护
+func NewAdminExerciseCatalogRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	ec := &controller.ExerciseCatalogController{
+		ExerciseCatalogUsecase: newExerciseCatalogUsecase(timeout, db),
+	}
+
+	exerciseGroup := group.Group("", middleware.RequirePermission(domain.PermissionExerciseManage))
+	exerciseGroup.POST("/exercises", ec.Create)
+	exerciseGroup.PUT("/exercises/:exerciseId", ec.Update)
+	exerciseGroup.DELETE("/exercises/:exerciseId", ec.Delete)
+}
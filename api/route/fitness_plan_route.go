@@ -15,11 +15,14 @@ import (
 func NewFitnessPlanRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	fp := repository.NewFitnessPlanRepository(db, domain.CollectionFitnessPlan)
 	pt := repository.NewPlanTemplateRepository(db, domain.CollectionPlanTemplate)
+	tr := repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord)
+	pr := repository.NewPersonalRecordRepository(db, domain.CollectionPersonalRecord)
 	fc := &controller.FitnessPlanController{
-		FitnessPlanUsecase: usecase.NewFitnessPlanUsecase(fp, pt, timeout),
+		FitnessPlanUsecase: usecase.NewFitnessPlanUsecase(fp, pt, tr, pr, repository.NewUserRepository(db, domain.CollectionUser), newExerciseCatalogUsecase(timeout, db), repository.NewTransactor(db, env.MongoAllowStandalone), timeout),
 	}
 	group.POST("/plans/from-template", fc.CreateFromTemplate)
 	group.POST("/plans/custom", fc.CreateCustom)
+	group.GET("/plans/upcoming", fc.GetUpcomingSessions)
 	group.GET("/plans/:planId", fc.GetByID)
 	group.GET("/plans", fc.GetList)
 	group.PUT("/plans/:planId/status", fc.UpdateStatus)
@@ -28,6 +31,10 @@ func NewFitnessPlanRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Da
 	group.DELETE("/plans/:planId", fc.Delete)
 	// v1.3.0 新增路由
 	group.GET("/plans/:planId/progress", fc.GetProgress)
+	group.GET("/plans/:planId/weeks/:week", fc.GetWeek)
 	group.POST("/plans/:planId/skip-day", fc.SkipDay)
 	group.POST("/plans/:planId/adjust-day", fc.AdjustDay)
+	group.POST("/plans/:planId/shift-days", fc.ShiftDays)
+	group.POST("/plans/:planId/swap-days", fc.SwapDays)
+	group.POST("/plans/:planId/move-day", fc.MoveDay)
 }
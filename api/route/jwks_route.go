@@ -0,0 +1,17 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/mongo"
+)
+
+func NewJwksRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	jc := &controller.JwksController{
+		Env: env,
+	}
+	group.GET("/jwks.json", jc.GetJWKS)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/api/middleware"
 	"github.com/zhengshui/flow-link-server/bootstrap"
 	"github.com/zhengshui/flow-link-server/domain"
 	"github.com/zhengshui/flow-link-server/mongo"
@@ -14,9 +15,36 @@ import (
 
 func NewLoginRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	ro := repository.NewRoleRepository(db, domain.CollectionRole)
+	la := repository.NewLoginAttemptRepository(db, domain.CollectionLoginAttempt)
 	lc := &controller.LoginController{
-		LoginUsecase: usecase.NewLoginUsecase(ur, timeout),
-		Env:          env,
+		LoginUsecase:        usecase.NewLoginUsecase(ur, ro, timeout),
+		RefreshTokenUsecase: usecase.NewRefreshTokenUsecase(ur, rr, sr, ro, timeout),
+		SessionUsecase:      usecase.NewSessionUsecase(sr, rr, timeout),
+		LoginAttemptUsecase: usecase.NewLoginAttemptUsecase(la, ur, loginLockoutPolicy(env), timeout),
+		Env:                 env,
 	}
 	group.POST("/auth/login", lc.Login)
 }
+
+// NewAdminLoginAttemptRouter 管理员路由 - 解除账号登录锁定
+func NewAdminLoginAttemptRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	la := repository.NewLoginAttemptRepository(db, domain.CollectionLoginAttempt)
+	lc := &controller.LoginAttemptController{
+		LoginAttemptUsecase: usecase.NewLoginAttemptUsecase(la, ur, loginLockoutPolicy(env), timeout),
+	}
+	group.POST("/users/:userId/unlock", middleware.RequirePermission(domain.PermissionUserManage), lc.Unlock)
+}
+
+func loginLockoutPolicy(env *bootstrap.Env) domain.LoginLockoutPolicy {
+	return domain.LoginLockoutPolicy{
+		MaxAttempts:   env.LoginMaxAttempts,
+		MaxIPAttempts: env.LoginIPMaxAttempts,
+		BaseLockout:   time.Minute * time.Duration(env.LoginLockoutMinute),
+		MaxLockout:    time.Minute * time.Duration(env.LoginMaxLockoutMinute),
+		Window:        time.Hour * time.Duration(env.LoginAttemptWindowHour),
+	}
+}
@@ -0,0 +1,38 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/notifier"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+func newPasswordController(env *bootstrap.Env, timeout time.Duration, db mongo.Database) *controller.PasswordController {
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	pr := repository.NewPasswordResetRepository(db, domain.CollectionPasswordReset)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	return &controller.PasswordController{
+		PasswordUsecase: usecase.NewPasswordUsecase(ur, pr, rr, sr, notifier.New(env.NotifierType, env.NotifierFilePath), timeout),
+		Env:             env,
+	}
+}
+
+// NewPasswordRouter 密码重置（无需认证）
+func NewPasswordRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	pc := newPasswordController(env, timeout, db)
+	group.POST("/auth/password/forgot", pc.ForgotPassword)
+	group.POST("/auth/password/reset", pc.ResetPassword)
+}
+
+// NewProtectedPasswordRouter 修改密码（需要认证）
+func NewProtectedPasswordRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	pc := newPasswordController(env, timeout, db)
+	group.PUT("/user/password", pc.ChangePassword)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/api/middleware"
 	"github.com/zhengshui/flow-link-server/bootstrap"
 	"github.com/zhengshui/flow-link-server/domain"
 	"github.com/zhengshui/flow-link-server/mongo"
@@ -16,7 +17,7 @@ import (
 func NewPlanTemplateRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	pt := repository.NewPlanTemplateRepository(db, domain.CollectionPlanTemplate)
 	pc := &controller.PlanTemplateController{
-		PlanTemplateUsecase: usecase.NewPlanTemplateUsecase(pt, timeout),
+		PlanTemplateUsecase: usecase.NewPlanTemplateUsecase(pt, newExerciseCatalogUsecase(timeout, db), timeout),
 	}
 	group.GET("/templates/:templateId", pc.GetByID)
 	group.GET("/templates", pc.GetList)
@@ -26,7 +27,7 @@ func NewPlanTemplateRouter(env *bootstrap.Env, timeout time.Duration, db mongo.D
 func NewProtectedPlanTemplateRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	pt := repository.NewPlanTemplateRepository(db, domain.CollectionPlanTemplate)
 	pc := &controller.PlanTemplateController{
-		PlanTemplateUsecase: usecase.NewPlanTemplateUsecase(pt, timeout),
+		PlanTemplateUsecase: usecase.NewPlanTemplateUsecase(pt, newExerciseCatalogUsecase(timeout, db), timeout),
 	}
 	group.POST("/templates/custom", pc.CreateCustom)
 	group.POST("/templates/:templateId/duplicate", pc.Duplicate)
@@ -34,11 +35,15 @@ func NewProtectedPlanTemplateRouter(env *bootstrap.Env, timeout time.Duration, d
 	group.DELETE("/templates/:templateId", pc.Delete)
 }
 
-// NewAdminPlanTemplateRouter 管理员路由（需要认证+管理员权限）- 官方模板管理
+// NewAdminPlanTemplateRouter 管理员路由（需要认证+template:publish 权限，可配置要求已验证邮箱）- 官方模板管理
 func NewAdminPlanTemplateRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	pt := repository.NewPlanTemplateRepository(db, domain.CollectionPlanTemplate)
 	pc := &controller.PlanTemplateController{
-		PlanTemplateUsecase: usecase.NewPlanTemplateUsecase(pt, timeout),
+		PlanTemplateUsecase: usecase.NewPlanTemplateUsecase(pt, newExerciseCatalogUsecase(timeout, db), timeout),
 	}
-	group.POST("/templates", pc.CreateOfficial)
+	handlers := []gin.HandlerFunc{middleware.RequirePermission(domain.PermissionTemplatePublish)}
+	if env.EmailVerificationRequiredToPublish {
+		handlers = append(handlers, middleware.RequireEmailVerified(newEmailVerificationUsecase(env, timeout, db)))
+	}
+	group.POST("/templates", append(handlers, pc.CreateOfficial)...)
 }
@@ -0,0 +1,33 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+// maxUploadSize 单张图片上传大小上限（字节）
+func maxUploadSize(env *bootstrap.Env) int64 {
+	return int64(env.UploadMaxSizeMB) << 20
+}
+
+func NewProgressPhotoRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, blobStore domain.BlobStore, group *gin.RouterGroup) {
+	pr := repository.NewProgressPhotoRepository(db, domain.CollectionProgressPhoto)
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	pc := &controller.ProgressPhotoController{
+		ProgressPhotoUsecase: usecase.NewProgressPhotoUsecase(pr, ur, blobStore, maxUploadSize(env), timeout),
+		MaxUploadSize:        maxUploadSize(env),
+	}
+	group.POST("/progress-photos", pc.Upload)
+	group.GET("/progress-photos", pc.GetList)
+	group.GET("/progress-photos/:photoId", pc.GetByID)
+	group.GET("/progress-photos/:photoId/file", pc.GetFile)
+	group.GET("/progress-photos/:photoId/thumbnail", pc.GetThumbnail)
+	group.DELETE("/progress-photos/:photoId", pc.Delete)
+}
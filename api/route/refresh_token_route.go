@@ -14,9 +14,13 @@ import (
 
 func NewRefreshTokenRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	ro := repository.NewRoleRepository(db, domain.CollectionRole)
 	rtc := &controller.RefreshTokenController{
-		RefreshTokenUsecase: usecase.NewRefreshTokenUsecase(ur, timeout),
+		RefreshTokenUsecase: usecase.NewRefreshTokenUsecase(ur, rr, sr, ro, timeout),
 		Env:                 env,
 	}
 	group.POST("/auth/refresh", rtc.RefreshToken)
+	group.POST("/auth/logout", rtc.Logout)
 }
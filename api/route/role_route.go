@@ -0,0 +1,30 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/api/middleware"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+// NewAdminRoleRouter 管理员路由（需要 role:manage 权限）- 角色定义与分配
+func NewAdminRoleRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	ro := repository.NewRoleRepository(db, domain.CollectionRole)
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	rc := &controller.RoleController{
+		RoleUsecase: usecase.NewRoleUsecase(ro, ur, sr, rr, timeout),
+	}
+
+	roleGroup := group.Group("", middleware.RequirePermission(domain.PermissionRoleManage))
+	roleGroup.GET("/roles", rc.FetchRoles)
+	roleGroup.PUT("/roles/:name", rc.UpsertRole)
+	roleGroup.PUT("/users/:userId/roles", rc.AssignRoles)
+}
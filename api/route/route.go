@@ -6,10 +6,13 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/zhengshui/flow-link-server/api/middleware"
 	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
 	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
 )
 
-func Setup(env *bootstrap.Env, timeout time.Duration, db mongo.Database, router *gin.Engine) {
+func Setup(env *bootstrap.Env, timeout time.Duration, db mongo.Database, blobStore domain.BlobStore, router *gin.Engine) {
 	// Health check endpoint (for Docker/K8s health probes)
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(200, gin.H{
@@ -18,6 +21,9 @@ func Setup(env *bootstrap.Env, timeout time.Duration, db mongo.Database, router
 		})
 	})
 
+	// Public verification keys for services that validate our access tokens
+	NewJwksRouter(env, timeout, db, router.Group("/.well-known"))
+
 	// API group with /api prefix
 	apiGroup := router.Group("/api")
 
@@ -26,18 +32,41 @@ func Setup(env *bootstrap.Env, timeout time.Duration, db mongo.Database, router
 	NewSignupRouter(env, timeout, db, publicRouter)
 	NewLoginRouter(env, timeout, db, publicRouter)
 	NewRefreshTokenRouter(env, timeout, db, publicRouter)
+	NewPasswordRouter(env, timeout, db, publicRouter)
 	// Plan templates public endpoints (GET only)
 	NewPlanTemplateRouter(env, timeout, db, publicRouter)
+	// Exercise catalog search and autocomplete
+	NewExerciseCatalogRouter(env, timeout, db, publicRouter)
+	// Uploaded avatars
+	NewAvatarRouter(env, timeout, db, blobStore, publicRouter)
+
+	// Session and account status checks shared by the JWT middleware of protected and admin APIs
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	sessionUsecase := usecase.NewSessionUsecase(sr, rr, timeout)
+	userStatus := usecase.NewAdminUserUsecase(repository.NewUserRepository(db, domain.CollectionUser), sr, rr, timeout)
 
 	// Protected APIs (JWT authentication required)
 	protectedRouter := apiGroup.Group("")
-	protectedRouter.Use(middleware.JwtAuthMiddleware(env.AccessTokenSecret))
+	protectedRouter.Use(middleware.JwtAuthMiddleware(env.AccessTokenKeys, sessionUsecase, userStatus))
 	// User info
-	NewUserInfoRouter(env, timeout, db, protectedRouter)
+	NewUserInfoRouter(env, timeout, db, blobStore, protectedRouter)
+	// Login sessions (devices)
+	NewSessionRouter(env, timeout, db, protectedRouter)
+	// Change password
+	NewProtectedPasswordRouter(env, timeout, db, protectedRouter)
+	// Email verification
+	NewEmailVerificationRouter(env, timeout, db, protectedRouter)
+	// Personal data export and account deletion
+	NewAccountRouter(env, timeout, db, blobStore, protectedRouter)
 	// Training records
 	NewTrainingRecordRouter(env, timeout, db, protectedRouter)
 	// Fitness plans
 	NewFitnessPlanRouter(env, timeout, db, protectedRouter)
+	// Body metrics (weight, body fat, circumferences)
+	NewBodyMetricRouter(env, timeout, db, protectedRouter)
+	// Progress photos (private to the uploader)
+	NewProgressPhotoRouter(env, timeout, db, blobStore, protectedRouter)
 	// Stats
 	NewStatsRouter(env, timeout, db, protectedRouter)
 	// Feedback
@@ -45,10 +74,17 @@ func Setup(env *bootstrap.Env, timeout time.Duration, db mongo.Database, router
 	// Plan templates protected endpoints (POST, PUT, DELETE for personal templates)
 	NewProtectedPlanTemplateRouter(env, timeout, db, protectedRouter)
 
-	// Admin APIs (JWT authentication + admin role required)
+	// Admin APIs (JWT authentication required, each route checks its own permission)
 	adminRouter := apiGroup.Group("/admin")
-	adminRouter.Use(middleware.JwtAuthMiddleware(env.AccessTokenSecret))
-	adminRouter.Use(middleware.AdminAuthMiddleware(env.AccessTokenSecret))
+	adminRouter.Use(middleware.JwtAuthMiddleware(env.AccessTokenKeys, sessionUsecase, userStatus))
 	// Admin plan templates management
 	NewAdminPlanTemplateRouter(env, timeout, db, adminRouter)
+	// Admin unlock of accounts locked by failed logins
+	NewAdminLoginAttemptRouter(env, timeout, db, adminRouter)
+	// Admin role management
+	NewAdminRoleRouter(env, timeout, db, adminRouter)
+	// Admin user management
+	NewAdminUserRouter(env, timeout, db, adminRouter)
+	// Admin exercise catalog management
+	NewAdminExerciseCatalogRouter(env, timeout, db, adminRouter)
 }
@@ -0,0 +1,23 @@
+package route
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhengshui/flow-link-server/api/controller"
+	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+)
+
+func NewSessionRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	sc := &controller.SessionController{
+		SessionUsecase: usecase.NewSessionUsecase(sr, rr, timeout),
+	}
+	group.GET("/user/sessions", sc.GetSessions)
+	group.DELETE("/user/sessions/:sessionId", sc.RevokeSession)
+}
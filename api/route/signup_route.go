@@ -14,9 +14,14 @@ import (
 
 func NewSignupRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	rr := repository.NewRefreshTokenRepository(db, domain.CollectionRefreshToken)
+	sr := repository.NewSessionRepository(db, domain.CollectionSession)
+	ro := repository.NewRoleRepository(db, domain.CollectionRole)
 	sc := controller.SignupController{
-		SignupUsecase: usecase.NewSignupUsecase(ur, timeout),
-		Env:           env,
+		SignupUsecase:       usecase.NewSignupUsecase(ur, ro, timeout),
+		RefreshTokenUsecase: usecase.NewRefreshTokenUsecase(ur, rr, sr, ro, timeout),
+		SessionUsecase:      usecase.NewSessionUsecase(sr, rr, timeout),
+		Env:                 env,
 	}
 	group.POST("/auth/register", sc.Signup)
 }
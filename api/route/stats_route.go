@@ -15,14 +15,19 @@ import (
 func NewStatsRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	tr := repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord)
 	fp := repository.NewFitnessPlanRepository(db, domain.CollectionFitnessPlan)
+	pr := repository.NewPersonalRecordRepository(db, domain.CollectionPersonalRecord)
+	rr := repository.NewTrainingRollupRepository(db, domain.CollectionTrainingRollup)
+	br := repository.NewBodyMetricRepository(db, domain.CollectionBodyMetric)
 	sc := &controller.StatsController{
-		StatsUsecase: usecase.NewStatsUsecase(tr, fp, timeout),
+		StatsUsecase: usecase.NewStatsUsecase(tr, fp, pr, rr, br, repository.NewUserRepository(db, domain.CollectionUser), env.PROneRMFormula, timeout),
 	}
 	group.GET("/stats/training", sc.GetTrainingStats)
 	group.GET("/stats/muscle-groups", sc.GetMuscleGroupStats)
 	group.GET("/stats/personal-records", sc.GetPersonalRecords)
+	group.GET("/stats/personal-records/history", sc.GetPersonalRecordHistory)
 	group.GET("/stats/calendar", sc.GetCalendar)
 	// v1.3.0 新增路由
 	group.GET("/stats/plan", sc.GetPlanStats)
 	group.GET("/stats/plan-progress", sc.GetPlanProgressList)
+	group.GET("/stats/weight-progress", sc.GetWeightProgress)
 }
@@ -12,10 +12,19 @@ import (
 	"github.com/zhengshui/flow-link-server/usecase"
 )
 
+func newTrainingRollupUsecase(timeout time.Duration, db mongo.Database) domain.TrainingRollupUsecase {
+	tr := repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord)
+	rr := repository.NewTrainingRollupRepository(db, domain.CollectionTrainingRollup)
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	return usecase.NewTrainingRollupUsecase(tr, rr, ur, timeout)
+}
+
 func NewTrainingRecordRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
 	tr := repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord)
+	pr := repository.NewPersonalRecordRepository(db, domain.CollectionPersonalRecord)
+	fp := repository.NewFitnessPlanRepository(db, domain.CollectionFitnessPlan)
 	tc := &controller.TrainingRecordController{
-		TrainingRecordUsecase: usecase.NewTrainingRecordUsecase(tr, timeout),
+		TrainingRecordUsecase: usecase.NewTrainingRecordUsecase(tr, pr, fp, repository.NewUserRepository(db, domain.CollectionUser), newTrainingRollupUsecase(timeout, db), newExerciseCatalogUsecase(timeout, db), repository.NewTransactor(db, env.MongoAllowStandalone), env.PROneRMFormula, timeout),
 	}
 	group.POST("/training/records", tc.Create)
 	group.GET("/training/records/:recordId", tc.GetByID)
@@ -12,11 +12,24 @@ import (
 	"github.com/zhengshui/flow-link-server/usecase"
 )
 
-func NewUserInfoRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, group *gin.RouterGroup) {
+func newUserInfoController(env *bootstrap.Env, timeout time.Duration, db mongo.Database, blobStore domain.BlobStore) *controller.UserInfoController {
 	ur := repository.NewUserRepository(db, domain.CollectionUser)
-	uc := &controller.UserInfoController{
-		UserInfoUsecase: usecase.NewUserInfoUsecase(ur, timeout),
+	br := repository.NewBodyMetricRepository(db, domain.CollectionBodyMetric)
+	return &controller.UserInfoController{
+		UserInfoUsecase: usecase.NewUserInfoUsecase(ur, br, newTrainingRollupUsecase(timeout, db), blobStore, maxUploadSize(env), timeout),
+		MaxUploadSize:   maxUploadSize(env),
 	}
+}
+
+func NewUserInfoRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, blobStore domain.BlobStore, group *gin.RouterGroup) {
+	uc := newUserInfoController(env, timeout, db, blobStore)
 	group.GET("/user/info", uc.GetUserInfo)
 	group.PUT("/user/info", uc.UpdateUserInfo)
+	group.POST("/user/avatar", uc.UploadAvatar)
+}
+
+// NewAvatarRouter 上传的头像（无需认证，他人也可以看到）
+func NewAvatarRouter(env *bootstrap.Env, timeout time.Duration, db mongo.Database, blobStore domain.BlobStore, group *gin.RouterGroup) {
+	uc := newUserInfoController(env, timeout, db, blobStore)
+	group.GET("/avatars/:userId", uc.GetAvatar)
 }
@@ -0,0 +1,17 @@
+package bootstrap
+
+import (
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/blobstore"
+)
+
+// NewBlobStore 按 BLOB_STORE 配置创建上传文件的存储，配置无效时返回错误
+func NewBlobStore(env *Env) (domain.BlobStore, error) {
+	return blobstore.New(env.BlobStore, env.BlobDir, blobstore.S3Config{
+		Endpoint:  env.S3Endpoint,
+		Region:    env.S3Region,
+		Bucket:    env.S3Bucket,
+		AccessKey: env.S3AccessKey,
+		SecretKey: env.S3SecretKey,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"net/url"
 	"time"
 
 	"github.com/zhengshui/flow-link-server/mongo"
@@ -24,6 +25,13 @@ func NewMongoDatabase(env *Env) mongo.Client {
 		mongodbURI = fmt.Sprintf("mongodb://%s:%s", dbHost, dbPort)
 	}
 
+	// 副本集成员地址可能只能在容器网络内解析，未指定副本集时直连该节点（主节点同样支持事务）
+	if env.DBReplicaSet != "" {
+		mongodbURI += "/?replicaSet=" + url.QueryEscape(env.DBReplicaSet)
+	} else {
+		mongodbURI += "/?directConnection=true"
+	}
+
 	client, err := mongo.NewClient(mongodbURI)
 	if err != nil {
 		log.Fatal(err)
@@ -6,26 +6,99 @@ import (
 	"strconv"
 
 	"github.com/spf13/viper"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/blobstore"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"github.com/zhengshui/flow-link-server/internal/tokenutil"
 )
 
 type Env struct {
-	AppEnv                 string `mapstructure:"APP_ENV"`
-	ServerAddress          string `mapstructure:"SERVER_ADDRESS"`
-	ContextTimeout         int    `mapstructure:"CONTEXT_TIMEOUT"`
-	DBHost                 string `mapstructure:"DB_HOST"`
-	DBPort                 string `mapstructure:"DB_PORT"`
-	DBUser                 string `mapstructure:"DB_USER"`
-	DBPass                 string `mapstructure:"DB_PASS"`
-	DBName                 string `mapstructure:"DB_NAME"`
+	AppEnv         string `mapstructure:"APP_ENV"`
+	ServerAddress  string `mapstructure:"SERVER_ADDRESS"`
+	ContextTimeout int    `mapstructure:"CONTEXT_TIMEOUT"`
+	DBHost         string `mapstructure:"DB_HOST"`
+	DBPort         string `mapstructure:"DB_PORT"`
+	DBUser         string `mapstructure:"DB_USER"`
+	DBPass         string `mapstructure:"DB_PASS"`
+	DBName         string `mapstructure:"DB_NAME"`
+	// 副本集名称，为空时直接连接 DB_HOST
+	DBReplicaSet string `mapstructure:"DB_REPLICA_SET"`
+	// 单节点 mongod 不支持事务，开发环境可设为 true 让计划与训练记录的关联写入不使用事务
+	MongoAllowStandalone   bool   `mapstructure:"MONGO_ALLOW_STANDALONE"`
 	AccessTokenExpiryHour  int    `mapstructure:"ACCESS_TOKEN_EXPIRY_HOUR"`
 	RefreshTokenExpiryHour int    `mapstructure:"REFRESH_TOKEN_EXPIRY_HOUR"`
 	AccessTokenSecret      string `mapstructure:"ACCESS_TOKEN_SECRET"`
 	RefreshTokenSecret     string `mapstructure:"REFRESH_TOKEN_SECRET"`
+	// 访问令牌签名算法: HS256 | RS256 | EdDSA，非对称算法从 PEM 文件加载密钥
+	JwtSigningAlg string `mapstructure:"JWT_SIGNING_ALG"`
+	// 当前签名私钥
+	JwtPrivateKeyFile string `mapstructure:"JWT_PRIVATE_KEY_FILE"`
+	// 轮换后仍需校验的旧密钥，逗号分隔
+	JwtVerificationKeyFiles string `mapstructure:"JWT_VERIFICATION_KEY_FILES"`
+	// 非对称算法下是否仍接受 ACCESS_TOKEN_SECRET 签发的 HS256 令牌（迁移过渡期使用）
+	JwtAcceptHS256 bool `mapstructure:"JWT_ACCEPT_HS256"`
+	// 密码重置令牌有效期（分钟）
+	PasswordResetExpiryMinute int `mapstructure:"PASSWORD_RESET_EXPIRY_MINUTE"`
+	// 通知方式: log | file，file 模式写入 NOTIFIER_FILE_PATH
+	NotifierType     string `mapstructure:"NOTIFIER_TYPE"`
+	NotifierFilePath string `mapstructure:"NOTIFIER_FILE_PATH"`
+	// 邮箱验证码有效期（分钟）
+	EmailVerificationExpiryMinute int `mapstructure:"EMAIL_VERIFICATION_EXPIRY_MINUTE"`
+	// 发布官方模板前是否要求已验证邮箱
+	EmailVerificationRequiredToPublish bool `mapstructure:"EMAIL_VERIFICATION_REQUIRED_TO_PUBLISH"`
+	// 申请注销后保留账号的天数，期间可撤销
+	AccountDeletionGraceDay int `mapstructure:"ACCOUNT_DELETION_GRACE_DAY"`
+	// 清理到期注销账号的间隔（分钟）
+	AccountPurgeIntervalMinute int `mapstructure:"ACCOUNT_PURGE_INTERVAL_MINUTE"`
+	// 个人记录估算 1RM 的默认公式：epley 或 brzycki
+	PROneRMFormula string `mapstructure:"PR_ONE_RM_FORMULA"`
+	// 没有设置时区的用户使用的 IANA 时区，也用于迁移旧的无时区训练时间
+	DefaultTimeZone string `mapstructure:"DEFAULT_TIME_ZONE"`
+	// 登录失败锁定策略
+	LoginMaxAttempts       int `mapstructure:"LOGIN_MAX_ATTEMPTS"`
+	LoginIPMaxAttempts     int `mapstructure:"LOGIN_IP_MAX_ATTEMPTS"`
+	LoginLockoutMinute     int `mapstructure:"LOGIN_LOCKOUT_MINUTE"`
+	LoginMaxLockoutMinute  int `mapstructure:"LOGIN_MAX_LOCKOUT_MINUTE"`
+	LoginAttemptWindowHour int `mapstructure:"LOGIN_ATTEMPT_WINDOW_HOUR"`
+	// 上传文件存储: filesystem | s3，filesystem 写入 BLOB_DIR
+	BlobStore string `mapstructure:"BLOB_STORE"`
+	BlobDir   string `mapstructure:"BLOB_DIR"`
+	// S3 兼容存储（AWS S3、MinIO 等），使用路径风格地址
+	S3Endpoint  string `mapstructure:"S3_ENDPOINT"`
+	S3Region    string `mapstructure:"S3_REGION"`
+	S3Bucket    string `mapstructure:"S3_BUCKET"`
+	S3AccessKey string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey string `mapstructure:"S3_SECRET_KEY"`
+	// 单张图片上传大小上限（MB）
+	UploadMaxSizeMB int `mapstructure:"UPLOAD_MAX_SIZE_MB"`
+
+	// 根据上述配置加载的签名/校验密钥
+	AccessTokenKeys  domain.JwtKeySet `mapstructure:"-"`
+	RefreshTokenKeys domain.JwtKeySet `mapstructure:"-"`
 }
 
 func NewEnv() *Env {
 	env := Env{}
 
+	// .env 文件中未配置的可选项使用默认值
+	viper.SetDefault("JWT_SIGNING_ALG", tokenutil.AlgHS256)
+	viper.SetDefault("PASSWORD_RESET_EXPIRY_MINUTE", 30)
+	viper.SetDefault("NOTIFIER_TYPE", "log")
+	viper.SetDefault("EMAIL_VERIFICATION_EXPIRY_MINUTE", 15)
+	viper.SetDefault("ACCOUNT_DELETION_GRACE_DAY", 14)
+	viper.SetDefault("ACCOUNT_PURGE_INTERVAL_MINUTE", 60)
+	viper.SetDefault("PR_ONE_RM_FORMULA", domain.OneRMFormulaEpley)
+	viper.SetDefault("DEFAULT_TIME_ZONE", "Asia/Shanghai")
+	viper.SetDefault("LOGIN_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_IP_MAX_ATTEMPTS", 20)
+	viper.SetDefault("LOGIN_LOCKOUT_MINUTE", 1)
+	viper.SetDefault("LOGIN_MAX_LOCKOUT_MINUTE", 60)
+	viper.SetDefault("LOGIN_ATTEMPT_WINDOW_HOUR", 24)
+	viper.SetDefault("BLOB_STORE", blobstore.TypeFileSystem)
+	viper.SetDefault("BLOB_DIR", "./uploads")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("UPLOAD_MAX_SIZE_MB", 10)
+
 	// 尝试读取 .env 文件（用于本地开发）
 	viper.SetConfigFile(".env")
 	if err := viper.ReadInConfig(); err != nil {
@@ -39,6 +112,14 @@ func NewEnv() *Env {
 		}
 	}
 
+	if err := env.loadJwtKeys(); err != nil {
+		log.Fatal("JWT keys can't be loaded: ", err)
+	}
+
+	if err := timeutil.SetDefaultLocation(env.DefaultTimeZone); err != nil {
+		log.Fatal("Default time zone can't be loaded: ", err)
+	}
+
 	if env.AppEnv == "development" {
 		log.Println("The App is running in development env")
 	} else if env.AppEnv == "production" {
@@ -51,19 +132,69 @@ func NewEnv() *Env {
 // loadFromSystemEnv 从系统环境变量加载配置（用于 Docker 部署）
 func loadFromSystemEnv() Env {
 	return Env{
-		AppEnv:                 getEnv("APP_ENV", "production"),
-		ServerAddress:          getEnv("SERVER_ADDRESS", "0.0.0.0:8080"),
-		ContextTimeout:         getEnvAsInt("CONTEXT_TIMEOUT", 30),
-		DBHost:                 getEnv("DB_HOST", "localhost"),
-		DBPort:                 getEnv("DB_PORT", "27017"),
-		DBUser:                 getEnv("DB_USER", ""),
-		DBPass:                 getEnv("DB_PASS", ""),
-		DBName:                 getEnv("DB_NAME", "flow_link"),
-		AccessTokenExpiryHour:  getEnvAsInt("ACCESS_TOKEN_EXPIRY_HOUR", 24),
-		RefreshTokenExpiryHour: getEnvAsInt("REFRESH_TOKEN_EXPIRY_HOUR", 168),
-		AccessTokenSecret:      getEnv("ACCESS_TOKEN_SECRET", ""),
-		RefreshTokenSecret:     getEnv("REFRESH_TOKEN_SECRET", ""),
+		AppEnv:                             getEnv("APP_ENV", "production"),
+		ServerAddress:                      getEnv("SERVER_ADDRESS", "0.0.0.0:8080"),
+		ContextTimeout:                     getEnvAsInt("CONTEXT_TIMEOUT", 30),
+		DBHost:                             getEnv("DB_HOST", "localhost"),
+		DBPort:                             getEnv("DB_PORT", "27017"),
+		DBUser:                             getEnv("DB_USER", ""),
+		DBPass:                             getEnv("DB_PASS", ""),
+		DBName:                             getEnv("DB_NAME", "flow_link"),
+		DBReplicaSet:                       getEnv("DB_REPLICA_SET", ""),
+		AccessTokenExpiryHour:              getEnvAsInt("ACCESS_TOKEN_EXPIRY_HOUR", 24),
+		RefreshTokenExpiryHour:             getEnvAsInt("REFRESH_TOKEN_EXPIRY_HOUR", 168),
+		AccessTokenSecret:                  getEnv("ACCESS_TOKEN_SECRET", ""),
+		RefreshTokenSecret:                 getEnv("REFRESH_TOKEN_SECRET", ""),
+		JwtSigningAlg:                      getEnv("JWT_SIGNING_ALG", tokenutil.AlgHS256),
+		JwtPrivateKeyFile:                  getEnv("JWT_PRIVATE_KEY_FILE", ""),
+		JwtVerificationKeyFiles:            getEnv("JWT_VERIFICATION_KEY_FILES", ""),
+		JwtAcceptHS256:                     getEnvAsBool("JWT_ACCEPT_HS256", false),
+		PasswordResetExpiryMinute:          getEnvAsInt("PASSWORD_RESET_EXPIRY_MINUTE", 30),
+		NotifierType:                       getEnv("NOTIFIER_TYPE", "log"),
+		NotifierFilePath:                   getEnv("NOTIFIER_FILE_PATH", ""),
+		EmailVerificationExpiryMinute:      getEnvAsInt("EMAIL_VERIFICATION_EXPIRY_MINUTE", 15),
+		EmailVerificationRequiredToPublish: getEnvAsBool("EMAIL_VERIFICATION_REQUIRED_TO_PUBLISH", false),
+		AccountDeletionGraceDay:            getEnvAsInt("ACCOUNT_DELETION_GRACE_DAY", 14),
+		AccountPurgeIntervalMinute:         getEnvAsInt("ACCOUNT_PURGE_INTERVAL_MINUTE", 60),
+		PROneRMFormula:                     getEnv("PR_ONE_RM_FORMULA", domain.OneRMFormulaEpley),
+		DefaultTimeZone:                    getEnv("DEFAULT_TIME_ZONE", "Asia/Shanghai"),
+		LoginMaxAttempts:                   getEnvAsInt("LOGIN_MAX_ATTEMPTS", 5),
+		LoginIPMaxAttempts:                 getEnvAsInt("LOGIN_IP_MAX_ATTEMPTS", 20),
+		LoginLockoutMinute:                 getEnvAsInt("LOGIN_LOCKOUT_MINUTE", 1),
+		LoginMaxLockoutMinute:              getEnvAsInt("LOGIN_MAX_LOCKOUT_MINUTE", 60),
+		LoginAttemptWindowHour:             getEnvAsInt("LOGIN_ATTEMPT_WINDOW_HOUR", 24),
+		BlobStore:                          getEnv("BLOB_STORE", blobstore.TypeFileSystem),
+		BlobDir:                            getEnv("BLOB_DIR", "./uploads"),
+		S3Endpoint:                         getEnv("S3_ENDPOINT", ""),
+		S3Region:                           getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:                           getEnv("S3_BUCKET", ""),
+		S3AccessKey:                        getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:                        getEnv("S3_SECRET_KEY", ""),
+		UploadMaxSizeMB:                    getEnvAsInt("UPLOAD_MAX_SIZE_MB", 10),
+	}
+}
+
+// loadJwtKeys 加载访问令牌和刷新令牌的密钥
+// 刷新令牌只由本服务校验，始终使用 REFRESH_TOKEN_SECRET 的 HS256 签名
+func (env *Env) loadJwtKeys() error {
+	legacySecret := env.AccessTokenSecret
+	if env.JwtSigningAlg != tokenutil.AlgHS256 && !env.JwtAcceptHS256 {
+		legacySecret = ""
 	}
+
+	accessKeys, err := tokenutil.LoadKeySet(
+		env.JwtSigningAlg,
+		env.JwtPrivateKeyFile,
+		tokenutil.SplitKeyFiles(env.JwtVerificationKeyFiles),
+		legacySecret,
+	)
+	if err != nil {
+		return err
+	}
+
+	env.AccessTokenKeys = accessKeys
+	env.RefreshTokenKeys = tokenutil.NewHMACKeySet(env.RefreshTokenSecret)
+	return nil
 }
 
 // getEnv 获取环境变量，如果不存在则返回默认值
@@ -83,3 +214,13 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+// getEnvAsBool 获取环境变量并转换为 bool
+func getEnvAsBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolVal, err := strconv.ParseBool(value); err == nil {
+			return boolVal
+		}
+	}
+	return defaultValue
+}
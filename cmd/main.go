@@ -1,10 +1,17 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"log"
+	"os"
 	"time"
 
 	route "github.com/zhengshui/flow-link-server/api/route"
 	"github.com/zhengshui/flow-link-server/bootstrap"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
 	"github.com/gin-gonic/gin"
 )
 
@@ -39,9 +46,106 @@ func main() {
 
 	timeout := time.Duration(env.ContextTimeout) * time.Second
 
+	// 计划与训练记录的关联写入依赖事务，数据库不支持时在启动时失败，而不是每个写入请求都失败
+	if err := repository.CheckTransactions(context.Background(), db); err != nil {
+		if !errors.Is(err, domain.ErrTransactionsUnsupported) {
+			log.Fatal("MongoDB transaction check failed: ", err)
+		}
+		if !env.MongoAllowStandalone {
+			log.Fatal("MongoDB does not support transactions: run it as a replica set (e.g. mongod --replSet rs0, then rs.initiate()) and set DB_REPLICA_SET, or set MONGO_ALLOW_STANDALONE=true for local development: ", err)
+		}
+		log.Println("MongoDB does not support transactions, transactional writes will run without a transaction (MONGO_ALLOW_STANDALONE=true)")
+	}
+
+	if err := repository.EnsureIndexes(context.Background(), db); err != nil {
+		log.Fatal("Failed to ensure indexes, remove duplicate documents and restart: ", err)
+	}
+
+	if err := repository.EnsureDefaultRoles(context.Background(), db); err != nil {
+		log.Println("Failed to ensure default roles: ", err)
+	}
+
+	rollupUsecase := usecase.NewTrainingRollupUsecase(
+		repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord),
+		repository.NewTrainingRollupRepository(db, domain.CollectionTrainingRollup),
+		repository.NewUserRepository(db, domain.CollectionUser),
+		timeout,
+	)
+
+	// 旧版字符串格式的训练时间转换为日期类型，日期归属可能变化，涉及的用户重建汇总
+	migrated, err := repository.MigrateTrainingTimes(context.Background(), db)
+	if err != nil {
+		log.Println("Failed to migrate training times: ", err)
+	}
+	for _, userID := range migrated {
+		if err := rollupUsecase.Rebuild(context.Background(), userID); err != nil {
+			log.Println("Failed to rebuild rollups after migration: ", err)
+		}
+	}
+	if len(migrated) > 0 {
+		log.Printf("Migrated training times for %d users", len(migrated))
+	}
+
+	// rebuild-rollups [userId] 重新计算训练汇总后退出，不指定用户时处理全部用户
+	if len(os.Args) > 1 && os.Args[1] == "rebuild-rollups" {
+		rebuildRollups(rollupUsecase, os.Args[2:])
+		return
+	}
+
+	blobStore, err := bootstrap.NewBlobStore(env)
+	if err != nil {
+		log.Fatal("Blob store can't be created: ", err)
+	}
+
+	accountUsecase := usecase.NewAccountUsecase(
+		repository.NewUserRepository(db, domain.CollectionUser),
+		repository.NewAccountRepository(db),
+		repository.NewLoginAttemptRepository(db, domain.CollectionLoginAttempt),
+		blobStore,
+		timeout,
+	)
+	go purgeDeletedAccounts(accountUsecase, time.Duration(env.AccountPurgeIntervalMinute)*time.Minute)
+
 	gin := gin.Default()
 
-	route.Setup(env, timeout, db, gin)
+	route.Setup(env, timeout, db, blobStore, gin)
 
 	gin.Run(env.ServerAddress)
 }
+
+// purgeDeletedAccounts 定期删除注销宽限期已结束的账号，interval 不大于 0 时不清理
+func purgeDeletedAccounts(accountUsecase domain.AccountUsecase, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := accountUsecase.PurgeDueAccounts(context.Background())
+		if err != nil {
+			log.Println("Failed to purge deleted accounts: ", err)
+		} else if purged > 0 {
+			log.Printf("Purged %d deleted accounts", purged)
+		}
+		<-ticker.C
+	}
+}
+
+// rebuildRollups 重新计算指定用户或全部用户的训练汇总
+func rebuildRollups(rollupUsecase domain.TrainingRollupUsecase, args []string) {
+	if len(args) > 0 {
+		if err := rollupUsecase.Rebuild(context.Background(), args[0]); err != nil {
+			log.Fatalln("Failed to rebuild rollups: ", err)
+		}
+		log.Printf("Rebuilt rollups for user %s", args[0])
+		return
+	}
+
+	rebuilt, err := rollupUsecase.RebuildAll(context.Background())
+	if err != nil {
+		log.Fatalf("Failed to rebuild rollups after %d users: %v", rebuilt, err)
+	}
+	log.Printf("Rebuilt rollups for %d users", rebuilt)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// 个人数据导出格式
+const (
+	ExportFormatJSON = "json"
+	ExportFormatZIP  = "zip"
+)
+
+// ErrAccountDeletionNotRequested 账号没有待执行的注销申请
+var ErrAccountDeletionNotRequested = errors.New("account deletion not requested")
+
+// AccountData 用户在各集合中的个人数据
+type AccountData struct {
+	TrainingRecords []TrainingRecord `json:"trainingRecords"`
+	FitnessPlans    []FitnessPlan    `json:"fitnessPlans"`
+	PlanTemplates   []PlanTemplate   `json:"planTemplates"` // 仅个人模板
+	Feedbacks       []Feedback       `json:"feedbacks"`
+	BodyMetrics     []BodyMetric     `json:"bodyMetrics"`
+	ProgressPhotos  []ProgressPhoto  `json:"progressPhotos"` // 仅照片信息，照片文件通过接口下载
+}
+
+// AccountExport 个人数据导出内容
+type AccountExport struct {
+	ExportedAt string `json:"exportedAt"`
+	User       User   `json:"user"`
+	AccountData
+}
+
+// DeleteAccountRequest 申请注销账号请求
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// AccountDeletionResponse 注销申请响应
+type AccountDeletionResponse struct {
+	ScheduledAt string `json:"scheduledAt"` // 计划删除时间，此前可撤销
+}
+
+// AccountRepository 跨集合读取和删除用户个人数据
+type AccountRepository interface {
+	GetUserData(c context.Context, userID string) (AccountData, error)
+	// DeleteUserData 删除用户在各集合中的个人数据（不含用户文档本身）
+	DeleteUserData(c context.Context, userID string) error
+}
+
+type AccountUsecase interface {
+	Export(c context.Context, userID string) (AccountExport, error)
+	// RequestDeletion 校验密码后安排在宽限期结束时删除账号，返回计划删除时间
+	RequestDeletion(c context.Context, userID, password string, graceDays int) (time.Time, error)
+	CancelDeletion(c context.Context, userID string) error
+	// PurgeDueAccounts 删除宽限期已结束的账号及其全部数据，返回删除的账号数
+	PurgeDueAccounts(c context.Context) (int, error)
+}
@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrUserDisabled 账号已被管理员禁用
+var ErrUserDisabled = errors.New("user is disabled")
+
+// AdminUserResponse 管理员查看的用户资料
+type AdminUserResponse struct {
+	ID            string   `json:"id"`
+	Username      string   `json:"username"`
+	Nickname      string   `json:"nickname,omitempty"`
+	AvatarUrl     string   `json:"avatarUrl,omitempty"`
+	Email         string   `json:"email,omitempty"`
+	EmailVerified bool     `json:"emailVerified"`
+	Phone         string   `json:"phone,omitempty"`
+	Gender        string   `json:"gender,omitempty"`
+	Age           int      `json:"age,omitempty"`
+	Height        float64  `json:"height,omitempty"`
+	Weight        float64  `json:"weight,omitempty"`
+	TargetWeight  float64  `json:"targetWeight,omitempty"`
+	FitnessGoal   string   `json:"fitnessGoal,omitempty"`
+	Role          string   `json:"role"`
+	Roles         []string `json:"roles"`
+	Disabled      bool     `json:"disabled"`
+	DisabledAt    string   `json:"disabledAt,omitempty"`
+	JoinDate      string   `json:"joinDate"`
+}
+
+// UserStatusChecker 校验账号是否被禁用，供认证中间件使用
+type UserStatusChecker interface {
+	IsDisabled(c context.Context, userID string) (bool, error)
+}
+
+// AdminUserUsecase 管理员用户管理用例接口
+type AdminUserUsecase interface {
+	UserStatusChecker
+	Search(c context.Context, keyword string, page, pageSize int) ([]User, int64, error)
+	GetByID(c context.Context, userID string) (User, error)
+	// SetDisabled 禁用或启用账号，禁用时同时吊销所有登录会话
+	SetDisabled(c context.Context, userID string, disabled bool) error
+	// ForceLogout 吊销用户所有登录会话和刷新令牌
+	ForceLogout(c context.Context, userID string) error
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	// ErrBlobNotFound 存储中不存在该文件
+	ErrBlobNotFound = errors.New("blob not found")
+	// ErrUnsupportedImage 不是支持的图片格式（JPEG/PNG）或无法解码
+	ErrUnsupportedImage = errors.New("unsupported image")
+	// ErrImageTooLarge 图片文件或像素尺寸超过上限
+	ErrImageTooLarge = errors.New("image too large")
+)
+
+// BlobInfo 文件的元信息
+type BlobInfo struct {
+	ContentType string
+	Size        int64
+}
+
+// BlobStore 文件存储接口，key 为以 / 分隔的相对路径，如 users/{userId}/photos/{id}.jpg
+type BlobStore interface {
+	Put(c context.Context, key string, data []byte, contentType string) error
+	// Get 返回文件内容，调用方负责关闭；不存在时返回 ErrBlobNotFound
+	Get(c context.Context, key string) (io.ReadCloser, BlobInfo, error)
+	// Delete 删除文件，不存在时不报错
+	Delete(c context.Context, key string) error
+	// DeletePrefix 删除 key 以 prefix 开头的全部文件
+	DeletePrefix(c context.Context, prefix string) error
+}
@@ -0,0 +1,156 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionBodyMetric = "body_metrics"
+)
+
+// 可查询趋势的身体指标
+const (
+	BodyMetricWeight  = "weight"
+	BodyMetricBodyFat = "bodyFat"
+	BodyMetricWaist   = "waist"
+	BodyMetricChest   = "chest"
+	BodyMetricArm     = "arm"
+	BodyMetricThigh   = "thigh"
+)
+
+// DefaultTrendWindow 趋势移动平均默认的窗口天数
+const DefaultTrendWindow = 7
+
+var (
+	// ErrBodyMetricNotFound 身体数据记录不存在
+	ErrBodyMetricNotFound = errors.New("body metric not found")
+	// ErrBodyMetricConflict 同一天已有身体数据记录
+	ErrBodyMetricConflict = errors.New("body metric already exists for date")
+	// ErrInvalidBodyMetric 日期格式错误、未知指标或没有填写任何指标
+	ErrInvalidBodyMetric = errors.New("invalid body metric")
+)
+
+// BodyMetric 某一天的身体数据，每个用户每天一条
+type BodyMetric struct {
+	ID        primitive.ObjectID `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID `bson:"userId" json:"userId"`
+	Date      string             `bson:"date" json:"date"`                           // 日期 YYYY-MM-DD，按用户时区
+	Weight    *float64           `bson:"weight,omitempty" json:"weight,omitempty"`   // 体重(kg)
+	BodyFat   *float64           `bson:"bodyFat,omitempty" json:"bodyFat,omitempty"` // 体脂率(%)
+	Waist     *float64           `bson:"waist,omitempty" json:"waist,omitempty"`     // 腰围(cm)
+	Chest     *float64           `bson:"chest,omitempty" json:"chest,omitempty"`     // 胸围(cm)
+	Arm       *float64           `bson:"arm,omitempty" json:"arm,omitempty"`         // 臂围(cm)
+	Thigh     *float64           `bson:"thigh,omitempty" json:"thigh,omitempty"`     // 腿围(cm)
+	Notes     *string            `bson:"notes,omitempty" json:"notes,omitempty"`     // 备注
+	CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+	UpdatedAt primitive.DateTime `bson:"updatedAt" json:"updatedAt" swaggertype:"string"`
+}
+
+// Value 返回指标的值，未记录或未知指标返回 nil
+func (m BodyMetric) Value(metric string) *float64 {
+	switch metric {
+	case BodyMetricWeight:
+		return m.Weight
+	case BodyMetricBodyFat:
+		return m.BodyFat
+	case BodyMetricWaist:
+		return m.Waist
+	case BodyMetricChest:
+		return m.Chest
+	case BodyMetricArm:
+		return m.Arm
+	case BodyMetricThigh:
+		return m.Thigh
+	}
+	return nil
+}
+
+// ValidBodyMetric 判断是否为支持的指标名称
+func ValidBodyMetric(metric string) bool {
+	switch metric {
+	case BodyMetricWeight, BodyMetricBodyFat, BodyMetricWaist, BodyMetricChest, BodyMetricArm, BodyMetricThigh:
+		return true
+	}
+	return false
+}
+
+// CreateBodyMetricRequest 记录身体数据请求，至少填写一项指标
+type CreateBodyMetricRequest struct {
+	Date    string   `json:"date"` // 日期 YYYY-MM-DD，默认今天
+	Weight  *float64 `json:"weight,omitempty" binding:"omitempty,gt=0"`
+	BodyFat *float64 `json:"bodyFat,omitempty" binding:"omitempty,gt=0,lt=100"`
+	Waist   *float64 `json:"waist,omitempty" binding:"omitempty,gt=0"`
+	Chest   *float64 `json:"chest,omitempty" binding:"omitempty,gt=0"`
+	Arm     *float64 `json:"arm,omitempty" binding:"omitempty,gt=0"`
+	Thigh   *float64 `json:"thigh,omitempty" binding:"omitempty,gt=0"`
+	Notes   *string  `json:"notes,omitempty"`
+}
+
+// UpdateBodyMetricRequest 更新身体数据请求，未提供的字段保持不变
+type UpdateBodyMetricRequest struct {
+	Date    *string  `json:"date,omitempty"`
+	Weight  *float64 `json:"weight,omitempty" binding:"omitempty,gt=0"`
+	BodyFat *float64 `json:"bodyFat,omitempty" binding:"omitempty,gt=0,lt=100"`
+	Waist   *float64 `json:"waist,omitempty" binding:"omitempty,gt=0"`
+	Chest   *float64 `json:"chest,omitempty" binding:"omitempty,gt=0"`
+	Arm     *float64 `json:"arm,omitempty" binding:"omitempty,gt=0"`
+	Thigh   *float64 `json:"thigh,omitempty" binding:"omitempty,gt=0"`
+	Notes   *string  `json:"notes,omitempty"`
+}
+
+// TrendPoint 趋势中的一天
+type TrendPoint struct {
+	Date          string  `json:"date"`
+	Value         float64 `json:"value"`
+	MovingAverage float64 `json:"movingAverage"` // 截至当天 window 天内记录的平均值
+}
+
+// BodyMetricTrend 某项指标在一段时间内的变化
+type BodyMetricTrend struct {
+	Metric     string       `json:"metric"`
+	Window     int          `json:"window"` // 移动平均窗口(天)
+	Points     []TrendPoint `json:"points"`
+	Latest     *float64     `json:"latest,omitempty"` // 区间内最后一次记录的值
+	Change     float64      `json:"change"`           // 区间内最后一次与第一次记录之差
+	WeeklyRate float64      `json:"weeklyRate"`       // 按移动平均计算的每周变化量
+}
+
+// WeightProgress 体重距目标体重的进度
+type WeightProgress struct {
+	StartWeight   float64 `json:"startWeight"`             // 第一次记录的体重
+	StartDate     string  `json:"startDate,omitempty"`     // 第一次记录的日期
+	CurrentWeight float64 `json:"currentWeight"`           // 最近一次记录的体重
+	CurrentDate   string  `json:"currentDate,omitempty"`   // 最近一次记录的日期
+	TargetWeight  float64 `json:"targetWeight"`            // 目标体重，未设置时为 0
+	Change        float64 `json:"change"`                  // 当前体重 - 起始体重
+	Remaining     float64 `json:"remaining"`               // 目标体重 - 当前体重
+	Progress      float64 `json:"progress"`                // 完成百分比(0-100)
+	WeeklyRate    float64 `json:"weeklyRate"`              // 最近 4 周的每周变化量
+	EstimatedDate string  `json:"estimatedDate,omitempty"` // 按最近变化速度预计达到目标的日期
+}
+
+// BodyMetricRepository 身体数据仓储接口
+type BodyMetricRepository interface {
+	Create(c context.Context, metric *BodyMetric) error
+	GetByID(c context.Context, id string) (BodyMetric, error)
+	// GetByUserID 按日期升序返回 [startDate, endDate] 内的记录，空字符串表示不限
+	GetByUserID(c context.Context, userID, startDate, endDate string) ([]BodyMetric, error)
+	Update(c context.Context, metric *BodyMetric) error
+	Delete(c context.Context, id string) error
+	// UpsertWeight 设置某一天的体重，当天没有记录时新建
+	UpsertWeight(c context.Context, userID, date string, weight float64) error
+}
+
+// BodyMetricUsecase 身体数据用例接口
+type BodyMetricUsecase interface {
+	Create(c context.Context, userID string, request *CreateBodyMetricRequest) (BodyMetric, error)
+	GetByID(c context.Context, userID, id string) (BodyMetric, error)
+	GetList(c context.Context, userID, startDate, endDate string) ([]BodyMetric, error)
+	Update(c context.Context, userID, id string, request *UpdateBodyMetricRequest) (BodyMetric, error)
+	Delete(c context.Context, userID, id string) error
+	// GetTrend 指标在 [startDate, endDate] 内的变化，window 为移动平均的天数
+	GetTrend(c context.Context, userID, metric, startDate, endDate string, window int) (BodyMetricTrend, error)
+}
@@ -0,0 +1,72 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionEmailVerification = "email_verifications"
+)
+
+var (
+	// ErrUserConflict 写入用户时触发唯一索引冲突（用户名/邮箱/手机号）
+	ErrUserConflict = errors.New("username, email or phone already in use")
+	// ErrEmailTaken 邮箱已被其他账号使用
+	ErrEmailTaken = errors.New("email already in use")
+	// ErrPhoneTaken 手机号已被其他账号使用
+	ErrPhoneTaken = errors.New("phone already in use")
+	// ErrEmailNotSet 账号未绑定邮箱
+	ErrEmailNotSet = errors.New("email not set")
+	// ErrEmailAlreadyVerified 邮箱已完成验证
+	ErrEmailAlreadyVerified = errors.New("email already verified")
+	// ErrEmailNotVerified 操作要求已验证邮箱
+	ErrEmailNotVerified = errors.New("email not verified")
+	// ErrVerificationCodeInvalid 验证码错误、已过期或尝试次数过多
+	ErrVerificationCodeInvalid = errors.New("invalid or expired verification code")
+	// ErrVerificationCodeTooFrequent 验证码发送过于频繁
+	ErrVerificationCodeTooFrequent = errors.New("verification code requested too frequently")
+)
+
+// EmailVerification 邮箱验证码，仅保存验证码的摘要
+type EmailVerification struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID  `bson:"userId" json:"userId"`
+	Email     string              `bson:"email" json:"email"` // 发送时的邮箱，用户修改邮箱后旧验证码失效
+	CodeHash  string              `bson:"codeHash" json:"-"`
+	Attempts  int                 `bson:"attempts" json:"attempts"` // 已尝试次数
+	ExpiresAt primitive.DateTime  `bson:"expiresAt" json:"expiresAt" swaggertype:"string"`
+	UsedAt    *primitive.DateTime `bson:"usedAt,omitempty" json:"usedAt,omitempty" swaggertype:"string"`
+	CreatedAt primitive.DateTime  `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+}
+
+// VerifyEmailRequest 验证邮箱请求
+type VerifyEmailRequest struct {
+	Code string `json:"code" binding:"required,len=6"`
+}
+
+type EmailVerificationRepository interface {
+	Create(c context.Context, verification *EmailVerification) error
+	// GetLatestByUserID 获取用户最近一个未使用的验证码
+	GetLatestByUserID(c context.Context, userID string) (EmailVerification, error)
+	IncrementAttempts(c context.Context, id string) error
+	// MarkUsed 原子地标记验证码已使用，已被使用过时返回 false
+	MarkUsed(c context.Context, id string) (bool, error)
+	// InvalidateByUserID 作废用户所有未使用的验证码
+	InvalidateByUserID(c context.Context, userID string) error
+}
+
+// EmailVerifiedChecker 校验用户邮箱是否已验证，供需要验证邮箱的接口使用
+type EmailVerifiedChecker interface {
+	IsEmailVerified(c context.Context, userID string) (bool, error)
+}
+
+type EmailVerificationUsecase interface {
+	EmailVerifiedChecker
+	// SendCode 生成验证码并通过 Notifier 发送到用户当前邮箱
+	SendCode(c context.Context, userID string, expiryMinute int) error
+	// Verify 校验验证码，成功后将邮箱标记为已验证
+	Verify(c context.Context, userID string, code string) error
+}
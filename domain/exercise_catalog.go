@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionExerciseCatalog = "exercises"
+)
+
+var (
+	// ErrExerciseNotFound 动作库中不存在该动作
+	ErrExerciseNotFound = errors.New("exercise not found")
+	// ErrExerciseConflict 动作名称已存在
+	ErrExerciseConflict = errors.New("exercise name already exists")
+)
+
+// 动作类别，决定动作库未单独设置 MET 时使用的默认代谢当量
+const (
+	ExerciseCategoryStrength    = "力量"
+	ExerciseCategoryCardio      = "有氧"
+	ExerciseCategoryHIIT        = "HIIT"
+	ExerciseCategoryFlexibility = "柔韧"
+)
+
+// CatalogExercise 动作库条目，ID 的十六进制字符串即规范动作ID
+type CatalogExercise struct {
+	ID               primitive.ObjectID `bson:"_id" json:"id"`
+	Name             string             `bson:"name" json:"name"`                             // 规范名称（唯一）
+	Aliases          []string           `bson:"aliases" json:"aliases"`                       // 别名，如 "卧推"、"Bench Press"
+	PrimaryMuscles   []string           `bson:"primaryMuscles" json:"primaryMuscles"`         // 主要肌群
+	SecondaryMuscles []string           `bson:"secondaryMuscles" json:"secondaryMuscles"`     // 次要肌群
+	Equipment        string             `bson:"equipment" json:"equipment"`                   // 器械：杠铃/哑铃/器械/自重等
+	MovementPattern  string             `bson:"movementPattern" json:"movementPattern"`       // 动作模式：推/拉/蹲/髋铰链等
+	Category         string             `bson:"category,omitempty" json:"category,omitempty"` // 类别：力量/有氧/HIIT/柔韧
+	MET              float64            `bson:"met,omitempty" json:"met,omitempty"`           // 代谢当量，为 0 时按类别取默认值
+	SearchTerms      []string           `bson:"searchTerms" json:"-"`                         // 规范化后的名称和别名，用于检索
+	CreatedAt        primitive.DateTime `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+	UpdatedAt        primitive.DateTime `bson:"updatedAt" json:"updatedAt" swaggertype:"string"`
+}
+
+// CreateCatalogExerciseRequest 新增动作请求
+type CreateCatalogExerciseRequest struct {
+	Name             string   `json:"name" binding:"required"`
+	Aliases          []string `json:"aliases"`
+	PrimaryMuscles   []string `json:"primaryMuscles" binding:"required,min=1"`
+	SecondaryMuscles []string `json:"secondaryMuscles"`
+	Equipment        string   `json:"equipment"`
+	MovementPattern  string   `json:"movementPattern"`
+	Category         string   `json:"category"`
+	MET              float64  `json:"met" binding:"gte=0"`
+}
+
+// UpdateCatalogExerciseRequest 更新动作请求，未提供的字段保持不变
+type UpdateCatalogExerciseRequest struct {
+	Name             *string  `json:"name,omitempty"`
+	Aliases          []string `json:"aliases,omitempty"`
+	PrimaryMuscles   []string `json:"primaryMuscles,omitempty"`
+	SecondaryMuscles []string `json:"secondaryMuscles,omitempty"`
+	Equipment        *string  `json:"equipment,omitempty"`
+	MovementPattern  *string  `json:"movementPattern,omitempty"`
+	Category         *string  `json:"category,omitempty"`
+	MET              *float64 `json:"met,omitempty" binding:"omitempty,gte=0"`
+}
+
+// NormalizeExerciseTerm 名称和别名统一去除首尾空白并转小写后再比较
+func NormalizeExerciseTerm(term string) string {
+	return strings.ToLower(strings.TrimSpace(term))
+}
+
+// ExerciseCatalogRepository 动作库仓储接口
+type ExerciseCatalogRepository interface {
+	Create(c context.Context, exercise *CatalogExercise) error
+	GetByID(c context.Context, id string) (CatalogExercise, error)
+	GetByIDs(c context.Context, ids []string) ([]CatalogExercise, error)
+	Update(c context.Context, exercise *CatalogExercise) error
+	Delete(c context.Context, id string) error
+	Search(c context.Context, keyword, muscle, equipment string, page, pageSize int) ([]CatalogExercise, int64, error)
+	Autocomplete(c context.Context, prefix string, limit int) ([]CatalogExercise, error)
+	// FindByTerms 返回名称或别名与任一规范化词完全匹配的动作
+	FindByTerms(c context.Context, terms []string) ([]CatalogExercise, error)
+}
+
+// ExerciseResolver 将训练项目与动作库关联：
+// 带 exerciseId 的项目补全名称和肌群，只有名称的项目在唯一匹配时补上 exerciseId
+type ExerciseResolver interface {
+	Resolve(c context.Context, exercises []Exercise) error
+}
+
+// ExerciseCatalogUsecase 动作库用例接口
+type ExerciseCatalogUsecase interface {
+	ExerciseResolver
+	Create(c context.Context, request *CreateCatalogExerciseRequest) (CatalogExercise, error)
+	GetByID(c context.Context, id string) (CatalogExercise, error)
+	Update(c context.Context, id string, request *UpdateCatalogExerciseRequest) (CatalogExercise, error)
+	Delete(c context.Context, id string) error
+	Search(c context.Context, keyword, muscle, equipment string, page, pageSize int) ([]CatalogExercise, int64, error)
+	Autocomplete(c context.Context, prefix string, limit int) ([]CatalogExercise, error)
+}
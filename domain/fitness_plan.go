@@ -2,6 +2,7 @@ package domain
 
 import (
 	"context"
+	"errors"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -10,6 +11,60 @@ const (
 	CollectionFitnessPlan = "fitness_plans"
 )
 
+var (
+	// ErrFitnessPlanNotFound 健身计划不存在
+	ErrFitnessPlanNotFound = errors.New("fitness plan not found")
+	// ErrInvalidPlanDay 计划日超出计划范围
+	ErrInvalidPlanDay = errors.New("invalid plan day")
+	// ErrInvalidTrainingWeekdays 训练星期不在 1-7 之间或有重复
+	ErrInvalidTrainingWeekdays = errors.New("invalid training weekdays")
+	// ErrPlanDayCompleted 已完成的计划日不能再调整日程
+	ErrPlanDayCompleted = errors.New("plan day already completed")
+	// ErrPlanDayNotScheduled 计划日没有安排训练
+	ErrPlanDayNotScheduled = errors.New("plan day has no session")
+	// ErrPlanDayOccupied 移动的目标日期已经安排了训练
+	ErrPlanDayOccupied = errors.New("plan day already has a session")
+	// ErrInvalidShiftDays 顺延天数超出范围
+	ErrInvalidShiftDays = errors.New("invalid shift days")
+	// ErrInvalidPlanWeek 计划周超出计划周期
+	ErrInvalidPlanWeek = errors.New("invalid plan week")
+)
+
+// 日程中训练安排的状态
+const (
+	SessionStatusPending   = "待训练"
+	SessionStatusCompleted = "已完成"
+	SessionStatusSkipped   = "已跳过"
+	SessionStatusMissed    = "已错过"
+)
+
+// 计划日程调整方式
+const (
+	RescheduleShift = "顺延"
+	RescheduleSwap  = "交换"
+	RescheduleMove  = "移动"
+)
+
+// DefaultUpcomingDays 近期训练安排默认查看的天数
+const DefaultUpcomingDays = 7
+
+// PlanSkip 跳过计划日的原因记录
+type PlanSkip struct {
+	DayNumber int                `bson:"dayNumber" json:"dayNumber"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	SkippedAt primitive.DateTime `bson:"skippedAt" json:"skippedAt" swaggertype:"string"`
+}
+
+// PlanReschedule 计划日程调整记录，按顺序作用于排期
+type PlanReschedule struct {
+	Action    string             `bson:"action" json:"action"`                           // 顺延/交换/移动
+	DayNumber int                `bson:"dayNumber" json:"dayNumber"`                     // 交换或移动的计划日，顺延时为开始顺延的计划日
+	TargetDay int                `bson:"targetDay,omitempty" json:"targetDay,omitempty"` // 交换或移动到的计划日
+	Days      int                `bson:"days,omitempty" json:"days,omitempty"`           // 顺延天数
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	CreatedAt primitive.DateTime `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+}
+
 // TrainingDay 训练日程
 type TrainingDay struct {
 	DayNumber    int        `bson:"dayNumber" json:"dayNumber"`                         // 第几天
@@ -35,13 +90,17 @@ type FitnessPlan struct {
 	TrainingDaysPerWeek   int                 `bson:"trainingDaysPerWeek" json:"trainingDaysPerWeek"`       // 每周训练天数
 	TrainingDays          []TrainingDay       `bson:"trainingDays" json:"trainingDays"`                     // 训练日程
 	TrainingDaysOverride  []TrainingDay       `bson:"trainingDaysOverride,omitempty" json:"trainingDaysOverride,omitempty"` // 可选，覆盖后的日程
+	Phases                []PlanPhase         `bson:"phases,omitempty" json:"phases,omitempty"`                             // 可选，计划阶段，设置后各周按所在阶段的训练日安排
+	TrainingWeekdays      []int               `bson:"trainingWeekdays,omitempty" json:"trainingWeekdays,omitempty"`         // 训练星期(1=周一…7=周日)，为空时使用用户偏好
 	StartDate             string              `bson:"startDate" json:"startDate"`                           // 开始日期 YYYY-MM-DD
 	EndDate               string              `bson:"endDate" json:"endDate"`                               // 结束日期 YYYY-MM-DD
 	Status                string              `bson:"status" json:"status"`                                 // 进行中/已完成/已暂停/已归档
-	CurrentWeek           int                 `bson:"currentWeek" json:"currentWeek"`                       // 当前第几周
-	CurrentDay            int                 `bson:"currentDay" json:"currentDay"`                         // 当前第几天
+	CurrentWeek           int                 `bson:"currentWeek" json:"currentWeek"`                       // 当前第几周，读取时按用户时区的今天计算
+	CurrentDay            int                 `bson:"currentDay" json:"currentDay"`                         // 当前是本周第几天，读取时计算
 	CompletedDays         []int               `bson:"completedDays" json:"completedDays"`                   // 已完成的训练日
 	SkippedDays           []int               `bson:"skippedDays" json:"skippedDays"`                       // 跳过的训练日
+	SkipLog               []PlanSkip          `bson:"skipLog,omitempty" json:"skipLog,omitempty"`           // 跳过原因记录
+	Reschedules           []PlanReschedule    `bson:"reschedules,omitempty" json:"reschedules,omitempty"`   // 日程调整记录
 	TotalCompletedDays    int                 `bson:"totalCompletedDays" json:"totalCompletedDays"`         // 累计完成天数
 	CompletionRate        int                 `bson:"completionRate" json:"completionRate"`                 // 完成率(百分比)
 	TotalWeight           float64             `bson:"totalWeight" json:"totalWeight"`                       // 计划累计重量
@@ -61,8 +120,13 @@ type FitnessPlanRepository interface {
 	Delete(c context.Context, id string) error
 	CompletePlanDay(c context.Context, id string, dayNumber int) error
 	UncompletePlanDay(c context.Context, id string, dayNumber int) error
-	SkipPlanDay(c context.Context, id string, dayNumber int) error
+	// SkipPlanDay 将计划日标记为跳过并记录原因
+	SkipPlanDay(c context.Context, id string, dayNumber int, reason string) error
 	UpdateTrainingDay(c context.Context, id string, dayNumber int, exercises []Exercise, notes string) error
+	// SetTotals 写入计划关联训练记录的累计重量、时长和卡路里
+	SetTotals(c context.Context, id string, totals PlanTotals) error
+	// UpdateSchedule 写入调整日程后的计划日、跳过记录、日程调整记录、结束日期以及完成天数和完成率
+	UpdateSchedule(c context.Context, id string, plan *FitnessPlan) error
 }
 
 // CreatePlanFromTemplateRequest 基于模板创建计划请求
@@ -72,6 +136,7 @@ type CreatePlanFromTemplateRequest struct {
 	Name                  string        `json:"name"`
 	DurationWeeksOverride *int          `json:"durationWeeksOverride,omitempty"` // 可选，覆盖模板周期
 	TrainingDaysOverride  []TrainingDay `json:"trainingDaysOverride,omitempty"`  // 可选，轻量调整日程
+	TrainingWeekdays      []int         `json:"trainingWeekdays,omitempty"`      // 可选，训练星期(1=周一…7=周日)
 }
 
 // CreateCustomPlanRequest 创建自定义计划请求
@@ -83,21 +148,26 @@ type CreateCustomPlanRequest struct {
 	TrainingDaysPerWeek int           `json:"trainingDaysPerWeek" binding:"required"`
 	TrainingDays        []TrainingDay `json:"trainingDays" binding:"required"`
 	StartDate           string        `json:"startDate" binding:"required"`
+	TrainingWeekdays    []int         `json:"trainingWeekdays,omitempty"` // 可选，训练星期(1=周一…7=周日)
+	Phases              []PlanPhase   `json:"phases,omitempty"`           // 可选，计划阶段，需从第 1 周起连续覆盖整个计划周期
 }
 
 // CompleteDayRequest 标记训练日完成请求
 type CompleteDayRequest struct {
+	Week      int    `json:"week"` // 可选，指定时 dayNumber 为该周第几天(1-7)
 	DayNumber int    `json:"dayNumber" binding:"required"`
-	RecordID  string `json:"recordId"`
+	RecordID  string `json:"recordId"` // 可选，将该训练记录关联到此计划日
 }
 
 // UncompleteDayRequest 取消完成训练日请求
 type UncompleteDayRequest struct {
+	Week      int `json:"week"` // 可选，指定时 dayNumber 为该周第几天(1-7)
 	DayNumber int `json:"dayNumber" binding:"required"`
 }
 
 // SkipDayRequest 跳过计划日请求
 type SkipDayRequest struct {
+	Week      int    `json:"week"` // 可选，指定时 dayNumber 为该周第几天(1-7)
 	DayNumber int    `json:"dayNumber" binding:"required"`
 	Reason    string `json:"reason"`
 }
@@ -109,6 +179,27 @@ type AdjustDayRequest struct {
 	Notes     string     `json:"notes"`
 }
 
+// ShiftDaysRequest 顺延计划日请求
+type ShiftDaysRequest struct {
+	FromDay int    `json:"fromDay"` // 可选，从该计划日起顺延，默认从最近一次完成之后最早错过的训练开始
+	Days    int    `json:"days" binding:"required"`
+	Reason  string `json:"reason"`
+}
+
+// SwapDaysRequest 交换两个计划日请求
+type SwapDaysRequest struct {
+	DayNumber int    `json:"dayNumber" binding:"required"`
+	TargetDay int    `json:"targetDay" binding:"required"`
+	Reason    string `json:"reason"`
+}
+
+// MoveDayRequest 将计划日移动到指定日期请求
+type MoveDayRequest struct {
+	DayNumber int    `json:"dayNumber" binding:"required"`
+	Date      string `json:"date" binding:"required"` // 目标日期 YYYY-MM-DD
+	Reason    string `json:"reason"`
+}
+
 // UpdatePlanStatusRequest 更新计划状态请求
 type UpdatePlanStatusRequest struct {
 	Status string `json:"status" binding:"required"`
@@ -123,10 +214,29 @@ type PlanProgress struct {
 	CompletionRate   int     `json:"completionRate"`
 	CurrentWeek      int     `json:"currentWeek"`
 	CurrentDay       int     `json:"currentDay"`
+	CurrentPhase     string  `json:"currentPhase,omitempty"` // 今天所在的计划阶段
 	NextTrainingDate string  `json:"nextTrainingDate"`
 	TotalDuration    int     `json:"totalDuration"`
 	TotalWeight      float64 `json:"totalWeight"`
 	TotalCalories    int     `json:"totalCalories"`
+	Phases           []PhaseProgress `json:"phases,omitempty"` // 各阶段完成情况，计划有阶段时返回
+}
+
+// PlanSession 排到日历上的一次计划训练
+type PlanSession struct {
+	PlanID        string     `json:"planId"`
+	PlanName      string     `json:"planName"`
+	Date          string     `json:"date"`      // 日期 YYYY-MM-DD
+	Weekday       int        `json:"weekday"`   // 1=周一 … 7=周日
+	Week          int        `json:"week"`      // 训练所属的计划周，顺延后仍按原计划计算，进阶目标按该周生成
+	Phase         string     `json:"phase,omitempty"` // 训练所属的计划阶段
+	DayNumber     int        `json:"dayNumber"` // 计划日，开始日期为第 1 天，用于完成/跳过训练日
+	DayName       string     `json:"dayName"`
+	Exercises     []Exercise `json:"exercises"`
+	Notes         string     `json:"notes,omitempty"`
+	IntensityHint string     `json:"intensityHint,omitempty"`
+	Status        string     `json:"status"` // 待训练/已完成/已跳过/已错过
+	Deload        bool       `json:"deload,omitempty"` // 是否为减载阶段或有动作处于减载周
 }
 
 // FitnessPlanUsecase 健身计划用例接口
@@ -142,4 +252,14 @@ type FitnessPlanUsecase interface {
 	GetProgress(c context.Context, userID, planID string) (PlanProgress, error)
 	SkipDay(c context.Context, userID, planID string, dayNumber int, reason string) (map[string]interface{}, error)
 	AdjustDay(c context.Context, userID, planID string, dayNumber int, exercises []Exercise, notes string) error
+	// ShiftDays 将 fromDay 起未完成的训练整体顺延 days 天，计划结束日期随之延后
+	ShiftDays(c context.Context, userID, planID string, request *ShiftDaysRequest) (map[string]interface{}, error)
+	// SwapDays 交换两个计划日的训练
+	SwapDays(c context.Context, userID, planID string, request *SwapDaysRequest) (map[string]interface{}, error)
+	// MoveDay 将计划日的训练移动到没有安排训练的日期
+	MoveDay(c context.Context, userID, planID string, request *MoveDayRequest) (map[string]interface{}, error)
+	// GetUpcomingSessions 返回用户进行中的计划从今天起 days 天内的训练安排，按日期排序
+	GetUpcomingSessions(c context.Context, userID string, days int) ([]PlanSession, error)
+	// GetWeek 返回计划第 week 周的训练安排，动作按进阶规则替换为当周目标
+	GetWeek(c context.Context, userID, planID string, week int) ([]PlanSession, error)
 }
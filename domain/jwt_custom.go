@@ -5,13 +5,45 @@ import (
 )
 
 type JwtCustomClaims struct {
-	Name string `json:"name"`
-	ID   string `json:"id"`
-	Role string `json:"role"`
+	Name        string   `json:"name"`
+	ID          string   `json:"id"`
+	Role        string   `json:"role"`
+	SessionID   string   `json:"sid,omitempty"`   // 所属登录会话ID
+	Roles       []string `json:"roles,omitempty"` // 全部角色
+	Permissions []string `json:"perms,omitempty"` // 签发时角色对应的权限，校验时无需查库
 	jwt.StandardClaims
 }
 
 type JwtCustomRefreshClaims struct {
-	ID string `json:"id"`
+	ID       string `json:"id"`
+	FamilyID string `json:"fid,omitempty"` // 令牌家族ID，令牌ID存放在 jti 中
 	jwt.StandardClaims
 }
+
+// JwtKeySet 签发和校验 JWT 的密钥集合
+// HS256 使用共享密钥；RS256/EdDSA 使用私钥签名，可同时保留多个公钥用于轮换期间的校验
+type JwtKeySet interface {
+	// Sign 使用当前签名密钥签发令牌，非对称密钥会在头部写入 kid
+	Sign(claims jwt.Claims) (string, error)
+	// Keyfunc 根据令牌头部的 alg/kid 选择校验密钥，供 jwt.ParseWithClaims 使用
+	Keyfunc(token *jwt.Token) (interface{}, error)
+	// PublicKeys 可公开发布的校验公钥，HS256 密钥不会包含在内
+	PublicKeys() []JWK
+}
+
+// JWK JSON Web Key (RFC 7517)，仅包含公钥参数
+type JWK struct {
+	Kty string `json:"kty"`           // RSA / OKP
+	Kid string `json:"kid"`           // 密钥ID，与令牌头部 kid 对应
+	Use string `json:"use"`           // sig
+	Alg string `json:"alg"`           // RS256 / EdDSA
+	N   string `json:"n,omitempty"`   // RSA 模数
+	E   string `json:"e,omitempty"`   // RSA 指数
+	Crv string `json:"crv,omitempty"` // Ed25519
+	X   string `json:"x,omitempty"`   // Ed25519 公钥
+}
+
+// JWKSResponse /.well-known/jwks.json 响应
+type JWKSResponse struct {
+	Keys []JWK `json:"keys"`
+}
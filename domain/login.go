@@ -2,20 +2,69 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"strings"
+)
+
+// 登录标识匹配到的字段
+const (
+	LoginIdentifierUsername = "username"
+	LoginIdentifierEmail    = "email"
+	LoginIdentifierPhone    = "phone"
+)
+
+var (
+	// ErrUserNotFound 登录标识未匹配到任何账号
+	ErrUserNotFound = errors.New("user not found")
+	// ErrLoginIdentifierAmbiguous 登录标识匹配到的多个账号密码都正确，无法确定登录哪个账号
+	ErrLoginIdentifierAmbiguous = errors.New("login identifier matches multiple accounts")
+	// ErrLoginIdentifierUnverified 使用未验证的邮箱登录
+	ErrLoginIdentifierUnverified = errors.New("login identifier not verified")
 )
 
 type LoginRequest struct {
-	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+	Identifier string `json:"identifier" binding:"required_without=Username"` // 用户名、邮箱或手机号
+	Username   string `json:"username"`                                       // 兼容旧客户端，等同于 identifier
+	Password   string `json:"password" binding:"required"`
+}
+
+// LoginIdentifier 请求中的登录标识，优先使用 identifier
+func (r *LoginRequest) LoginIdentifier() string {
+	if r.Identifier != "" {
+		return strings.TrimSpace(r.Identifier)
+	}
+	return strings.TrimSpace(r.Username)
+}
+
+// LoginCandidate 登录标识解析结果
+type LoginCandidate struct {
+	User      User
+	MatchedBy string // username/email/phone
+}
+
+// CheckVerified 校验登录标识是否可用于登录，邮箱未验证时返回 ErrLoginIdentifierUnverified。
+// 服务端没有短信通道，手机号无法验证，不做要求：手机号在账号间唯一且登录仍需该账号的密码，
+// 未验证的手机号只能登录绑定它的账号
+func (c LoginCandidate) CheckVerified() error {
+	if c.MatchedBy == LoginIdentifierEmail && !c.User.EmailVerified {
+		return ErrLoginIdentifierUnverified
+	}
+	return nil
 }
 
 type LoginResponse struct {
-	Token string `json:"token"`
-	Role  string `json:"role"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	Role         string `json:"role"`
 }
 
 type LoginUsecase interface {
-	GetUserByUsername(c context.Context, username string) (User, error)
-	CreateAccessToken(user *User, secret string, expiry int) (accessToken string, err error)
-	CreateRefreshToken(user *User, secret string, expiry int) (refreshToken string, err error)
+	// ResolveUser 按用户名、邮箱、手机号解析登录标识；用户名精确匹配时只返回该账号，
+	// 否则返回邮箱/手机号匹配到的全部账号，由 Authenticate 校验密码后确定登录的账号；未匹配时返回 ErrUserNotFound
+	ResolveUser(c context.Context, identifier string) ([]LoginCandidate, error)
+	// Authenticate 在候选账号中按密码确定登录的账号：密码均不正确时返回 ErrPasswordIncorrect，
+	// 多个账号密码都正确时返回 ErrLoginIdentifierAmbiguous，使用未验证的邮箱时返回 ErrLoginIdentifierUnverified
+	Authenticate(candidates []LoginCandidate, password string) (LoginCandidate, error)
+	CreateAccessToken(c context.Context, user *User, sessionID string, keys JwtKeySet, expiry int) (accessToken string, err error)
+	CreateRefreshToken(user *User, keys JwtKeySet, expiry int) (refreshToken string, err error)
 }
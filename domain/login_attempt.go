@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionLoginAttempt = "login_attempts"
+)
+
+const (
+	LoginAttemptScopeUsername = "username"
+	LoginAttemptScopeIP       = "ip"
+)
+
+// LoginAttempt 某个用户名或IP的登录失败记录
+// Key 形如 "username:alice" 或 "ip:203.0.113.10"
+type LoginAttempt struct {
+	ID           primitive.ObjectID  `bson:"_id,omitempty" json:"id"`
+	Key          string              `bson:"key" json:"key"`
+	Failures     int                 `bson:"failures" json:"failures"`                                                // 当前锁定周期内的连续失败次数
+	Lockouts     int                 `bson:"lockouts" json:"lockouts"`                                                // 已触发锁定的次数，用于计算指数退避
+	LockedUntil  *primitive.DateTime `bson:"lockedUntil,omitempty" json:"lockedUntil,omitempty" swaggertype:"string"` // 锁定截止时间
+	LastFailedAt primitive.DateTime  `bson:"lastFailedAt" json:"lastFailedAt" swaggertype:"string"`
+	ExpiresAt    primitive.DateTime  `bson:"expiresAt" json:"expiresAt" swaggertype:"string"` // 长时间无失败后由 TTL 索引清理，不早于锁定截止时间
+}
+
+// LoginLockoutPolicy 登录失败锁定策略
+// 连续失败达到上限后锁定 BaseLockout，之后每次锁定时长翻倍，最长 MaxLockout
+type LoginLockoutPolicy struct {
+	MaxAttempts   int           // 每个用户名允许的连续失败次数
+	MaxIPAttempts int           // 每个IP允许的连续失败次数
+	BaseLockout   time.Duration // 首次锁定时长
+	MaxLockout    time.Duration // 锁定时长上限
+	Window        time.Duration // 失败记录保留时长，超过后重新计数
+}
+
+// LoginLockStatus 登录锁定状态
+type LoginLockStatus struct {
+	Locked            bool
+	LockedUntil       time.Time
+	RemainingAttempts int
+}
+
+// LoginFailureResponse 登录失败时在 ApiResponse.data 中返回的信息
+type LoginFailureResponse struct {
+	RemainingAttempts int    `json:"remainingAttempts"`           // 剩余可尝试次数
+	LockedUntil       string `json:"lockedUntil,omitempty"`       // 锁定截止时间 YYYY-MM-DD HH:mm:ss
+	RetryAfterSeconds int    `json:"retryAfterSeconds,omitempty"` // 距离解锁的秒数
+}
+
+type LoginAttemptRepository interface {
+	// GetByKey 获取失败记录，记录不存在时返回零次失败的记录
+	GetByKey(c context.Context, key string) (LoginAttempt, error)
+	// IncrementFailures 原子地累加失败次数（记录不存在时创建），返回更新后的记录
+	IncrementFailures(c context.Context, key string, expiresAt primitive.DateTime) (LoginAttempt, error)
+	// Lock 锁定到指定时间，清零失败次数并累加锁定次数；过期时间顺延到不早于锁定截止时间
+	Lock(c context.Context, key string, lockedUntil primitive.DateTime) error
+	DeleteByKey(c context.Context, key string) error
+	// DeleteByKeys 删除多个键的失败记录
+	DeleteByKeys(c context.Context, keys []string) error
+}
+
+type LoginAttemptUsecase interface {
+	// CheckLocked 检查任一用户名或IP是否处于锁定中
+	CheckLocked(c context.Context, usernames []string, ip string) (LoginLockStatus, error)
+	// RecordFailure 为每个用户名和IP记录一次失败登录，返回剩余次数或锁定信息
+	RecordFailure(c context.Context, usernames []string, ip string) (LoginLockStatus, error)
+	// RecordSuccess 登录成功后清除该用户名的失败记录
+	RecordSuccess(c context.Context, username string) error
+	// UnlockUser 管理员解除用户锁定，只清除该账号的失败记录，IP 维度的锁定独立计时
+	UnlockUser(c context.Context, userID string) error
+}
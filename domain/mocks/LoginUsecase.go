@@ -14,20 +14,41 @@ type LoginUsecase struct {
 	mock.Mock
 }
 
-// CreateAccessToken provides a mock function with given fields: user, secret, expiry
-func (_m *LoginUsecase) CreateAccessToken(user *domain.User, secret string, expiry int) (string, error) {
-	ret := _m.Called(user, secret, expiry)
+// Authenticate provides a mock function with given fields: candidates, password
+func (_m *LoginUsecase) Authenticate(candidates []domain.LoginCandidate, password string) (domain.LoginCandidate, error) {
+	ret := _m.Called(candidates, password)
+
+	var r0 domain.LoginCandidate
+	if rf, ok := ret.Get(0).(func([]domain.LoginCandidate, string) domain.LoginCandidate); ok {
+		r0 = rf(candidates, password)
+	} else {
+		r0 = ret.Get(0).(domain.LoginCandidate)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func([]domain.LoginCandidate, string) error); ok {
+		r1 = rf(candidates, password)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CreateAccessToken provides a mock function with given fields: c, user, sessionID, keys, expiry
+func (_m *LoginUsecase) CreateAccessToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(c, user, sessionID, keys, expiry)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*domain.User, string, int) string); ok {
-		r0 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) string); ok {
+		r0 = rf(c, user, sessionID, keys, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.User, string, int) error); ok {
-		r1 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) error); ok {
+		r1 = rf(c, user, sessionID, keys, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,20 +56,20 @@ func (_m *LoginUsecase) CreateAccessToken(user *domain.User, secret string, expi
 	return r0, r1
 }
 
-// CreateRefreshToken provides a mock function with given fields: user, secret, expiry
-func (_m *LoginUsecase) CreateRefreshToken(user *domain.User, secret string, expiry int) (string, error) {
-	ret := _m.Called(user, secret, expiry)
+// CreateRefreshToken provides a mock function with given fields: user, keys, expiry
+func (_m *LoginUsecase) CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(user, keys, expiry)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*domain.User, string, int) string); ok {
-		r0 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(0).(func(*domain.User, domain.JwtKeySet, int) string); ok {
+		r0 = rf(user, keys, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.User, string, int) error); ok {
-		r1 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(1).(func(*domain.User, domain.JwtKeySet, int) error); ok {
+		r1 = rf(user, keys, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,20 +77,22 @@ func (_m *LoginUsecase) CreateRefreshToken(user *domain.User, secret string, exp
 	return r0, r1
 }
 
-// GetUserByEmail provides a mock function with given fields: c, email
-func (_m *LoginUsecase) GetUserByEmail(c context.Context, email string) (domain.User, error) {
-	ret := _m.Called(c, email)
+// ResolveUser provides a mock function with given fields: c, identifier
+func (_m *LoginUsecase) ResolveUser(c context.Context, identifier string) ([]domain.LoginCandidate, error) {
+	ret := _m.Called(c, identifier)
 
-	var r0 domain.User
-	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
-		r0 = rf(c, email)
+	var r0 []domain.LoginCandidate
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.LoginCandidate); ok {
+		r0 = rf(c, identifier)
 	} else {
-		r0 = ret.Get(0).(domain.User)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.LoginCandidate)
+		}
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(c, email)
+		r1 = rf(c, identifier)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -14,20 +14,20 @@ type RefreshTokenUsecase struct {
 	mock.Mock
 }
 
-// CreateAccessToken provides a mock function with given fields: user, secret, expiry
-func (_m *RefreshTokenUsecase) CreateAccessToken(user *domain.User, secret string, expiry int) (string, error) {
-	ret := _m.Called(user, secret, expiry)
+// CreateAccessToken provides a mock function with given fields: c, user, sessionID, keys, expiry
+func (_m *RefreshTokenUsecase) CreateAccessToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(c, user, sessionID, keys, expiry)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*domain.User, string, int) string); ok {
-		r0 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) string); ok {
+		r0 = rf(c, user, sessionID, keys, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.User, string, int) error); ok {
-		r1 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) error); ok {
+		r1 = rf(c, user, sessionID, keys, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -35,20 +35,20 @@ func (_m *RefreshTokenUsecase) CreateAccessToken(user *domain.User, secret strin
 	return r0, r1
 }
 
-// CreateRefreshToken provides a mock function with given fields: user, secret, expiry
-func (_m *RefreshTokenUsecase) CreateRefreshToken(user *domain.User, secret string, expiry int) (string, error) {
-	ret := _m.Called(user, secret, expiry)
+// CreateRefreshToken provides a mock function with given fields: user, keys, expiry
+func (_m *RefreshTokenUsecase) CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(user, keys, expiry)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*domain.User, string, int) string); ok {
-		r0 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(0).(func(*domain.User, domain.JwtKeySet, int) string); ok {
+		r0 = rf(user, keys, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.User, string, int) error); ok {
-		r1 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(1).(func(*domain.User, domain.JwtKeySet, int) error); ok {
+		r1 = rf(user, keys, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -56,20 +56,20 @@ func (_m *RefreshTokenUsecase) CreateRefreshToken(user *domain.User, secret stri
 	return r0, r1
 }
 
-// ExtractIDFromToken provides a mock function with given fields: requestToken, secret
-func (_m *RefreshTokenUsecase) ExtractIDFromToken(requestToken string, secret string) (string, error) {
-	ret := _m.Called(requestToken, secret)
+// ExtractIDFromToken provides a mock function with given fields: requestToken, keys
+func (_m *RefreshTokenUsecase) ExtractIDFromToken(requestToken string, keys domain.JwtKeySet) (string, error) {
+	ret := _m.Called(requestToken, keys)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(string, string) string); ok {
-		r0 = rf(requestToken, secret)
+	if rf, ok := ret.Get(0).(func(string, domain.JwtKeySet) string); ok {
+		r0 = rf(requestToken, keys)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(string, string) error); ok {
-		r1 = rf(requestToken, secret)
+	if rf, ok := ret.Get(1).(func(string, domain.JwtKeySet) error); ok {
+		r1 = rf(requestToken, keys)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -98,6 +98,62 @@ func (_m *RefreshTokenUsecase) GetUserByID(c context.Context, id string) (domain
 	return r0, r1
 }
 
+// IssueRefreshToken provides a mock function with given fields: c, user, sessionID, keys, expiry
+func (_m *RefreshTokenUsecase) IssueRefreshToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(c, user, sessionID, keys, expiry)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) string); ok {
+		r0 = rf(c, user, sessionID, keys, expiry)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) error); ok {
+		r1 = rf(c, user, sessionID, keys, expiry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevokeRefreshToken provides a mock function with given fields: c, requestToken, keys
+func (_m *RefreshTokenUsecase) RevokeRefreshToken(c context.Context, requestToken string, keys domain.JwtKeySet) error {
+	ret := _m.Called(c, requestToken, keys)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.JwtKeySet) error); ok {
+		r0 = rf(c, requestToken, keys)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RotateRefreshToken provides a mock function with given fields: c, requestToken, keys, expiry
+func (_m *RefreshTokenUsecase) RotateRefreshToken(c context.Context, requestToken string, keys domain.JwtKeySet, expiry int) (domain.RotatedRefreshToken, error) {
+	ret := _m.Called(c, requestToken, keys, expiry)
+
+	var r0 domain.RotatedRefreshToken
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.JwtKeySet, int) domain.RotatedRefreshToken); ok {
+		r0 = rf(c, requestToken, keys, expiry)
+	} else {
+		r0 = ret.Get(0).(domain.RotatedRefreshToken)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, domain.JwtKeySet, int) error); ok {
+		r1 = rf(c, requestToken, keys, expiry)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewRefreshTokenUsecase interface {
 	mock.TestingT
 	Cleanup(func())
@@ -14,6 +14,20 @@ type SignupUsecase struct {
 	mock.Mock
 }
 
+// CheckContactAvailable provides a mock function with given fields: c, email, phone
+func (_m *SignupUsecase) CheckContactAvailable(c context.Context, email string, phone string) error {
+	ret := _m.Called(c, email, phone)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, email, phone)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Create provides a mock function with given fields: c, user
 func (_m *SignupUsecase) Create(c context.Context, user *domain.User) error {
 	ret := _m.Called(c, user)
@@ -28,20 +42,20 @@ func (_m *SignupUsecase) Create(c context.Context, user *domain.User) error {
 	return r0
 }
 
-// CreateAccessToken provides a mock function with given fields: user, secret, expiry
-func (_m *SignupUsecase) CreateAccessToken(user *domain.User, secret string, expiry int) (string, error) {
-	ret := _m.Called(user, secret, expiry)
+// CreateAccessToken provides a mock function with given fields: c, user, sessionID, keys, expiry
+func (_m *SignupUsecase) CreateAccessToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(c, user, sessionID, keys, expiry)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*domain.User, string, int) string); ok {
-		r0 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(0).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) string); ok {
+		r0 = rf(c, user, sessionID, keys, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.User, string, int) error); ok {
-		r1 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(1).(func(context.Context, *domain.User, string, domain.JwtKeySet, int) error); ok {
+		r1 = rf(c, user, sessionID, keys, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -49,20 +63,20 @@ func (_m *SignupUsecase) CreateAccessToken(user *domain.User, secret string, exp
 	return r0, r1
 }
 
-// CreateRefreshToken provides a mock function with given fields: user, secret, expiry
-func (_m *SignupUsecase) CreateRefreshToken(user *domain.User, secret string, expiry int) (string, error) {
-	ret := _m.Called(user, secret, expiry)
+// CreateRefreshToken provides a mock function with given fields: user, keys, expiry
+func (_m *SignupUsecase) CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (string, error) {
+	ret := _m.Called(user, keys, expiry)
 
 	var r0 string
-	if rf, ok := ret.Get(0).(func(*domain.User, string, int) string); ok {
-		r0 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(0).(func(*domain.User, domain.JwtKeySet, int) string); ok {
+		r0 = rf(user, keys, expiry)
 	} else {
 		r0 = ret.Get(0).(string)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(*domain.User, string, int) error); ok {
-		r1 = rf(user, secret, expiry)
+	if rf, ok := ret.Get(1).(func(*domain.User, domain.JwtKeySet, int) error); ok {
+		r1 = rf(user, keys, expiry)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -70,20 +84,20 @@ func (_m *SignupUsecase) CreateRefreshToken(user *domain.User, secret string, ex
 	return r0, r1
 }
 
-// GetUserByEmail provides a mock function with given fields: c, email
-func (_m *SignupUsecase) GetUserByEmail(c context.Context, email string) (domain.User, error) {
-	ret := _m.Called(c, email)
+// GetUserByUsername provides a mock function with given fields: c, username
+func (_m *SignupUsecase) GetUserByUsername(c context.Context, username string) (domain.User, error) {
+	ret := _m.Called(c, username)
 
 	var r0 domain.User
 	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
-		r0 = rf(c, email)
+		r0 = rf(c, username)
 	} else {
 		r0 = ret.Get(0).(domain.User)
 	}
 
 	var r1 error
 	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(c, email)
+		r1 = rf(c, username)
 	} else {
 		r1 = ret.Error(1)
 	}
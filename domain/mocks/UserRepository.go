@@ -7,6 +7,8 @@ import (
 
 	domain "github.com/zhengshui/flow-link-server/domain"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserRepository is an autogenerated mock type for the UserRepository type
@@ -28,6 +30,20 @@ func (_m *UserRepository) Create(c context.Context, user *domain.User) error {
 	return r0
 }
 
+// Delete provides a mock function with given fields: c, id
+func (_m *UserRepository) Delete(c context.Context, id string) error {
+	ret := _m.Called(c, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(c, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Fetch provides a mock function with given fields: c
 func (_m *UserRepository) Fetch(c context.Context) ([]domain.User, error) {
 	ret := _m.Called(c)
@@ -51,6 +67,29 @@ func (_m *UserRepository) Fetch(c context.Context) ([]domain.User, error) {
 	return r0, r1
 }
 
+// FindByIdentifier provides a mock function with given fields: c, identifier, limit
+func (_m *UserRepository) FindByIdentifier(c context.Context, identifier string, limit int) ([]domain.User, error) {
+	ret := _m.Called(c, identifier, limit)
+
+	var r0 []domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []domain.User); ok {
+		r0 = rf(c, identifier, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(c, identifier, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetByEmail provides a mock function with given fields: c, email
 func (_m *UserRepository) GetByEmail(c context.Context, email string) (domain.User, error) {
 	ret := _m.Called(c, email)
@@ -93,6 +132,206 @@ func (_m *UserRepository) GetByID(c context.Context, id string) (domain.User, er
 	return r0, r1
 }
 
+// GetByPhone provides a mock function with given fields: c, phone
+func (_m *UserRepository) GetByPhone(c context.Context, phone string) (domain.User, error) {
+	ret := _m.Called(c, phone)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(c, phone)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, phone)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetByUsername provides a mock function with given fields: c, username
+func (_m *UserRepository) GetByUsername(c context.Context, username string) (domain.User, error) {
+	ret := _m.Called(c, username)
+
+	var r0 domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.User); ok {
+		r0 = rf(c, username)
+	} else {
+		r0 = ret.Get(0).(domain.User)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(c, username)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetDueForDeletion provides a mock function with given fields: c, before, limit
+func (_m *UserRepository) GetDueForDeletion(c context.Context, before time.Time, limit int) ([]domain.User, error) {
+	ret := _m.Called(c, before, limit)
+
+	var r0 []domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time, int) []domain.User); ok {
+		r0 = rf(c, before, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time, int) error); ok {
+		r1 = rf(c, before, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ScheduleDeletion provides a mock function with given fields: c, id, at
+func (_m *UserRepository) ScheduleDeletion(c context.Context, id string, at *time.Time) error {
+	ret := _m.Called(c, id, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) error); ok {
+		r0 = rf(c, id, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: c, keyword, page, pageSize
+func (_m *UserRepository) Search(c context.Context, keyword string, page int, pageSize int) ([]domain.User, int64, error) {
+	ret := _m.Called(c, keyword, page, pageSize)
+
+	var r0 []domain.User
+	if rf, ok := ret.Get(0).(func(context.Context, string, int, int) []domain.User); ok {
+		r0 = rf(c, keyword, page, pageSize)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, string, int, int) int64); ok {
+		r1 = rf(c, keyword, page, pageSize)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string, int, int) error); ok {
+		r2 = rf(c, keyword, page, pageSize)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// SetDisabled provides a mock function with given fields: c, id, disabled
+func (_m *UserRepository) SetDisabled(c context.Context, id string, disabled bool) error {
+	ret := _m.Called(c, id, disabled)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, bool) error); ok {
+		r0 = rf(c, id, disabled)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetEmailVerified provides a mock function with given fields: c, id, email
+func (_m *UserRepository) SetEmailVerified(c context.Context, id string, email string) (bool, error) {
+	ret := _m.Called(c, id, email)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) bool); ok {
+		r0 = rf(c, id, email)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(c, id, email)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: c, id, user
+func (_m *UserRepository) Update(c context.Context, id string, user *domain.User) error {
+	ret := _m.Called(c, id, user)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *domain.User) error); ok {
+		r0 = rf(c, id, user)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdatePassword provides a mock function with given fields: c, id, hashedPassword
+func (_m *UserRepository) UpdatePassword(c context.Context, id string, hashedPassword string) error {
+	ret := _m.Called(c, id, hashedPassword)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(c, id, hashedPassword)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateRoles provides a mock function with given fields: c, id, roles
+func (_m *UserRepository) UpdateRoles(c context.Context, id string, roles []string) error {
+	ret := _m.Called(c, id, roles)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(c, id, roles)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateWeight provides a mock function with given fields: c, id, weight
+func (_m *UserRepository) UpdateWeight(c context.Context, id string, weight float64) error {
+	ret := _m.Called(c, id, weight)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, float64) error); ok {
+		r0 = rf(c, id, weight)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewUserRepository interface {
 	mock.TestingT
 	Cleanup(func())
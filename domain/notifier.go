@@ -0,0 +1,15 @@
+package domain
+
+import "context"
+
+// Notification 发送给用户的通知
+type Notification struct {
+	To      string // 接收方（邮箱/手机号/用户名）
+	Subject string
+	Body    string
+}
+
+// Notifier 通知发送接口，可替换为邮件、短信等实现
+type Notifier interface {
+	Send(c context.Context, notification Notification) error
+}
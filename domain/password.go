@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionPasswordReset = "password_resets"
+)
+
+var (
+	// ErrPasswordIncorrect 原密码错误
+	ErrPasswordIncorrect = errors.New("incorrect password")
+	// ErrPasswordResetTokenInvalid 重置令牌不存在、已过期或已使用
+	ErrPasswordResetTokenInvalid = errors.New("invalid or expired password reset token")
+)
+
+// PasswordReset 密码重置令牌，仅保存令牌的 SHA-256 摘要
+type PasswordReset struct {
+	ID        primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID    primitive.ObjectID  `bson:"userId" json:"userId"`
+	TokenHash string              `bson:"tokenHash" json:"-"`
+	ExpiresAt primitive.DateTime  `bson:"expiresAt" json:"expiresAt" swaggertype:"string"`
+	UsedAt    *primitive.DateTime `bson:"usedAt,omitempty" json:"usedAt,omitempty" swaggertype:"string"` // 使用时间(一次性)
+	CreatedAt primitive.DateTime  `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+}
+
+// ChangePasswordRequest 修改密码请求
+type ChangePasswordRequest struct {
+	OldPassword string `json:"oldPassword" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=6,max=20"`
+}
+
+// ForgotPasswordRequest 申请重置密码请求
+type ForgotPasswordRequest struct {
+	Account string `json:"account" binding:"required"` // 用户名或邮箱
+}
+
+// ResetPasswordRequest 重置密码请求
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"newPassword" binding:"required,min=6,max=20"`
+}
+
+type PasswordResetRepository interface {
+	Create(c context.Context, reset *PasswordReset) error
+	GetByTokenHash(c context.Context, tokenHash string) (PasswordReset, error)
+	// MarkUsed 原子地标记令牌已使用，令牌已被使用过时返回 false
+	MarkUsed(c context.Context, id string) (bool, error)
+	// InvalidateByUserID 作废用户所有未使用的重置令牌
+	InvalidateByUserID(c context.Context, userID string) error
+}
+
+type PasswordUsecase interface {
+	ChangePassword(c context.Context, userID string, request *ChangePasswordRequest) error
+	// RequestPasswordReset 生成重置令牌并通过 Notifier 发送，账号不存在时静默返回
+	RequestPasswordReset(c context.Context, account string, expiryMinute int) error
+	// ResetPassword 使用重置令牌设置新密码，并吊销用户所有登录会话
+	ResetPassword(c context.Context, request *ResetPasswordRequest) error
+}
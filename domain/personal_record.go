@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionPersonalRecord = "personal_records"
+)
+
+// 估算 1RM 的公式
+const (
+	OneRMFormulaEpley   = "epley"   // weight × (1 + reps / 30)
+	OneRMFormulaBrzycki = "brzycki" // weight × 36 / (37 − reps)
+)
+
+// 组类型
+const (
+	SetTypeWarmup   = "热身"
+	SetTypeWorking  = "正式"
+	SetTypeCooldown = "放松"
+)
+
+// 个人记录类型
+const (
+	PRTypeMaxWeight      = "maxWeight"      // 单组最大重量
+	PRTypeEstimatedOneRM = "estimatedOneRM" // 估算 1RM
+	PRTypeRepMax         = "repMax"         // 次数档位最佳重量
+	PRTypeSessionVolume  = "sessionVolume"  // 单次训练量
+)
+
+// RepMaxTargets 统计的次数档位：1RM、3RM、5RM、10RM
+var RepMaxTargets = []int{1, 3, 5, 10}
+
+// ErrOneRMFormulaUnknown 不支持的 1RM 估算公式
+var ErrOneRMFormulaUnknown = errors.New("unknown one rep max formula")
+
+// PRSet 创造个人记录的那一组，SetIndex 为该组在训练项目 setsData 中的下标
+type PRSet struct {
+	RecordID string  `json:"recordId"` // 训练记录ID
+	SetIndex int     `json:"setIndex"` // 组下标（从0开始）
+	Weight   float64 `json:"weight"`   // 重量(kg)
+	Reps     int     `json:"reps"`     // 次数
+	Date     string  `json:"date"`     // 训练日期 YYYY-MM-DD
+}
+
+// EstimatedOneRM 估算 1RM 最佳记录
+type EstimatedOneRM struct {
+	Value   float64 `json:"value"`   // 估算 1RM(kg)
+	Formula string  `json:"formula"` // 使用的公式
+	PRSet
+}
+
+// RepMax 某个次数档位的最佳重量，完成次数不少于 Reps 的组都计入该档位
+type RepMax struct {
+	Target int `json:"target"` // 次数档位
+	PRSet
+}
+
+// SessionVolume 单次训练中该动作的最大训练量(重量×次数之和)
+type SessionVolume struct {
+	Volume   float64 `json:"volume"`   // 训练量(kg)
+	RecordID string  `json:"recordId"` // 训练记录ID
+	Date     string  `json:"date"`     // 训练日期 YYYY-MM-DD
+}
+
+// PersonalRecord 个人记录，只统计已完成的非热身组
+type PersonalRecord struct {
+	ExerciseID        string          `json:"exerciseId,omitempty"`        // 动作库规范ID
+	ExerciseName      string          `json:"exerciseName"`                // 训练项目名称
+	MaxWeight         float64         `json:"maxWeight"`                   // 单组最大重量
+	Date              string          `json:"date"`                        // 最大重量的训练日期
+	RecordID          string          `json:"recordId"`                    // 最大重量所在的训练记录ID
+	SetIndex          int             `json:"setIndex"`                    // 最大重量所在的组下标
+	EstimatedOneRM    *EstimatedOneRM `json:"estimatedOneRM,omitempty"`    // 估算 1RM 最佳
+	RepMaxes          []RepMax        `json:"repMaxes"`                    // 各次数档位最佳重量，未达到的档位不返回
+	BestSessionVolume *SessionVolume  `json:"bestSessionVolume,omitempty"` // 单次训练量最佳
+}
+
+// ValidOneRMFormula 判断 1RM 估算公式是否受支持
+func ValidOneRMFormula(formula string) bool {
+	return formula == OneRMFormulaEpley || formula == OneRMFormulaBrzycki
+}
+
+// PersonalRecordEvent 个人记录历史，每次刷新记录写入一条，用于 PR 时间线。
+// PreviousValue 为空表示该动作该项第一次有成绩
+type PersonalRecordEvent struct {
+	ID            primitive.ObjectID `bson:"_id" json:"id"`
+	UserID        primitive.ObjectID `bson:"userId" json:"-"`
+	ExerciseKey   string             `bson:"exerciseKey" json:"-"` // 归并键：动作库ID或规范化名称
+	ExerciseID    string             `bson:"exerciseId,omitempty" json:"exerciseId,omitempty"`
+	ExerciseName  string             `bson:"exerciseName" json:"exerciseName"`
+	Type          string             `bson:"type" json:"type"`                                       // 记录类型
+	Target        int                `bson:"target,omitempty" json:"target,omitempty"`               // 次数档位，仅 repMax
+	Formula       string             `bson:"formula,omitempty" json:"formula,omitempty"`             // 估算公式，仅 estimatedOneRM
+	Value         float64            `bson:"value" json:"value"`                                     // 新成绩
+	PreviousValue *float64           `bson:"previousValue,omitempty" json:"previousValue,omitempty"` // 被打破的旧成绩
+	RecordID      primitive.ObjectID `bson:"recordId" json:"recordId"`
+	SetIndex      *int               `bson:"setIndex,omitempty" json:"setIndex,omitempty"` // 单次训练量没有组下标
+	Weight        float64            `bson:"weight,omitempty" json:"weight,omitempty"`
+	Reps          int                `bson:"reps,omitempty" json:"reps,omitempty"`
+	Date          string             `bson:"date" json:"date"` // 训练日期 YYYY-MM-DD
+	CreatedAt     primitive.DateTime `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+}
+
+// PersonalRecordRepository 个人记录历史仓储接口
+type PersonalRecordRepository interface {
+	CreateMany(c context.Context, events []PersonalRecordEvent) error
+	CountByUserID(c context.Context, userID string) (int64, error)
+	// GetByExerciseKeys 返回用户指定动作的全部历史，excludeRecordID 非空时排除该训练记录产生的历史
+	GetByExerciseKeys(c context.Context, userID string, exerciseKeys []string, excludeRecordID string) ([]PersonalRecordEvent, error)
+	GetTimeline(c context.Context, userID, exerciseKey string, page, pageSize int) ([]PersonalRecordEvent, int64, error)
+	DeleteByRecordID(c context.Context, recordID string) error
+}
@@ -0,0 +1,88 @@
+package domain
+
+import "errors"
+
+// ErrInvalidPlanPhases 计划阶段没有从第 1 周起连续覆盖整个计划周期，或阶段内没有训练日
+var ErrInvalidPlanPhases = errors.New("invalid plan phases")
+
+// PlanPhase 计划阶段（训练块），阶段内每周按该阶段的训练日安排训练
+type PlanPhase struct {
+	Name         string        `bson:"name" json:"name"`                         // 阶段名称，如 增肌期/力量期/减载周
+	StartWeek    int           `bson:"startWeek" json:"startWeek"`               // 开始周(含)
+	EndWeek      int           `bson:"endWeek" json:"endWeek"`                   // 结束周(含)
+	TrainingDays []TrainingDay `bson:"trainingDays" json:"trainingDays"`         // 阶段内每周的训练日，按 dayNumber 排序
+	Deload       bool          `bson:"deload,omitempty" json:"deload,omitempty"` // 是否为减载阶段
+	Notes        string        `bson:"notes,omitempty" json:"notes,omitempty"`   // 阶段说明
+}
+
+// PhaseProgress 计划阶段的完成情况
+type PhaseProgress struct {
+	Name              string `json:"name"`
+	StartWeek         int    `json:"startWeek"`
+	EndWeek           int    `json:"endWeek"`
+	Deload            bool   `json:"deload,omitempty"`
+	TotalSessions     int    `json:"totalSessions"`     // 阶段内安排的训练次数
+	CompletedSessions int    `json:"completedSessions"` // 阶段内已完成的训练日数
+}
+
+// TrainingSessions 阶段每周的训练次数，即非休息训练日数
+func (p PlanPhase) TrainingSessions() int {
+	sessions := 0
+	for _, day := range p.TrainingDays {
+		if !day.IsRestDay {
+			sessions++
+		}
+	}
+	return sessions
+}
+
+// PhaseAt 返回第 week 周所在的阶段，计划没有阶段或该周不在任何阶段内时返回 nil
+func (p FitnessPlan) PhaseAt(week int) *PlanPhase {
+	for i := range p.Phases {
+		if week >= p.Phases[i].StartWeek && week <= p.Phases[i].EndWeek {
+			return &p.Phases[i]
+		}
+	}
+	return nil
+}
+
+// TotalSessions 计划安排的训练总次数，用于计算完成率：
+// 有阶段时为各阶段周数 × 每周训练次数之和，否则为计划周数 × 每周训练天数
+func (p FitnessPlan) TotalSessions() int {
+	if len(p.Phases) == 0 {
+		return p.DurationWeeks * p.TrainingDaysPerWeek
+	}
+
+	total := 0
+	for _, phase := range p.Phases {
+		total += (phase.EndWeek - phase.StartWeek + 1) * phase.TrainingSessions()
+	}
+	return total
+}
+
+// planDay 将第 week 周的第 dayNumber 天换算为从开始日期算起的计划日，week 为 0 时 dayNumber 即计划日；
+// 周内天数超出 1-7 时返回 0
+func planDay(week, dayNumber int) int {
+	if week == 0 {
+		return dayNumber
+	}
+	if dayNumber < 1 || dayNumber > 7 {
+		return 0
+	}
+	return (week-1)*7 + dayNumber
+}
+
+// PlanDay 请求中的计划日，指定 week 时 dayNumber 为该周第几天
+func (r *CompleteDayRequest) PlanDay() int {
+	return planDay(r.Week, r.DayNumber)
+}
+
+// PlanDay 请求中的计划日，指定 week 时 dayNumber 为该周第几天
+func (r *UncompleteDayRequest) PlanDay() int {
+	return planDay(r.Week, r.DayNumber)
+}
+
+// PlanDay 请求中的计划日，指定 week 时 dayNumber 为该周第几天
+func (r *SkipDayRequest) PlanDay() int {
+	return planDay(r.Week, r.DayNumber)
+}
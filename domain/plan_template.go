@@ -23,6 +23,7 @@ type PlanTemplate struct {
 	DurationWeeks        int                 `bson:"durationWeeks" json:"durationWeeks"`                                   // 计划周期(周)
 	TrainingDaysPerWeek  int                 `bson:"trainingDaysPerWeek" json:"trainingDaysPerWeek"`                       // 每周训练天数
 	TrainingDays         []TrainingDay       `bson:"trainingDays" json:"trainingDays"`                                     // 训练日程
+	Phases               []PlanPhase         `bson:"phases,omitempty" json:"phases,omitempty"`                             // 可选，计划阶段
 	ImageUrl             string              `bson:"imageUrl,omitempty" json:"imageUrl,omitempty"`                         // 封面图片URL
 	Author               string              `bson:"author" json:"author"`                                                 // 作者/来源
 	Tags                 []string            `bson:"tags" json:"tags"`                                                     // 标签
@@ -43,6 +44,7 @@ type CreateCustomTemplateRequest struct {
 	DurationWeeks        int           `json:"durationWeeks" binding:"required"`
 	TrainingDaysPerWeek  int           `json:"trainingDaysPerWeek" binding:"required"`
 	TrainingDays         []TrainingDay `json:"trainingDays" binding:"required"`
+	Phases               []PlanPhase   `json:"phases,omitempty"` // 可选，计划阶段，需从第 1 周起连续覆盖整个周期
 	Tags                 []string      `json:"tags"`
 	ImageUrl             string        `json:"imageUrl"`
 	RecommendedIntensity string        `json:"recommendedIntensity"`
@@ -59,6 +61,7 @@ type UpdateTemplateRequest struct {
 	DurationWeeks        *int          `json:"durationWeeks,omitempty"`
 	TrainingDaysPerWeek  *int          `json:"trainingDaysPerWeek,omitempty"`
 	TrainingDays         []TrainingDay `json:"trainingDays,omitempty"`
+	Phases               []PlanPhase   `json:"phases,omitempty"`
 	Tags                 []string      `json:"tags,omitempty"`
 	ImageUrl             *string       `json:"imageUrl,omitempty"`
 	RecommendedIntensity *string       `json:"recommendedIntensity,omitempty"`
@@ -75,7 +78,8 @@ type CreateOfficialTemplateRequest struct {
 	DurationWeeks        int           `json:"durationWeeks" binding:"required"`
 	TrainingDaysPerWeek  int           `json:"trainingDaysPerWeek" binding:"required"`
 	TrainingDays         []TrainingDay `json:"trainingDays" binding:"required"`
-	Author               string        `json:"author"` // 作者/来源
+	Phases               []PlanPhase   `json:"phases,omitempty"` // 可选，计划阶段，需从第 1 周起连续覆盖整个周期
+	Author               string        `json:"author"`           // 作者/来源
 	Tags                 []string      `json:"tags"`
 	RecommendedIntensity string        `json:"recommendedIntensity"` // 推荐强度
 	ImageUrl             string        `json:"imageUrl"`
@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionProgressPhoto = "progress_photos"
+)
+
+var (
+	// ErrProgressPhotoNotFound 进度照片不存在
+	ErrProgressPhotoNotFound = errors.New("progress photo not found")
+	// ErrInvalidProgressPhoto 日期格式错误
+	ErrInvalidProgressPhoto = errors.New("invalid progress photo")
+)
+
+// ProgressPhoto 进度照片，只有上传者本人可以查看
+type ProgressPhoto struct {
+	ID           primitive.ObjectID `bson:"_id" json:"id"`
+	UserID       primitive.ObjectID `bson:"userId" json:"userId"`
+	Date         string             `bson:"date" json:"date"`               // 拍摄日期 YYYY-MM-DD，按用户时区
+	ContentType  string             `bson:"contentType" json:"contentType"` // image/jpeg 或 image/png
+	Size         int64              `bson:"size" json:"size"`               // 文件大小(字节)
+	Width        int                `bson:"width" json:"width"`
+	Height       int                `bson:"height" json:"height"`
+	Notes        *string            `bson:"notes,omitempty" json:"notes,omitempty"`
+	Key          string             `bson:"key" json:"-"`                    // 原图在存储中的 key
+	ThumbnailKey string             `bson:"thumbnailKey" json:"-"`           // 缩略图在存储中的 key
+	URL          string             `bson:"-" json:"url,omitempty"`          // 原图下载地址
+	ThumbnailURL string             `bson:"-" json:"thumbnailUrl,omitempty"` // 缩略图下载地址
+	CreatedAt    primitive.DateTime `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+}
+
+// ProgressPhotoRepository 进度照片仓储接口
+type ProgressPhotoRepository interface {
+	Create(c context.Context, photo *ProgressPhoto) error
+	GetByID(c context.Context, id string) (ProgressPhoto, error)
+	// GetByUserID 按日期倒序返回 [startDate, endDate] 内的照片，空字符串表示不限
+	GetByUserID(c context.Context, userID, startDate, endDate string) ([]ProgressPhoto, error)
+	Delete(c context.Context, id string) error
+}
+
+// ProgressPhotoUsecase 进度照片用例接口
+type ProgressPhotoUsecase interface {
+	// Upload 校验并保存照片，去除 EXIF 等元数据并生成缩略图；date 为空时记为今天
+	Upload(c context.Context, userID, date string, notes *string, file io.Reader) (ProgressPhoto, error)
+	GetByID(c context.Context, userID, id string) (ProgressPhoto, error)
+	GetList(c context.Context, userID, startDate, endDate string) ([]ProgressPhoto, error)
+	// Open 读取照片原图或缩略图，调用方负责关闭
+	Open(c context.Context, userID, id string, thumbnail bool) (io.ReadCloser, BlobInfo, error)
+	Delete(c context.Context, userID, id string) error
+}
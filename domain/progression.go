@@ -0,0 +1,25 @@
+package domain
+
+import "errors"
+
+// 进阶方式
+const (
+	ProgressionLinear = "linear" // 线性进阶：每周固定加重
+	ProgressionDouble = "double" // 双重进阶：次数练到上限后加重并回到下限
+	ProgressionWave   = "wave"   // 波浪周期：按 1RM 百分比循环，每轮结束后提高训练最大重量
+)
+
+// ErrInvalidProgression 进阶规则缺少必要参数或取值超出范围
+var ErrInvalidProgression = errors.New("invalid progression rule")
+
+// ProgressionRule 模板和计划中单个动作的进阶规则，计划按规则和用户成绩生成每周的目标
+type ProgressionRule struct {
+	Type         string    `bson:"type" json:"type"`                                     // linear/double/wave
+	Increment    float64   `bson:"increment,omitempty" json:"increment,omitempty"`       // 每次加重(kg)；wave 为每轮结束后训练最大重量的增量
+	MinReps      int       `bson:"minReps,omitempty" json:"minReps,omitempty"`           // double：次数下限
+	MaxReps      int       `bson:"maxReps,omitempty" json:"maxReps,omitempty"`           // double：次数上限
+	Percentages  []float64 `bson:"percentages,omitempty" json:"percentages,omitempty"`   // wave：每周的 1RM 百分比，按周循环
+	WaveReps     []int     `bson:"waveReps,omitempty" json:"waveReps,omitempty"`         // wave：每周的次数，与 percentages 一一对应(可选)
+	DeloadEvery  int       `bson:"deloadEvery,omitempty" json:"deloadEvery,omitempty"`   // 每隔几周安排一次减载周，0 表示不减载
+	DeloadFactor float64   `bson:"deloadFactor,omitempty" json:"deloadFactor,omitempty"` // 减载周的重量比例(0-1)，默认 0.7
+}
@@ -2,8 +2,37 @@ package domain
 
 import (
 	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionRefreshToken = "refresh_tokens"
 )
 
+var (
+	// ErrRefreshTokenInvalid 刷新令牌不存在或与服务端记录不匹配
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	// ErrRefreshTokenRevoked 刷新令牌所在家族已被吊销
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	// ErrRefreshTokenReused 已轮换过的刷新令牌被再次使用，整个家族会被吊销
+	ErrRefreshTokenReused = errors.New("refresh token reuse detected")
+)
+
+// RefreshToken 服务端保存的刷新令牌状态
+// ID 对应 JWT 的 jti，同一次登录轮换出的所有令牌共享 FamilyID
+type RefreshToken struct {
+	ID         primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID     primitive.ObjectID  `bson:"userId" json:"userId"`
+	FamilyID   primitive.ObjectID  `bson:"familyId" json:"familyId"`
+	ExpiresAt  primitive.DateTime  `bson:"expiresAt" json:"expiresAt" swaggertype:"string"`
+	UsedAt     *primitive.DateTime `bson:"usedAt,omitempty" json:"usedAt,omitempty" swaggertype:"string"`       // 轮换使用时间(一次性)
+	ReplacedBy *primitive.ObjectID `bson:"replacedBy,omitempty" json:"replacedBy,omitempty"`                    // 轮换后的新令牌ID
+	RevokedAt  *primitive.DateTime `bson:"revokedAt,omitempty" json:"revokedAt,omitempty" swaggertype:"string"` // 吊销时间
+	CreatedAt  primitive.DateTime  `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+}
+
 type RefreshTokenRequest struct {
 	RefreshToken string `form:"refreshToken" binding:"required"`
 }
@@ -13,9 +42,32 @@ type RefreshTokenResponse struct {
 	RefreshToken string `json:"refreshToken"`
 }
 
+// RotatedRefreshToken 刷新令牌轮换结果
+type RotatedRefreshToken struct {
+	User         User
+	SessionID    string
+	RefreshToken string
+}
+
+// RefreshTokenRepository 刷新令牌仓储接口
+type RefreshTokenRepository interface {
+	Create(c context.Context, token *RefreshToken) error
+	GetByID(c context.Context, id string) (RefreshToken, error)
+	// MarkUsed 将未使用且未吊销的令牌标记为已使用，令牌已被使用或吊销时返回 false
+	MarkUsed(c context.Context, id string, replacedBy primitive.ObjectID) (bool, error)
+	RevokeFamily(c context.Context, familyID string) error
+	RevokeByUserID(c context.Context, userID string) error
+}
+
 type RefreshTokenUsecase interface {
 	GetUserByID(c context.Context, id string) (User, error)
-	CreateAccessToken(user *User, secret string, expiry int) (accessToken string, err error)
-	CreateRefreshToken(user *User, secret string, expiry int) (refreshToken string, err error)
-	ExtractIDFromToken(requestToken string, secret string) (string, error)
+	CreateAccessToken(c context.Context, user *User, sessionID string, keys JwtKeySet, expiry int) (accessToken string, err error)
+	CreateRefreshToken(user *User, keys JwtKeySet, expiry int) (refreshToken string, err error)
+	ExtractIDFromToken(requestToken string, keys JwtKeySet) (string, error)
+	// IssueRefreshToken 为新登录会话开启令牌家族(家族ID即会话ID)并签发第一个刷新令牌
+	IssueRefreshToken(c context.Context, user *User, sessionID string, keys JwtKeySet, expiry int) (refreshToken string, err error)
+	// RotateRefreshToken 校验并消费刷新令牌，返回同一家族中的新令牌
+	RotateRefreshToken(c context.Context, requestToken string, keys JwtKeySet, expiry int) (RotatedRefreshToken, error)
+	// RevokeRefreshToken 吊销刷新令牌所在的整个家族(登出)
+	RevokeRefreshToken(c context.Context, requestToken string, keys JwtKeySet) error
 }
@@ -42,4 +42,6 @@ type PaginatedData struct {
 	Records  interface{} `json:"records,omitempty"` // 用于训练记录
 	Plans    interface{} `json:"plans,omitempty"`   // 用于计划
 	Templates interface{} `json:"templates,omitempty"` // 用于模板
+	Users    interface{} `json:"users,omitempty"`    // 用于用户管理
+	Exercises interface{} `json:"exercises,omitempty"` // 用于动作库
 }
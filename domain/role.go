@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionRole = "roles"
+)
+
+// 内置角色
+const (
+	RoleUser          = "user"
+	RoleAdmin         = "admin"
+	RoleCoach         = "coach"
+	RoleContentEditor = "content-editor"
+	RoleSupport       = "support"
+)
+
+// 权限名称，格式为 资源:操作
+const (
+	PermissionTemplatePublish = "template:publish" // 发布官方计划模板
+	PermissionFeedbackTriage  = "feedback:triage"  // 处理用户反馈
+	PermissionUserRead        = "user:read"        // 查看用户资料
+	PermissionUserManage      = "user:manage"      // 管理用户账号（解锁等）
+	PermissionRoleManage      = "role:manage"      // 管理角色及分配角色
+	PermissionExerciseManage  = "exercise:manage"  // 维护动作库
+)
+
+var (
+	// ErrRoleNotFound 角色不存在
+	ErrRoleNotFound = errors.New("role not found")
+	// ErrPermissionUnknown 权限名称不在 AllPermissions 中
+	ErrPermissionUnknown = errors.New("unknown permission")
+	// ErrRoleProtected 管理员角色拥有全部权限，不允许修改
+	ErrRoleProtected = errors.New("role is protected")
+)
+
+// AllPermissions 系统支持的全部权限
+var AllPermissions = []string{
+	PermissionTemplatePublish,
+	PermissionFeedbackTriage,
+	PermissionUserRead,
+	PermissionUserManage,
+	PermissionRoleManage,
+	PermissionExerciseManage,
+}
+
+// Role 角色定义，保存在 roles 集合中，Name 唯一
+type Role struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	Permissions []string           `bson:"permissions" json:"permissions"`
+	CreatedAt   primitive.DateTime `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+	UpdatedAt   primitive.DateTime `bson:"updatedAt" json:"updatedAt" swaggertype:"string"`
+}
+
+// DefaultRoles 启动时写入数据库的内置角色，已存在的角色不会被覆盖，管理员角色除外
+func DefaultRoles() []Role {
+	return []Role{
+		{Name: RoleUser, Description: "普通用户", Permissions: []string{}},
+		{Name: RoleAdmin, Description: "管理员", Permissions: AllPermissions},
+		{Name: RoleCoach, Description: "教练", Permissions: []string{PermissionUserRead}},
+		{Name: RoleContentEditor, Description: "内容编辑", Permissions: []string{PermissionTemplatePublish, PermissionExerciseManage}},
+		{Name: RoleSupport, Description: "客服", Permissions: []string{PermissionFeedbackTriage, PermissionUserRead}},
+	}
+}
+
+// HasRole 角色列表中是否包含指定角色
+func HasRole(roles []string, name string) bool {
+	for _, role := range roles {
+		if role == name {
+			return true
+		}
+	}
+	return false
+}
+
+// LegacyRole 由角色列表推导旧版 role 字段，分配角色时一并写入，使两者保持一致
+func LegacyRole(roles []string) string {
+	if HasRole(roles, RoleAdmin) {
+		return RoleAdmin
+	}
+	return RoleUser
+}
+
+// UpsertRoleRequest 创建或更新角色请求
+type UpsertRoleRequest struct {
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" binding:"required"`
+}
+
+// AssignRolesRequest 为用户分配角色请求
+type AssignRolesRequest struct {
+	Roles []string `json:"roles" binding:"required"`
+}
+
+type RoleRepository interface {
+	Fetch(c context.Context) ([]Role, error)
+	GetByNames(c context.Context, names []string) ([]Role, error)
+	Upsert(c context.Context, role *Role) error
+	// InsertIfMissing 仅在角色不存在时写入，用于初始化内置角色
+	InsertIfMissing(c context.Context, role *Role) error
+}
+
+type RoleUsecase interface {
+	Fetch(c context.Context) ([]Role, error)
+	Upsert(c context.Context, name string, request *UpsertRoleRequest) (Role, error)
+	// AssignRoles 替换用户的角色列表，并吊销其登录会话使新权限立即生效
+	AssignRoles(c context.Context, userID string, roles []string) error
+}
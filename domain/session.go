@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionSession = "sessions"
+)
+
+// Session 登录会话(设备)
+// ID 与该次登录签发的刷新令牌家族ID一致，访问令牌通过 sid 声明关联到会话
+type Session struct {
+	ID         primitive.ObjectID  `bson:"_id" json:"id"`
+	UserID     primitive.ObjectID  `bson:"userId" json:"userId"`
+	UserAgent  string              `bson:"userAgent" json:"userAgent"`
+	IP         string              `bson:"ip" json:"ip"`
+	CreatedAt  primitive.DateTime  `bson:"createdAt" json:"createdAt" swaggertype:"string"`
+	LastUsedAt primitive.DateTime  `bson:"lastUsedAt" json:"lastUsedAt" swaggertype:"string"`
+	ExpiresAt  primitive.DateTime  `bson:"expiresAt" json:"expiresAt" swaggertype:"string"`
+	RevokedAt  *primitive.DateTime `bson:"revokedAt,omitempty" json:"revokedAt,omitempty" swaggertype:"string"`
+}
+
+// SessionResponse 会话列表项
+type SessionResponse struct {
+	ID         string `json:"id"`
+	UserAgent  string `json:"userAgent"`
+	IP         string `json:"ip"`
+	CreatedAt  string `json:"createdAt"`
+	LastUsedAt string `json:"lastUsedAt"`
+	Current    bool   `json:"current"` // 是否为发起请求的当前会话
+}
+
+// SessionRepository 会话仓储接口
+type SessionRepository interface {
+	Create(c context.Context, session *Session) error
+	GetByID(c context.Context, id string) (Session, error)
+	GetActiveByUserID(c context.Context, userID string) ([]Session, error)
+	Touch(c context.Context, id string, expiresAt primitive.DateTime) error
+	Revoke(c context.Context, id string) error
+	RevokeByUserID(c context.Context, userID string) error
+}
+
+// SessionUsecase 会话用例接口
+type SessionUsecase interface {
+	Create(c context.Context, userID primitive.ObjectID, userAgent, ip string, expiry int) (Session, error)
+	GetActiveSessions(c context.Context, userID string) ([]Session, error)
+	Revoke(c context.Context, userID, sessionID string) error
+	IsActive(c context.Context, sessionID string) (bool, error)
+}
@@ -2,8 +2,13 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"strings"
 )
 
+// ErrUsernameInvalid 用户名形如邮箱或手机号
+var ErrUsernameInvalid = errors.New("username must not look like an email or phone number")
+
 type SignupRequest struct {
 	Username     string  `json:"username" binding:"required,min=4,max=20"`
 	Password     string  `json:"password" binding:"required,min=6,max=20"`
@@ -18,14 +23,26 @@ type SignupRequest struct {
 	FitnessGoal  string  `json:"fitnessGoal"`   // 增肌/减脂/力量提升/耐力提升/综合健身
 }
 
+// ValidateUsername 用户名不能包含 @ 或只由数字和 +、-、空格组成，
+// 否则会与其他账号的邮箱/手机号混淆，登录时用户名优先匹配而顶替对方
+func ValidateUsername(username string) error {
+	if strings.Contains(username, "@") || strings.Trim(username, "+- 0123456789") == "" {
+		return ErrUsernameInvalid
+	}
+	return nil
+}
+
 type SignupResponse struct {
-	Token string `json:"token"`
-	Role  string `json:"role"`
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	Role         string `json:"role"`
 }
 
 type SignupUsecase interface {
 	Create(c context.Context, user *User) error
 	GetUserByUsername(c context.Context, username string) (User, error)
-	CreateAccessToken(user *User, secret string, expiry int) (accessToken string, err error)
-	CreateRefreshToken(user *User, secret string, expiry int) (refreshToken string, err error)
+	// CheckContactAvailable 校验邮箱和手机号未被其他账号使用，空值跳过
+	CheckContactAvailable(c context.Context, email, phone string) error
+	CreateAccessToken(c context.Context, user *User, sessionID string, keys JwtKeySet, expiry int) (accessToken string, err error)
+	CreateRefreshToken(user *User, keys JwtKeySet, expiry int) (refreshToken string, err error)
 }
@@ -39,14 +39,6 @@ type MuscleGroupStats struct {
 	Percentage    int     `json:"percentage"`    // 占比百分比
 }
 
-// PersonalRecord 个人记录
-type PersonalRecord struct {
-	ExerciseName string  `json:"exerciseName"` // 训练项目名称
-	MaxWeight    float64 `json:"maxWeight"`    // 最大重量
-	Date         string  `json:"date"`         // 创建日期
-	RecordID     int     `json:"recordId"`     // 记录ID
-}
-
 // CalendarDay 日历天数据
 type CalendarDay struct {
 	Date          string `json:"date"`          // 日期 YYYY-MM-DD
@@ -55,6 +47,21 @@ type CalendarDay struct {
 	TotalDuration int    `json:"totalDuration"` // 总时长
 }
 
+// PlanTotals 计划关联训练记录的累计数据
+type PlanTotals struct {
+	TrainingCount int     `bson:"trainingCount" json:"trainingCount"`
+	TotalDuration int     `bson:"totalDuration" json:"totalDuration"`
+	TotalWeight   float64 `bson:"totalWeight" json:"totalWeight"`
+	TotalCalories int     `bson:"totalCalories" json:"totalCalories"`
+}
+
+// DayTotals 某一天（按用户时区）的训练记录汇总
+type DayTotals struct {
+	Date          string `bson:"date" json:"date"` // 日期 YYYY-MM-DD
+	TrainingCount int    `bson:"trainingCount" json:"trainingCount"`
+	TotalDuration int    `bson:"totalDuration" json:"totalDuration"`
+}
+
 // PlanStats 计划维度统计
 type PlanStats struct {
 	PlanID         string       `json:"planId"`
@@ -85,8 +92,10 @@ type PlanProgressSummary struct {
 type StatsUsecase interface {
 	GetTrainingStats(c context.Context, userID string, period, startDate, endDate string) (TrainingStats, error)
 	GetMuscleGroupStats(c context.Context, userID string, period string) ([]MuscleGroupStats, error)
-	GetPersonalRecords(c context.Context, userID string) ([]PersonalRecord, error)
+	GetPersonalRecords(c context.Context, userID, formula string) ([]PersonalRecord, error)
+	GetPersonalRecordHistory(c context.Context, userID, exercise string, page, pageSize int) ([]PersonalRecordEvent, int64, error)
 	GetCalendar(c context.Context, userID string, year, month int) ([]CalendarDay, error)
 	GetPlanStats(c context.Context, userID, planID, period string) (PlanStats, error)
 	GetPlanProgressList(c context.Context, userID, status string, page, pageSize int) ([]PlanProgressSummary, int64, error)
+	GetWeightProgress(c context.Context, userID string) (WeightProgress, error)
 }
@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -10,6 +12,13 @@ const (
 	CollectionTrainingRecord = "training_records"
 )
 
+var (
+	// ErrInvalidTrainingTime 训练开始/结束时间或查询日期格式错误
+	ErrInvalidTrainingTime = errors.New("invalid training time")
+	// ErrTrainingRecordNotFound 训练记录不存在
+	ErrTrainingRecordNotFound = errors.New("training record not found")
+)
+
 // SetDetail 组详情
 type SetDetail struct {
 	SetType     string   `bson:"setType" json:"setType"`         // 组类型：热身/正式/放松
@@ -22,7 +31,8 @@ type SetDetail struct {
 // Exercise 训练项目
 type Exercise struct {
 	ID          int          `bson:"id" json:"id"`
-	Name        string       `bson:"name" json:"name"`                                   // 项目名称(必填)
+	ExerciseID  string       `bson:"exerciseId,omitempty" json:"exerciseId,omitempty"`   // 动作库规范ID(可选)
+	Name        string       `bson:"name" json:"name"`                                   // 项目名称(必填，提供 exerciseId 时可省略)
 	Sets        *int         `bson:"sets,omitempty" json:"sets,omitempty"`               // 组数
 	Reps        *int         `bson:"reps,omitempty" json:"reps,omitempty"`               // 次数
 	Weight      *float64     `bson:"weight,omitempty" json:"weight,omitempty"`           // 重量(kg)
@@ -31,6 +41,17 @@ type Exercise struct {
 	Notes       *string      `bson:"notes,omitempty" json:"notes,omitempty"`             // 备注
 	Duration    *int         `bson:"duration,omitempty" json:"duration,omitempty"`       // 训练时长(分钟)
 	SetsData    []SetDetail  `bson:"setsData,omitempty" json:"setsData,omitempty"`       // 详细组数据
+	Volume      *float64     `bson:"volume,omitempty" json:"volume,omitempty"`           // 训练量(kg)，服务端按已完成的非热身组计算
+	WorkingSets *int         `bson:"workingSets,omitempty" json:"workingSets,omitempty"` // 已完成的非热身组数，服务端计算
+	MET         *float64     `bson:"met,omitempty" json:"met,omitempty"`                 // 代谢当量，未填写时取动作库，用于估算卡路里
+	Progression *ProgressionRule `bson:"progression,omitempty" json:"progression,omitempty"` // 进阶规则，仅模板和计划使用
+}
+
+// MuscleGroupVolume 单次训练中某个肌群的训练量
+type MuscleGroupVolume struct {
+	MuscleGroup string  `bson:"muscleGroup" json:"muscleGroup"` // 肌群
+	Volume      float64 `bson:"volume" json:"volume"`           // 训练量(kg)
+	Sets        int     `bson:"sets" json:"sets"`               // 已完成的非热身组数
 }
 
 // TrainingRecord 训练记录
@@ -38,13 +59,17 @@ type TrainingRecord struct {
 	ID               primitive.ObjectID  `bson:"_id" json:"id"`
 	UserID           primitive.ObjectID  `bson:"userId" json:"userId"`
 	Title            string              `bson:"title" json:"title"`                                               // 标题(必填)
-	StartTime        *string             `bson:"startTime,omitempty" json:"startTime,omitempty"`                   // 开始时间 YYYY-MM-DD HH:mm:ss
-	EndTime          *string             `bson:"endTime,omitempty" json:"endTime,omitempty"`                       // 结束时间 YYYY-MM-DD HH:mm:ss
+	StartTime        *time.Time          `bson:"startTime,omitempty" json:"startTime,omitempty"`                   // 开始时间，返回时按用户时区带偏移输出
+	EndTime          *time.Time          `bson:"endTime,omitempty" json:"endTime,omitempty"`                       // 结束时间
+	LegacyStartTime  string              `bson:"legacyStartTime,omitempty" json:"legacyStartTime,omitempty"`       // 迁移时无法解析的旧版开始时间原文
+	LegacyEndTime    string              `bson:"legacyEndTime,omitempty" json:"legacyEndTime,omitempty"`           // 迁移时无法解析的旧版结束时间原文
 	Duration         *int                `bson:"duration,omitempty" json:"duration,omitempty"`                     // 总时长(分钟)
 	Exercises        []Exercise          `bson:"exercises,omitempty" json:"exercises,omitempty"`                   // 训练项目列表
-	TotalWeight      *float64            `bson:"totalWeight,omitempty" json:"totalWeight,omitempty"`               // 总重量(kg)
-	TotalSets        *int                `bson:"totalSets,omitempty" json:"totalSets,omitempty"`                   // 总组数
-	CaloriesBurned   *int                `bson:"caloriesBurned,omitempty" json:"caloriesBurned,omitempty"`         // 消耗卡路里
+	TotalWeight      *float64            `bson:"totalWeight,omitempty" json:"totalWeight,omitempty"`               // 总重量(kg)，有组数据时由服务端计算
+	TotalSets        *int                `bson:"totalSets,omitempty" json:"totalSets,omitempty"`                   // 总组数，有组数据时由服务端计算
+	MuscleGroupVolumes []MuscleGroupVolume `bson:"muscleGroupVolumes,omitempty" json:"muscleGroupVolumes,omitempty"` // 各肌群训练量，服务端计算
+	CaloriesBurned   *int                `bson:"caloriesBurned,omitempty" json:"caloriesBurned,omitempty"`         // 消耗卡路里，未提交时由服务端估算
+	CaloriesEstimated bool               `bson:"caloriesEstimated,omitempty" json:"caloriesEstimated,omitempty"`   // caloriesBurned 是否为服务端估算值
 	Notes            *string             `bson:"notes,omitempty" json:"notes,omitempty"`                           // 训练备注
 	Mood             *string             `bson:"mood,omitempty" json:"mood,omitempty"`                             // 训练状态(优秀/良好/一般/疲劳)
 	PlanID           string              `bson:"planId,omitempty" json:"planId,omitempty"`             // 关联计划ID
@@ -58,21 +83,30 @@ type TrainingRecord struct {
 type TrainingRecordRepository interface {
 	Create(c context.Context, record *TrainingRecord) error
 	GetByID(c context.Context, id string) (TrainingRecord, error)
-	GetByUserID(c context.Context, userID string, page, pageSize int, startDate, endDate string, planID string) ([]TrainingRecord, int64, error)
+	// GetByUserID start/end 为开始时间的 [start, end) 区间，零值表示不限
+	GetByUserID(c context.Context, userID string, page, pageSize int, start, end time.Time, planID string) ([]TrainingRecord, int64, error)
 	Update(c context.Context, id string, record *TrainingRecord) error
 	Delete(c context.Context, id string) error
+	// SumByPlan 汇总计划在开始时间 [start, end) 内的训练记录，零值表示不限
+	SumByPlan(c context.Context, userID, planID string, start, end time.Time) (PlanTotals, error)
+	// SumByDay 按 location 时区的日期汇总开始时间在 [start, end) 内的训练记录，只返回有训练的日期并按日期排序
+	SumByDay(c context.Context, userID string, start, end time.Time, location *time.Location) ([]DayTotals, error)
+	// CountByPlanDay 统计关联到计划日的训练记录数
+	CountByPlanDay(c context.Context, planID string, dayNumber int) (int64, error)
+	// ClearPlanDay 解除训练记录与计划日的关联，记录仍计入计划
+	ClearPlanDay(c context.Context, planID string, dayNumber int) error
 }
 
 // CreateTrainingRecordRequest 创建训练记录请求
 type CreateTrainingRecordRequest struct {
 	Title            string     `json:"title" binding:"required"` // 标题(必填)
-	StartTime        *string    `json:"startTime,omitempty"`      // 开始时间 YYYY-MM-DD HH:mm:ss
-	EndTime          *string    `json:"endTime,omitempty"`        // 结束时间 YYYY-MM-DD HH:mm:ss
+	StartTime        *string    `json:"startTime,omitempty"`      // 开始时间 RFC3339，或按用户时区解释的 YYYY-MM-DD HH:mm:ss
+	EndTime          *string    `json:"endTime,omitempty"`        // 结束时间，格式同开始时间
 	Duration         *int       `json:"duration,omitempty"`       // 总时长(分钟)
 	Exercises        []Exercise `json:"exercises,omitempty"`      // 训练项目列表
-	TotalWeight      *float64   `json:"totalWeight,omitempty"`    // 总重量(kg)
-	TotalSets        *int       `json:"totalSets,omitempty"`      // 总组数
-	CaloriesBurned   *int       `json:"caloriesBurned,omitempty"` // 消耗卡路里
+	TotalWeight      *float64   `json:"totalWeight,omitempty"`    // 总重量(kg)，仅在没有组数据时使用
+	TotalSets        *int       `json:"totalSets,omitempty"`      // 总组数，仅在没有组数据时使用
+	CaloriesBurned   *int       `json:"caloriesBurned,omitempty"` // 消耗卡路里，未提交时由服务端估算
 	Notes            *string    `json:"notes,omitempty"`          // 训练备注
 	Mood             *string    `json:"mood,omitempty"`           // 训练状态
 	PlanID           *string    `json:"planId,omitempty"`         // 关联计划ID
@@ -83,13 +117,13 @@ type CreateTrainingRecordRequest struct {
 // UpdateTrainingRecordRequest 更新训练记录请求
 type UpdateTrainingRecordRequest struct {
 	Title            *string    `json:"title,omitempty"`          // 标题
-	StartTime        *string    `json:"startTime,omitempty"`      // 开始时间 YYYY-MM-DD HH:mm:ss
-	EndTime          *string    `json:"endTime,omitempty"`        // 结束时间 YYYY-MM-DD HH:mm:ss
+	StartTime        *string    `json:"startTime,omitempty"`      // 开始时间 RFC3339，或按用户时区解释的 YYYY-MM-DD HH:mm:ss
+	EndTime          *string    `json:"endTime,omitempty"`        // 结束时间，格式同开始时间
 	Duration         *int       `json:"duration,omitempty"`       // 总时长(分钟)
 	Exercises        []Exercise `json:"exercises,omitempty"`      // 训练项目列表
-	TotalWeight      *float64   `json:"totalWeight,omitempty"`    // 总重量(kg)
-	TotalSets        *int       `json:"totalSets,omitempty"`      // 总组数
-	CaloriesBurned   *int       `json:"caloriesBurned,omitempty"` // 消耗卡路里
+	TotalWeight      *float64   `json:"totalWeight,omitempty"`    // 总重量(kg)，仅在没有组数据时使用
+	TotalSets        *int       `json:"totalSets,omitempty"`      // 总组数，仅在没有组数据时使用
+	CaloriesBurned   *int       `json:"caloriesBurned,omitempty"` // 消耗卡路里，提交后不再由服务端估算
 	Notes            *string    `json:"notes,omitempty"`          // 训练备注
 	Mood             *string    `json:"mood,omitempty"`           // 训练状态
 	PlanID           *string    `json:"planId,omitempty"`         // 关联计划ID
@@ -102,6 +136,7 @@ type TrainingRecordUsecase interface {
 	Create(c context.Context, userID string, request *CreateTrainingRecordRequest) (map[string]interface{}, error)
 	GetByID(c context.Context, userID, recordID string) (TrainingRecord, error)
 	GetList(c context.Context, userID string, page, pageSize int, startDate, endDate string, planID string) ([]TrainingRecord, int64, error)
-	Update(c context.Context, userID, recordID string, request *UpdateTrainingRecordRequest) error
+	// Update 返回本次保存新打破的个人记录
+	Update(c context.Context, userID, recordID string, request *UpdateTrainingRecordRequest) ([]PersonalRecordEvent, error)
 	Delete(c context.Context, userID, recordID string) error
 }
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CollectionTrainingRollup = "training_rollups"
+)
+
+// 汇总粒度
+const (
+	RollupPeriodDay  = "day"
+	RollupPeriodWeek = "week" // 自然周，周一开始
+)
+
+// MuscleGroupRollup 汇总周期内某个肌群的训练情况
+type MuscleGroupRollup struct {
+	MuscleGroup   string  `bson:"muscleGroup" json:"muscleGroup"`
+	TrainingCount int     `bson:"trainingCount" json:"trainingCount"` // 训练项目次数
+	TotalWeight   float64 `bson:"totalWeight" json:"totalWeight"`     // 动作训练量之和
+}
+
+// ExerciseRollup 汇总周期内某个动作出现的次数
+type ExerciseRollup struct {
+	Key   string `bson:"key" json:"key"` // 动作库ID或规范化名称
+	Name  string `bson:"name" json:"name"`
+	Count int    `bson:"count" json:"count"`
+}
+
+// TrainingRollup 用户按日/按周的训练汇总，随训练记录的增删改更新，按开始时间所在日期归属
+type TrainingRollup struct {
+	ID            primitive.ObjectID  `bson:"_id,omitempty" json:"-"`
+	UserID        primitive.ObjectID  `bson:"userId" json:"-"`
+	Period        string              `bson:"period" json:"period"`           // day/week
+	PeriodStart   string              `bson:"periodStart" json:"periodStart"` // 周期第一天 YYYY-MM-DD
+	TrainingCount int                 `bson:"trainingCount" json:"trainingCount"`
+	Duration      int                 `bson:"duration" json:"duration"`
+	Weight        float64             `bson:"weight" json:"weight"`
+	Sets          int                 `bson:"sets" json:"sets"`
+	Calories      int                 `bson:"calories" json:"calories"`
+	MuscleGroups  []MuscleGroupRollup `bson:"muscleGroups" json:"muscleGroups"`
+	Exercises     []ExerciseRollup    `bson:"exercises" json:"exercises"`
+	UpdatedAt     primitive.DateTime  `bson:"updatedAt" json:"-"`
+}
+
+// TrainingRollupRepository 训练汇总仓储接口
+type TrainingRollupRepository interface {
+	// Upsert 按 userId+period+periodStart 写入汇总
+	Upsert(c context.Context, rollup *TrainingRollup) error
+	Delete(c context.Context, userID, period, periodStart string) error
+	DeleteByUserID(c context.Context, userID string) error
+	// GetRange 返回 periodStart 在 [start, end] 内的汇总，按日期升序
+	GetRange(c context.Context, userID, period, start, end string) ([]TrainingRollup, error)
+	// Summarize 合并 periodStart 在 [start, end] 内的汇总，肌群和动作按次数从多到少排列
+	Summarize(c context.Context, userID, period, start, end string) (TrainingRollup, error)
+}
+
+// TrainingRollupUsecase 训练汇总维护
+type TrainingRollupUsecase interface {
+	// Refresh 根据指定日期的训练记录重新计算这些日期及其所在周的汇总
+	Refresh(c context.Context, userID string, dates []string) error
+	// Rebuild 重新计算用户全部汇总
+	Rebuild(c context.Context, userID string) error
+	// RebuildAll 重新计算所有用户的汇总，返回处理的用户数
+	RebuildAll(c context.Context) (int, error)
+}
@@ -0,0 +1,15 @@
+package domain
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrTransactionsUnsupported MongoDB 部署不支持事务（单节点 mongod），且没有允许退化为无事务执行
+var ErrTransactionsUnsupported = errors.New("mongodb deployment does not support transactions")
+
+// Transactor 让多个仓储操作在同一个事务中提交或回滚
+type Transactor interface {
+	// WithTransaction 在事务中执行 fn，fn 返回错误时回滚。fn 中的仓储调用必须使用传入的 ctx
+	WithTransaction(c context.Context, fn func(ctx context.Context) error) error
+}
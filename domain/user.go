@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -11,23 +13,31 @@ const (
 )
 
 type User struct {
-	ID           primitive.ObjectID `bson:"_id" json:"id"`
-	Username     string             `bson:"username" json:"username"`
-	Password     string             `bson:"password" json:"-"` // 不在JSON中返回密码
-	Nickname     string             `bson:"nickname" json:"nickname,omitempty"`
-	AvatarUrl    string             `bson:"avatarUrl" json:"avatarUrl,omitempty"`
-	Email        string             `bson:"email" json:"email,omitempty"`
-	Phone        string             `bson:"phone" json:"phone,omitempty"`
-	Gender       string             `bson:"gender" json:"gender,omitempty"`       // 男/女
-	Age          int                `bson:"age" json:"age,omitempty"`
-	Height       float64            `bson:"height" json:"height,omitempty"`       // 身高(cm)
-	Weight       float64            `bson:"weight" json:"weight,omitempty"`       // 体重(kg)
-	TargetWeight float64            `bson:"targetWeight" json:"targetWeight,omitempty"` // 目标体重(kg)
-	FitnessGoal  string             `bson:"fitnessGoal" json:"fitnessGoal,omitempty"`   // 健身目标
-	Role         string             `bson:"role" json:"role"`                     // user/admin
-	JoinDate     string             `bson:"joinDate" json:"joinDate"`             // 加入日期 YYYY-MM-DD
-	CreatedAt    primitive.DateTime `bson:"createdAt" json:"-"`
-	UpdatedAt    primitive.DateTime `bson:"updatedAt" json:"-"`
+	ID                  primitive.ObjectID  `bson:"_id" json:"id"`
+	Username            string              `bson:"username" json:"username"`
+	Password            string              `bson:"password" json:"-"` // 不在JSON中返回密码
+	Nickname            string              `bson:"nickname" json:"nickname,omitempty"`
+	AvatarUrl           string              `bson:"avatarUrl" json:"avatarUrl,omitempty"`
+	AvatarKey           string              `bson:"avatarKey,omitempty" json:"-"` // 上传的头像在存储中的 key，使用外部头像地址时为空
+	Email               string              `bson:"email" json:"email,omitempty"`
+	EmailVerified       bool                `bson:"emailVerified" json:"emailVerified"` // 邮箱是否已验证，修改邮箱后重置
+	Phone               string              `bson:"phone" json:"phone,omitempty"`
+	Gender              string              `bson:"gender" json:"gender,omitempty"` // 男/女
+	Age                 int                 `bson:"age" json:"age,omitempty"`
+	Height              float64             `bson:"height" json:"height,omitempty"`                                                          // 身高(cm)
+	Weight              float64             `bson:"weight" json:"weight,omitempty"`                                                          // 体重(kg)
+	TargetWeight        float64             `bson:"targetWeight" json:"targetWeight,omitempty"`                                              // 目标体重(kg)
+	FitnessGoal         string              `bson:"fitnessGoal" json:"fitnessGoal,omitempty"`                                                // 健身目标
+	TimeZone            string              `bson:"timeZone,omitempty" json:"timeZone,omitempty"`                                            // IANA 时区，如 Asia/Shanghai，为空时使用服务默认时区
+	TrainingWeekdays    []int               `bson:"trainingWeekdays,omitempty" json:"trainingWeekdays,omitempty"`                            // 偏好的训练星期(1=周一…7=周日)，计划未单独设置时使用
+	Role                string              `bson:"role" json:"role"`                                                                        // user/admin
+	Roles               []string            `bson:"roles,omitempty" json:"roles,omitempty"`                                                  // 额外分配的角色，如 coach/content-editor/support
+	JoinDate            string              `bson:"joinDate" json:"joinDate"`                                                                // 加入日期 YYYY-MM-DD
+	Disabled            bool                `bson:"disabled,omitempty" json:"disabled"`                                                      // 是否被管理员禁用
+	DisabledAt          *primitive.DateTime `bson:"disabledAt,omitempty" json:"disabledAt,omitempty" swaggertype:"string"`                   // 禁用时间
+	DeletionScheduledAt *primitive.DateTime `bson:"deletionScheduledAt,omitempty" json:"deletionScheduledAt,omitempty" swaggertype:"string"` // 申请注销后的计划删除时间
+	CreatedAt           primitive.DateTime  `bson:"createdAt" json:"-"`
+	UpdatedAt           primitive.DateTime  `bson:"updatedAt" json:"-"`
 }
 
 type UserRepository interface {
@@ -35,6 +45,43 @@ type UserRepository interface {
 	Fetch(c context.Context) ([]User, error)
 	GetByEmail(c context.Context, email string) (User, error)
 	GetByUsername(c context.Context, username string) (User, error)
+	GetByPhone(c context.Context, phone string) (User, error)
+	// FindByIdentifier 查找用户名、邮箱或手机号等于 identifier 的用户，最多返回 limit 个
+	FindByIdentifier(c context.Context, identifier string, limit int) ([]User, error)
 	GetByID(c context.Context, id string) (User, error)
 	Update(c context.Context, id string, user *User) error
+	UpdatePassword(c context.Context, id string, hashedPassword string) error
+	// UpdateRoles 替换用户角色，并将 role 同步为 LegacyRole(roles)
+	UpdateRoles(c context.Context, id string, roles []string) error
+	// UpdateWeight 仅更新用户资料中的体重，0 表示清空
+	UpdateWeight(c context.Context, id string, weight float64) error
+	// Search 按关键字（用户名/昵称/邮箱/手机号）分页查询，不返回密码
+	Search(c context.Context, keyword string, page, pageSize int) ([]User, int64, error)
+	SetDisabled(c context.Context, id string, disabled bool) error
+	// SetEmailVerified 仅当用户邮箱仍为 email 时标记为已验证，邮箱已变更时返回 false
+	SetEmailVerified(c context.Context, id string, email string) (bool, error)
+	// ScheduleDeletion 设置计划删除时间，at 为 nil 时撤销
+	ScheduleDeletion(c context.Context, id string, at *time.Time) error
+	// GetDueForDeletion 获取计划删除时间早于 before 的用户
+	GetDueForDeletion(c context.Context, before time.Time, limit int) ([]User, error)
+	Delete(c context.Context, id string) error
+}
+
+// RoleNames 用户拥有的全部角色（Role 与 Roles 合并去重）
+func (u *User) RoleNames() []string {
+	names := []string{}
+	seen := map[string]bool{}
+	for _, name := range append([]string{u.Role}, u.Roles...) {
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// NormalizeEmail 邮箱统一去除首尾空白并转为小写，保证唯一索引按邮箱本身去重
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
 }
@@ -1,40 +1,57 @@
 package domain
 
-import "context"
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrInvalidTimeZone 不是有效的 IANA 时区名称
+var ErrInvalidTimeZone = errors.New("invalid time zone")
 
 // UserInfoResponse 用户信息响应
 type UserInfoResponse struct {
-	ID           int     `json:"id"`
-	Username     string  `json:"username"`
-	Nickname     string  `json:"nickname,omitempty"`
-	AvatarUrl    string  `json:"avatarUrl,omitempty"`
-	Email        string  `json:"email,omitempty"`
-	Phone        string  `json:"phone,omitempty"`
-	Gender       string  `json:"gender,omitempty"`
-	Age          int     `json:"age,omitempty"`
-	Height       float64 `json:"height,omitempty"`
-	Weight       float64 `json:"weight,omitempty"`
-	TargetWeight float64 `json:"targetWeight,omitempty"`
-	FitnessGoal  string  `json:"fitnessGoal,omitempty"`
-	JoinDate     string  `json:"joinDate"`
+	ID                  int     `json:"id"`
+	Username            string  `json:"username"`
+	Nickname            string  `json:"nickname,omitempty"`
+	AvatarUrl           string  `json:"avatarUrl,omitempty"`
+	Email               string  `json:"email,omitempty"`
+	EmailVerified       bool    `json:"emailVerified"`
+	Phone               string  `json:"phone,omitempty"`
+	Gender              string  `json:"gender,omitempty"`
+	Age                 int     `json:"age,omitempty"`
+	Height              float64 `json:"height,omitempty"`
+	Weight              float64 `json:"weight,omitempty"`
+	TargetWeight        float64 `json:"targetWeight,omitempty"`
+	FitnessGoal         string  `json:"fitnessGoal,omitempty"`
+	TimeZone            string  `json:"timeZone"`         // 用户时区，未设置时为服务默认时区
+	TrainingWeekdays    []int   `json:"trainingWeekdays"` // 偏好的训练星期(1=周一…7=周日)
+	JoinDate            string  `json:"joinDate"`
+	DeletionScheduledAt string  `json:"deletionScheduledAt,omitempty"` // 已申请注销时的计划删除时间
 }
 
 // UpdateUserInfoRequest 更新用户信息请求
 type UpdateUserInfoRequest struct {
-	Nickname     string  `json:"nickname"`
-	AvatarUrl    string  `json:"avatarUrl"`
-	Email        string  `json:"email"`
-	Phone        string  `json:"phone"`
-	Gender       string  `json:"gender"`
-	Age          int     `json:"age"`
-	Height       float64 `json:"height"`
-	Weight       float64 `json:"weight"`
-	TargetWeight float64 `json:"targetWeight"`
-	FitnessGoal  string  `json:"fitnessGoal"`
+	Nickname         string  `json:"nickname"`
+	AvatarUrl        string  `json:"avatarUrl"`
+	Email            string  `json:"email"`
+	Phone            string  `json:"phone"`
+	Gender           string  `json:"gender"`
+	Age              int     `json:"age"`
+	Height           float64 `json:"height"`
+	Weight           float64 `json:"weight"`
+	TargetWeight     float64 `json:"targetWeight"`
+	FitnessGoal      string  `json:"fitnessGoal"`
+	TimeZone         string  `json:"timeZone"`         // IANA 时区，如 Asia/Shanghai
+	TrainingWeekdays []int   `json:"trainingWeekdays"` // 偏好的训练星期(1=周一…7=周日)，不传时不修改，传空数组时清除
 }
 
 // UserInfoUsecase 用户信息用例接口
 type UserInfoUsecase interface {
 	GetUserInfo(c context.Context, userID string) (User, error)
 	UpdateUserInfo(c context.Context, userID string, request *UpdateUserInfoRequest) error
+	// UploadAvatar 将上传的图片裁剪缩放为正方形头像保存，返回新的头像地址
+	UploadAvatar(c context.Context, userID string, file io.Reader) (string, error)
+	// OpenAvatar 读取用户上传的头像，调用方负责关闭
+	OpenAvatar(c context.Context, userID string) (io.ReadCloser, BlobInfo, error)
 }
@@ -0,0 +1,54 @@
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+const (
+	TypeFileSystem = "filesystem"
+	TypeS3         = "s3"
+)
+
+// errInvalidKey key 为空、以 / 开头或包含 .. 等路径片段
+var errInvalidKey = errors.New("invalid blob key")
+
+// S3Config S3 兼容存储（AWS S3、MinIO 等）的连接配置
+type S3Config struct {
+	Endpoint  string // 如 https://s3.us-east-1.amazonaws.com 或 http://localhost:9000
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+}
+
+// New 根据配置创建文件存储，未知类型或 S3 缺少地址、存储桶时返回错误，
+// 以免上传的文件悄悄写到本地磁盘
+func New(storeType, dir string, s3 S3Config) (domain.BlobStore, error) {
+	switch storeType {
+	case "", TypeFileSystem:
+		return NewFileSystemStore(dir), nil
+	case TypeS3:
+		if s3.Endpoint == "" || s3.Bucket == "" {
+			return nil, errors.New("blob store s3 requires S3_ENDPOINT and S3_BUCKET")
+		}
+		return NewS3Store(s3), nil
+	default:
+		return nil, fmt.Errorf("unknown blob store %q", storeType)
+	}
+}
+
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package blobstore
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// fileSystemStore 将文件保存在本地目录，内容类型由扩展名推断
+type fileSystemStore struct {
+	root string
+}
+
+func NewFileSystemStore(root string) domain.BlobStore {
+	return &fileSystemStore{root: root}
+}
+
+func (fss *fileSystemStore) Put(c context.Context, key string, data []byte, contentType string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+
+	name := fss.path(key)
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+
+	// 先写临时文件再重命名，读取方不会看到写了一半的文件
+	tmp, err := os.CreateTemp(filepath.Dir(name), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), name)
+}
+
+func (fss *fileSystemStore) Get(c context.Context, key string) (io.ReadCloser, domain.BlobInfo, error) {
+	if !validKey(key) {
+		return nil, domain.BlobInfo{}, domain.ErrBlobNotFound
+	}
+
+	file, err := os.Open(fss.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, domain.BlobInfo{}, domain.ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, domain.BlobInfo{}, err
+	}
+
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, domain.BlobInfo{}, err
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return file, domain.BlobInfo{ContentType: contentType, Size: stat.Size()}, nil
+}
+
+func (fss *fileSystemStore) Delete(c context.Context, key string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+
+	err := os.Remove(fss.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (fss *fileSystemStore) DeletePrefix(c context.Context, prefix string) error {
+	if prefix == "" {
+		return errInvalidKey
+	}
+
+	// 前缀以 / 结尾时直接删除整个目录，否则逐个比较同目录下的文件名
+	if strings.HasSuffix(prefix, "/") {
+		if !validKey(strings.TrimSuffix(prefix, "/")) {
+			return errInvalidKey
+		}
+		return os.RemoveAll(fss.path(prefix))
+	}
+
+	if !validKey(prefix) {
+		return errInvalidKey
+	}
+	dir, base := path.Split(prefix)
+	entries, err := os.ReadDir(fss.path(dir))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), base) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(fss.path(dir), entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fss *fileSystemStore) path(key string) string {
+	return filepath.Join(fss.root, filepath.FromSlash(key))
+}
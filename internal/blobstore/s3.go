@@ -0,0 +1,280 @@
+package blobstore
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// emptyPayloadHash 空请求体的 SHA-256
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// s3Store 通过 S3 REST API 读写对象，使用路径风格地址 {endpoint}/{bucket}/{key} 和 SigV4 签名，
+// 兼容 AWS S3 以及 MinIO 等 S3 兼容存储
+type s3Store struct {
+	config S3Config
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Store(config S3Config) domain.BlobStore {
+	if config.Region == "" {
+		config.Region = "us-east-1"
+	}
+	config.Endpoint = strings.TrimSuffix(config.Endpoint, "/")
+	return &s3Store{
+		config: config,
+		client: &http.Client{Timeout: time.Minute},
+		now:    time.Now,
+	}
+}
+
+func (ss *s3Store) Put(c context.Context, key string, data []byte, contentType string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodPut, ss.objectURL(key), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	sum := sha256.Sum256(data)
+
+	resp, err := ss.do(req, hex.EncodeToString(sum[:]))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return checkResponse(resp)
+}
+
+func (ss *s3Store) Get(c context.Context, key string) (io.ReadCloser, domain.BlobInfo, error) {
+	if !validKey(key) {
+		return nil, domain.BlobInfo{}, domain.ErrBlobNotFound
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodGet, ss.objectURL(key), nil)
+	if err != nil {
+		return nil, domain.BlobInfo{}, err
+	}
+
+	resp, err := ss.do(req, emptyPayloadHash)
+	if err != nil {
+		return nil, domain.BlobInfo{}, err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, domain.BlobInfo{}, domain.ErrBlobNotFound
+	}
+	if err := checkResponse(resp); err != nil {
+		resp.Body.Close()
+		return nil, domain.BlobInfo{}, err
+	}
+
+	return resp.Body, domain.BlobInfo{ContentType: resp.Header.Get("Content-Type"), Size: resp.ContentLength}, nil
+}
+
+func (ss *s3Store) Delete(c context.Context, key string) error {
+	if !validKey(key) {
+		return errInvalidKey
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodDelete, ss.objectURL(key), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ss.do(req, emptyPayloadHash)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	// 删除不存在的对象 S3 也返回 204
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	return checkResponse(resp)
+}
+
+func (ss *s3Store) DeletePrefix(c context.Context, prefix string) error {
+	if prefix == "" {
+		return errInvalidKey
+	}
+
+	// 逐页列出前缀下的对象后逐个删除
+	token := ""
+	for {
+		result, err := ss.list(c, prefix, token)
+		if err != nil {
+			return err
+		}
+		for _, object := range result.Contents {
+			if err := ss.Delete(c, object.Key); err != nil {
+				return err
+			}
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return nil
+		}
+		token = result.NextContinuationToken
+	}
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// list 调用 ListObjectsV2 列出前缀下的一页对象
+func (ss *s3Store) list(c context.Context, prefix, token string) (listBucketResult, error) {
+	var result listBucketResult
+
+	query := url.Values{}
+	query.Set("list-type", "2")
+	query.Set("prefix", prefix)
+	if token != "" {
+		query.Set("continuation-token", token)
+	}
+
+	req, err := http.NewRequestWithContext(c, http.MethodGet, ss.config.Endpoint+"/"+escapePath(ss.config.Bucket)+"?"+query.Encode(), nil)
+	if err != nil {
+		return result, err
+	}
+
+	resp, err := ss.do(req, emptyPayloadHash)
+	if err != nil {
+		return result, err
+	}
+	defer resp.Body.Close()
+	if err := checkResponse(resp); err != nil {
+		return result, err
+	}
+
+	err = xml.NewDecoder(resp.Body).Decode(&result)
+	return result, err
+}
+
+func (ss *s3Store) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	ss.sign(req, payloadHash, ss.now())
+	return ss.client.Do(req)
+}
+
+func (ss *s3Store) objectURL(key string) string {
+	return ss.config.Endpoint + "/" + escapePath(ss.config.Bucket) + "/" + escapePath(key)
+}
+
+// sign 按 AWS Signature Version 4 为请求添加 x-amz-date、x-amz-content-sha256 和 Authorization 头，
+// 请求中已有的头全部参与签名
+func (ss *s3Store) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		escapePath(req.URL.Path),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + ss.config.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+ss.config.SecretKey), date)
+	key = hmacSHA256(key, ss.config.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		ss.config.AccessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := []string{}
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, escape(key, true)+"="+escape(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+func escapePath(path string) string {
+	return escape(path, false)
+}
+
+// escape 除 A-Z a-z 0-9 - _ . ~ 外全部百分号编码，路径中的 / 保留
+func escape(value string, encodeSlash bool) string {
+	var builder strings.Builder
+	for _, b := range []byte(value) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			builder.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			builder.WriteByte(b)
+		default:
+			fmt.Fprintf(&builder, "%%%02X", b)
+		}
+	}
+	return builder.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func checkResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s: %s %s", resp.Request.Method, resp.Request.URL.Path, resp.Status, strings.TrimSpace(string(body)))
+}
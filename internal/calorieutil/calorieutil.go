@@ -0,0 +1,116 @@
+package calorieutil
+
+import (
+	"math"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// DefaultMET 未关联动作库、类别未知的动作使用的代谢当量，取中等强度抗阻训练
+const DefaultMET = 5.0
+
+// restingVO2 标准静息耗氧量(ml/kg/min)，即 1 MET
+const restingVO2 = 3.5
+
+// categoryMET 各类别的默认代谢当量，参考 Compendium of Physical Activities
+var categoryMET = map[string]float64{
+	domain.ExerciseCategoryStrength:    5.0,
+	domain.ExerciseCategoryCardio:      7.0,
+	domain.ExerciseCategoryHIIT:        8.0,
+	domain.ExerciseCategoryFlexibility: 2.5,
+}
+
+// CategoryMET 类别的默认代谢当量
+func CategoryMET(category string) float64 {
+	if met, ok := categoryMET[category]; ok {
+		return met
+	}
+	return DefaultMET
+}
+
+// CatalogMET 动作库条目的代谢当量，未单独设置时按类别取默认值
+func CatalogMET(entry domain.CatalogExercise) float64 {
+	if entry.MET > 0 {
+		return entry.MET
+	}
+	return CategoryMET(entry.Category)
+}
+
+// Estimate 按 MET × 体重 × 时长估算训练消耗的卡路里。
+// 填写了时长的动作按各自时长计算，记录总时长的剩余部分按其余动作的平均 MET 计算；
+// 身高、年龄、性别齐全时用 Mifflin-St Jeor 静息代谢修正 MET。没有体重或时长时无法估算。
+func Estimate(user domain.User, record domain.TrainingRecord) (int, bool) {
+	if user.Weight <= 0 {
+		return 0, false
+	}
+
+	total := recordMinutes(record)
+	timedMinutes := 0.0
+	metMinutes := 0.0
+	untimedMET := 0.0
+	untimed := 0
+	for _, exercise := range record.Exercises {
+		met := DefaultMET
+		if exercise.MET != nil && *exercise.MET > 0 {
+			met = *exercise.MET
+		}
+		if exercise.Duration != nil && *exercise.Duration > 0 {
+			timedMinutes += float64(*exercise.Duration)
+			metMinutes += met * float64(*exercise.Duration)
+			continue
+		}
+		untimedMET += met
+		untimed++
+	}
+
+	if remaining := total - timedMinutes; remaining > 0 {
+		switch {
+		case untimed > 0:
+			metMinutes += untimedMET / float64(untimed) * remaining
+		case len(record.Exercises) == 0:
+			metMinutes += DefaultMET * remaining
+		}
+	}
+	if metMinutes <= 0 {
+		return 0, false
+	}
+
+	// 每分钟消耗 = MET × 3.5 × 体重(kg) / 200
+	calories := metMinutes * metCorrection(user) * restingVO2 * user.Weight / 200
+	return int(math.Round(calories)), true
+}
+
+// recordMinutes 记录的总时长，未填写时用开始和结束时间计算
+func recordMinutes(record domain.TrainingRecord) float64 {
+	if record.Duration != nil && *record.Duration > 0 {
+		return float64(*record.Duration)
+	}
+	if record.StartTime != nil && record.EndTime != nil && record.EndTime.After(*record.StartTime) {
+		return record.EndTime.Sub(*record.StartTime).Minutes()
+	}
+	return 0
+}
+
+// metCorrection 标准 MET 假设静息耗氧量为 3.5 ml/kg/min，按用户估算的静息代谢修正
+func metCorrection(user domain.User) float64 {
+	if user.Height <= 0 || user.Age <= 0 {
+		return 1
+	}
+
+	bmr := 10*user.Weight + 6.25*user.Height - 5*float64(user.Age)
+	switch user.Gender {
+	case "男":
+		bmr += 5
+	case "女":
+		bmr -= 161
+	default:
+		return 1
+	}
+
+	// 每升氧气约 5 kcal
+	vo2 := bmr / 1440 / 5 * 1000 / user.Weight
+	if vo2 <= 0 {
+		return 1
+	}
+	return restingVO2 / vo2
+}
@@ -0,0 +1,146 @@
+package calorieutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestCatalogMET(t *testing.T) {
+	assert.Equal(t, 9.5, CatalogMET(domain.CatalogExercise{MET: 9.5, Category: domain.ExerciseCategoryCardio}))
+	assert.Equal(t, 7.0, CatalogMET(domain.CatalogExercise{Category: domain.ExerciseCategoryCardio}))
+	assert.Equal(t, DefaultMET, CatalogMET(domain.CatalogExercise{Category: "unknown"}))
+}
+
+func TestEstimate(t *testing.T) {
+	start := time.Date(2025, 11, 3, 18, 0, 0, 0, time.UTC)
+	end := start.Add(45 * time.Minute)
+	// 70kg 时每 MET·分钟消耗 3.5 × 70 / 200 = 1.225 kcal
+	user := domain.User{Weight: 70}
+
+	tests := []struct {
+		name   string
+		user   domain.User
+		record domain.TrainingRecord
+		want   int
+		ok     bool
+	}{
+		{
+			name: "timed and untimed exercises",
+			user: user,
+			// 20 分钟 × 8 MET，其余 40 分钟按 (4 + 5) / 2 MET，共 340 MET·分钟
+			record: domain.TrainingRecord{
+				Duration: intPtr(60),
+				Exercises: []domain.Exercise{
+					{MET: floatPtr(8), Duration: intPtr(20)},
+					{MET: floatPtr(4)},
+					{},
+				},
+			},
+			want: 417,
+			ok:   true,
+		},
+		{
+			name: "timed exercises only ignore remaining minutes",
+			user: user,
+			record: domain.TrainingRecord{
+				Duration:  intPtr(60),
+				Exercises: []domain.Exercise{{MET: floatPtr(8), Duration: intPtr(20)}},
+			},
+			want: 196,
+			ok:   true,
+		},
+		{
+			name: "timed exercises longer than the record",
+			user: user,
+			record: domain.TrainingRecord{
+				Duration: intPtr(60),
+				Exercises: []domain.Exercise{
+					{MET: floatPtr(6), Duration: intPtr(30)},
+					{MET: floatPtr(3), Duration: intPtr(40)},
+					{MET: floatPtr(10)},
+				},
+			},
+			want: 368,
+			ok:   true,
+		},
+		{
+			name:   "no exercises use default MET",
+			user:   user,
+			record: domain.TrainingRecord{Duration: intPtr(30)},
+			want:   184,
+			ok:     true,
+		},
+		{
+			name: "duration from start and end time",
+			user: user,
+			record: domain.TrainingRecord{
+				StartTime: &start,
+				EndTime:   &end,
+				Exercises: []domain.Exercise{{MET: floatPtr(6)}},
+			},
+			want: 331,
+			ok:   true,
+		},
+		{
+			name: "corrected by resting metabolic rate",
+			user: domain.User{Weight: 70, Height: 175, Age: 30, Gender: "男"},
+			record: domain.TrainingRecord{
+				Duration: intPtr(60),
+				Exercises: []domain.Exercise{
+					{MET: floatPtr(8), Duration: intPtr(20)},
+					{MET: floatPtr(4)},
+					{},
+				},
+			},
+			want: 446,
+			ok:   true,
+		},
+		{
+			name:   "no weight",
+			user:   domain.User{},
+			record: domain.TrainingRecord{Duration: intPtr(60)},
+		},
+		{
+			name:   "no duration",
+			user:   user,
+			record: domain.TrainingRecord{Exercises: []domain.Exercise{{MET: floatPtr(6)}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calories, ok := Estimate(tt.user, tt.record)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.want, calories)
+		})
+	}
+}
+
+func TestMETCorrection(t *testing.T) {
+	tests := []struct {
+		name string
+		user domain.User
+		want float64
+	}{
+		// 静息代谢 1648.75 kcal/天，耗氧量约 3.271 ml/kg/min
+		{"male", domain.User{Weight: 70, Height: 175, Age: 30, Gender: "男"}, 1.0699},
+		// 静息代谢 1320.25 kcal/天，耗氧量约 3.056 ml/kg/min
+		{"female", domain.User{Weight: 60, Height: 165, Age: 30, Gender: "女"}, 1.1452},
+		{"no height", domain.User{Weight: 70, Age: 30, Gender: "男"}, 1},
+		{"no age", domain.User{Weight: 70, Height: 175, Gender: "男"}, 1},
+		{"no gender", domain.User{Weight: 70, Height: 175, Age: 30}, 1},
+		{"non-positive resting rate", domain.User{Weight: 1, Height: 1, Age: 100, Gender: "女"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, metCorrection(tt.user), 1e-4)
+		})
+	}
+}
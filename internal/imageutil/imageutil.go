@@ -0,0 +1,274 @@
+package imageutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+
+	// maxPixels 解码前按文件头检查像素数，避免小文件解码出超大图片耗尽内存
+	maxPixels = 40_000_000
+
+	jpegQuality      = 90
+	thumbnailQuality = 80
+)
+
+// Image 编码后的图片
+type Image struct {
+	Data        []byte
+	ContentType string
+	Width       int
+	Height      int
+}
+
+// Picture 解码后的图片，已按 EXIF 方向摆正
+type Picture struct {
+	img         *image.RGBA
+	contentType string
+}
+
+// Decode 校验并解码上传的图片，仅支持 JPEG 和 PNG，格式按文件内容判断而不是文件名
+func Decode(data []byte) (Picture, error) {
+	img, contentType, err := decode(data)
+	if err != nil {
+		return Picture{}, err
+	}
+	return Picture{img: img, contentType: contentType}, nil
+}
+
+// Encode 按原格式重新编码，不再包含拍摄位置等 EXIF 元数据
+func (p Picture) Encode() (Image, error) {
+	return encodeImage(p.img, p.contentType, jpegQuality)
+}
+
+// Thumbnail 生成长边不超过 size 的 JPEG 缩略图，square 为 true 时先居中裁成正方形
+func (p Picture) Thumbnail(size int, square bool) (Image, error) {
+	img := p.img
+	if square {
+		img = cropSquare(img)
+	}
+	return encodeImage(resize(img, size), ContentTypeJPEG, thumbnailQuality)
+}
+
+// decode 解码为 RGBA 并按 EXIF 方向旋转
+func decode(data []byte) (*image.RGBA, string, error) {
+	contentType := http.DetectContentType(data)
+	if contentType != ContentTypeJPEG && contentType != ContentTypePNG {
+		return nil, "", domain.ErrUnsupportedImage
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", domain.ErrUnsupportedImage
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return nil, "", domain.ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxPixels {
+		return nil, "", domain.ErrImageTooLarge
+	}
+
+	var img image.Image
+	if contentType == ContentTypeJPEG {
+		img, err = jpeg.Decode(bytes.NewReader(data))
+	} else {
+		img, err = png.Decode(bytes.NewReader(data))
+	}
+	if err != nil {
+		return nil, "", domain.ErrUnsupportedImage
+	}
+
+	bounds := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(rgba, rgba.Bounds(), img, bounds.Min, draw.Src)
+
+	if contentType == ContentTypeJPEG {
+		rgba = orient(rgba, jpegOrientation(data))
+	}
+	return rgba, contentType, nil
+}
+
+func encodeImage(img *image.RGBA, contentType string, quality int) (Image, error) {
+	var buf bytes.Buffer
+	var err error
+	if contentType == ContentTypePNG {
+		err = png.Encode(&buf, img)
+	} else {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return Image{}, err
+	}
+
+	bounds := img.Bounds()
+	return Image{Data: buf.Bytes(), ContentType: contentType, Width: bounds.Dx(), Height: bounds.Dy()}, nil
+}
+
+func cropSquare(img *image.RGBA) *image.RGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	side := width
+	if height < side {
+		side = height
+	}
+	offset := image.Pt((width-side)/2, (height-side)/2)
+
+	square := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(square, square.Bounds(), img, offset, draw.Src)
+	return square
+}
+
+// resize 按区域平均缩小到长边不超过 size，图片本来就更小时不放大
+func resize(img *image.RGBA, size int) *image.RGBA {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	dstWidth, dstHeight := size, height*size/width
+	if height > width {
+		dstWidth, dstHeight = width*size/height, size
+	}
+	if dstWidth < 1 {
+		dstWidth = 1
+	}
+	if dstHeight < 1 {
+		dstHeight = 1
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < dstHeight; y++ {
+		y0, y1 := y*height/dstHeight, (y+1)*height/dstHeight
+		for x := 0; x < dstWidth; x++ {
+			x0, x1 := x*width/dstWidth, (x+1)*width/dstWidth
+
+			var r, g, b, a, count int
+			for sy := y0; sy < y1; sy++ {
+				i := img.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += int(img.Pix[i])
+					g += int(img.Pix[i+1])
+					b += int(img.Pix[i+2])
+					a += int(img.Pix[i+3])
+					count++
+					i += 4
+				}
+			}
+
+			o := dst.PixOffset(x, y)
+			dst.Pix[o] = uint8(r / count)
+			dst.Pix[o+1] = uint8(g / count)
+			dst.Pix[o+2] = uint8(b / count)
+			dst.Pix[o+3] = uint8(a / count)
+		}
+	}
+	return dst
+}
+
+// orient 按 EXIF Orientation(1-8) 把图片变换为正常方向
+func orient(img *image.RGBA, orientation int) *image.RGBA {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	// 5-8 需要转置，宽高互换
+	dstWidth, dstHeight := width, height
+	if orientation >= 5 {
+		dstWidth, dstHeight = height, width
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dstWidth, dstHeight))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2:
+				dx, dy = width-1-x, y
+			case 3:
+				dx, dy = width-1-x, height-1-y
+			case 4:
+				dx, dy = x, height-1-y
+			case 5:
+				dx, dy = y, x
+			case 6:
+				dx, dy = height-1-y, x
+			case 7:
+				dx, dy = height-1-y, width-1-x
+			case 8:
+				dx, dy = y, width-1-x
+			}
+			copy(dst.Pix[dst.PixOffset(dx, dy):dst.PixOffset(dx, dy)+4], img.Pix[img.PixOffset(x, y):img.PixOffset(x, y)+4])
+		}
+	}
+	return dst
+}
+
+// jpegOrientation 读取 JPEG APP1 段中 EXIF 的 Orientation 标签，没有或无法解析时返回 1
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return 1
+		}
+		marker := data[i+1]
+		// 到达图像数据，之后不会再有 EXIF
+		if marker == 0xDA || marker == 0xD9 {
+			return 1
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2 : i+4]))
+		if length < 2 || i+2+length > len(data) {
+			return 1
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && len(segment) > 6 && string(segment[:6]) == "Exif\x00\x00" {
+			return tiffOrientation(segment[6:])
+		}
+		i += 2 + length
+	}
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	offset := int(order.Uint32(tiff[4:8]))
+	if offset+2 > len(tiff) {
+		return 1
+	}
+	count := int(order.Uint16(tiff[offset : offset+2]))
+	for n := 0; n < count; n++ {
+		entry := offset + 2 + n*12
+		if entry+12 > len(tiff) {
+			return 1
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 1
+}
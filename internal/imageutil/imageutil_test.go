@@ -0,0 +1,96 @@
+package imageutil
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+func testImage(width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	return img
+}
+
+// withOrientation 在 SOI 之后插入只含 Orientation 标签的 EXIF APP1 段
+func withOrientation(data []byte, orientation byte) []byte {
+	tiff := []byte{
+		'M', 'M', 0, 42, 0, 0, 0, 8, // 大端，IFD0 偏移 8
+		0, 1, // 1 个条目
+		0x01, 0x12, 0, 3, 0, 0, 0, 1, 0, orientation, 0, 0,
+		0, 0, 0, 0, // 没有下一个 IFD
+	}
+	segment := append([]byte("Exif\x00\x00"), tiff...)
+	length := len(segment) + 2
+
+	result := []byte{0xFF, 0xD8, 0xFF, 0xE1, byte(length >> 8), byte(length)}
+	result = append(result, segment...)
+	return append(result, data[2:]...)
+}
+
+func TestDecodeAppliesExifOrientation(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, testImage(40, 20), nil))
+
+	picture, err := Decode(withOrientation(buf.Bytes(), 6))
+	require.NoError(t, err)
+
+	encoded, err := picture.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJPEG, encoded.ContentType)
+	assert.Equal(t, 20, encoded.Width)
+	assert.Equal(t, 40, encoded.Height)
+	assert.Equal(t, 1, jpegOrientation(encoded.Data), "re-encoded image carries no EXIF")
+}
+
+func TestThumbnail(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(300, 200)))
+
+	picture, err := Decode(buf.Bytes())
+	require.NoError(t, err)
+
+	thumbnail, err := picture.Thumbnail(60, false)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJPEG, thumbnail.ContentType)
+	assert.Equal(t, 60, thumbnail.Width)
+	assert.Equal(t, 40, thumbnail.Height)
+
+	square, err := picture.Thumbnail(50, true)
+	require.NoError(t, err)
+	assert.Equal(t, 50, square.Width)
+	assert.Equal(t, 50, square.Height)
+
+	// 小图不放大
+	small, err := picture.Thumbnail(1000, false)
+	require.NoError(t, err)
+	assert.Equal(t, 300, small.Width)
+}
+
+func TestDecodeRejectsUnsupportedContent(t *testing.T) {
+	_, err := Decode([]byte("GIF89a not really"))
+	assert.ErrorIs(t, err, domain.ErrUnsupportedImage)
+
+	// 文件头声明的像素数超过上限时不解码
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(1, 1)))
+	data := buf.Bytes()
+	// IHDR 中的宽高位于第 16-23 字节，改写后重新计算块的 CRC
+	copy(data[16:24], []byte{0, 0, 0x27, 0x10, 0, 0, 0x27, 0x10})
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	_, err = Decode(data)
+	assert.ErrorIs(t, err, domain.ErrImageTooLarge)
+}
@@ -0,0 +1,71 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+const (
+	TypeLog  = "log"
+	TypeFile = "file"
+)
+
+// New 根据配置创建通知器，未知类型回退为日志通知器
+func New(notifierType string, filePath string) domain.Notifier {
+	if notifierType == TypeFile && filePath != "" {
+		return NewFileNotifier(filePath)
+	}
+	return NewLogNotifier()
+}
+
+// logNotifier 将通知打印到标准日志，用于本地开发
+type logNotifier struct{}
+
+func NewLogNotifier() domain.Notifier {
+	return &logNotifier{}
+}
+
+func (ln *logNotifier) Send(c context.Context, notification domain.Notification) error {
+	log.Printf("[notifier] to=%s subject=%s\n%s", notification.To, notification.Subject, notification.Body)
+	return nil
+}
+
+// fileNotifier 将通知以 JSON 行追加写入文件，便于本地调试和测试读取
+type fileNotifier struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileNotifier(path string) domain.Notifier {
+	return &fileNotifier{path: path}
+}
+
+func (fn *fileNotifier) Send(c context.Context, notification domain.Notification) error {
+	line, err := json.Marshal(struct {
+		To      string    `json:"to"`
+		Subject string    `json:"subject"`
+		Body    string    `json:"body"`
+		SentAt  time.Time `json:"sentAt"`
+	}{notification.To, notification.Subject, notification.Body, time.Now()})
+	if err != nil {
+		return err
+	}
+
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+
+	f, err := os.OpenFile(fn.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
@@ -0,0 +1,232 @@
+package progressutil
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/prutil"
+)
+
+// defaultDeloadFactor 没有指定减载比例时减载周使用的重量比例
+const defaultDeloadFactor = 0.7
+
+// Valid 校验进阶规则的参数
+func Valid(rule domain.ProgressionRule) bool {
+	if rule.DeloadEvery < 0 || rule.DeloadEvery == 1 || rule.DeloadFactor < 0 || rule.DeloadFactor >= 1 {
+		return false
+	}
+
+	switch rule.Type {
+	case domain.ProgressionLinear:
+		return rule.Increment > 0
+	case domain.ProgressionDouble:
+		return rule.Increment > 0 && rule.MinReps >= 1 && rule.MinReps < rule.MaxReps
+	case domain.ProgressionWave:
+		if len(rule.Percentages) == 0 || rule.Increment < 0 {
+			return false
+		}
+		for _, percentage := range rule.Percentages {
+			if percentage <= 0 || percentage > 100 {
+				return false
+			}
+		}
+		if len(rule.WaveReps) == 0 {
+			return true
+		}
+		if len(rule.WaveReps) != len(rule.Percentages) {
+			return false
+		}
+		for _, reps := range rule.WaveReps {
+			if reps <= 0 {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// Performance 用户在计划某一周完成某个动作的成绩，取最重的一组
+type Performance struct {
+	Week   int     // 训练所属的计划周
+	Date   string  // 训练日期 YYYY-MM-DD
+	Weight float64 // 最重一组的重量
+	Reps   int     // 该重量下完成的最多次数
+}
+
+// History 按动作归并计划关联训练记录中的成绩，按计划周和日期排序。
+// week 返回记录关联的计划日所属的计划周，没有关联计划日的记录不计入
+func History(records []domain.TrainingRecord, week func(dayNumber int) int, location *time.Location) map[string][]Performance {
+	history := make(map[string][]Performance)
+	for i := range records {
+		record := &records[i]
+		if record.PlanDayID == nil {
+			continue
+		}
+
+		for _, exercise := range record.Exercises {
+			performance := Performance{
+				Week: week(*record.PlanDayID),
+				Date: prutil.RecordDate(record, location),
+			}
+			for _, set := range prutil.CompletedSets(exercise) {
+				if set.Weight > performance.Weight || (set.Weight == performance.Weight && set.Reps > performance.Reps) {
+					performance.Weight = set.Weight
+					performance.Reps = set.Reps
+				}
+			}
+			if performance.Reps == 0 {
+				continue
+			}
+
+			key := prutil.ExerciseKey(exercise)
+			history[key] = append(history[key], performance)
+		}
+	}
+
+	for _, performances := range history {
+		sort.SliceStable(performances, func(i, j int) bool {
+			if performances[i].Week != performances[j].Week {
+				return performances[i].Week < performances[j].Week
+			}
+			return performances[i].Date < performances[j].Date
+		})
+	}
+	return history
+}
+
+// Target 按进阶规则生成动作在计划第 week 周的目标重量、次数和组数，返回是否为减载周。
+// history 为该动作在计划中的成绩（见 History），以 week 之前最近一次非减载周的成绩为起点继续进阶，
+// 没有成绩时从计划中写的重量和次数开始；oneRM 为估算 1RM，只有 wave 使用，没有时为 0。
+// 没有规则或规则无效时原样返回
+func Target(exercise domain.Exercise, week int, history []Performance, oneRM float64) (domain.Exercise, bool) {
+	if exercise.Progression == nil || !Valid(*exercise.Progression) || week < 1 {
+		return exercise, false
+	}
+	rule := *exercise.Progression
+
+	deload := isDeload(rule, week)
+	if deload {
+		// 减载周在上一周目标的基础上降低重量、减半组数
+		week--
+	}
+
+	var last *Performance
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Week < week && !isDeload(rule, history[i].Week) {
+			last = &history[i]
+			break
+		}
+	}
+
+	weight, reps := exercise.Weight, exercise.Reps
+	switch rule.Type {
+	case domain.ProgressionLinear:
+		weight = linearWeight(rule, exercise, week, last)
+	case domain.ProgressionDouble:
+		weight, reps = doubleTarget(rule, exercise, week, last)
+	case domain.ProgressionWave:
+		weight, reps = waveTarget(rule, exercise, week, oneRM)
+	}
+
+	target := exercise
+	target.Weight = weight
+	target.Reps = reps
+	if deload {
+		if target.Weight != nil {
+			factor := rule.DeloadFactor
+			if factor == 0 {
+				factor = defaultDeloadFactor
+			}
+			target.Weight = roundWeight(*target.Weight * factor)
+		}
+		if target.Sets != nil {
+			sets := (*target.Sets + 1) / 2
+			target.Sets = &sets
+		}
+	}
+	return target, deload
+}
+
+// linearWeight 每个非减载周加重一次；最近一次没有完成计划次数时重复一次该重量
+func linearWeight(rule domain.ProgressionRule, exercise domain.Exercise, week int, last *Performance) *float64 {
+	if last == nil {
+		if exercise.Weight == nil {
+			return nil
+		}
+		return roundWeight(*exercise.Weight + rule.Increment*float64(steps(rule, 1, week)))
+	}
+
+	n := steps(rule, last.Week, week)
+	if exercise.Reps != nil && last.Reps < *exercise.Reps {
+		n--
+	}
+	return roundWeight(last.Weight + rule.Increment*float64(n))
+}
+
+// doubleTarget 每个非减载周多做一次，超过次数上限时加重并回到次数下限；
+// 最近一次没有达到次数下限时从下限重新开始
+func doubleTarget(rule domain.ProgressionRule, exercise domain.Exercise, week int, last *Performance) (*float64, *int) {
+	span := rule.MaxReps - rule.MinReps + 1
+
+	var base *float64
+	var position int
+	if last == nil {
+		base = exercise.Weight
+		position = steps(rule, 1, week)
+	} else {
+		base = &last.Weight
+		done := min(max(last.Reps, rule.MinReps-1), rule.MaxReps)
+		position = done - rule.MinReps + steps(rule, last.Week, week)
+	}
+
+	reps := rule.MinReps + position%span
+	if base == nil {
+		return nil, &reps
+	}
+	return roundWeight(*base + rule.Increment*float64(position/span)), &reps
+}
+
+// waveTarget 按周循环 1RM 百分比，每完成一轮训练最大重量增加 increment；没有估算 1RM 时只调整次数
+func waveTarget(rule domain.ProgressionRule, exercise domain.Exercise, week int, oneRM float64) (*float64, *int) {
+	position := steps(rule, 1, week)
+	index := position % len(rule.Percentages)
+
+	reps := exercise.Reps
+	if len(rule.WaveReps) > 0 {
+		waveReps := rule.WaveReps[index]
+		reps = &waveReps
+	}
+	if oneRM <= 0 {
+		return exercise.Weight, reps
+	}
+
+	trainingMax := oneRM + rule.Increment*float64(position/len(rule.Percentages))
+	return roundWeight(trainingMax * rule.Percentages[index] / 100), reps
+}
+
+// steps 从第 from 周到第 to 周之间进阶的次数，减载周不进阶
+func steps(rule domain.ProgressionRule, from, to int) int {
+	return progressed(rule, to) - progressed(rule, from)
+}
+
+// progressed 第 week 周之前的非减载周数
+func progressed(rule domain.ProgressionRule, week int) int {
+	if rule.DeloadEvery == 0 {
+		return week - 1
+	}
+	return week - 1 - (week-1)/rule.DeloadEvery
+}
+
+func isDeload(rule domain.ProgressionRule, week int) bool {
+	return rule.DeloadEvery > 0 && week%rule.DeloadEvery == 0
+}
+
+// roundWeight 目标重量取最接近的 0.5kg
+func roundWeight(weight float64) *float64 {
+	rounded := math.Round(weight*2) / 2
+	return &rounded
+}
@@ -0,0 +1,129 @@
+package progressutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid(domain.ProgressionRule{Type: domain.ProgressionLinear, Increment: 2.5}))
+	assert.False(t, Valid(domain.ProgressionRule{Type: domain.ProgressionLinear}))
+	assert.True(t, Valid(domain.ProgressionRule{Type: domain.ProgressionDouble, Increment: 2.5, MinReps: 8, MaxReps: 12}))
+	assert.False(t, Valid(domain.ProgressionRule{Type: domain.ProgressionDouble, Increment: 2.5, MinReps: 12, MaxReps: 8}))
+	assert.True(t, Valid(domain.ProgressionRule{Type: domain.ProgressionWave, Percentages: []float64{70, 80}, WaveReps: []int{5, 3}}))
+	assert.False(t, Valid(domain.ProgressionRule{Type: domain.ProgressionWave, Percentages: []float64{70, 80}, WaveReps: []int{5}}))
+	assert.False(t, Valid(domain.ProgressionRule{Type: domain.ProgressionWave, Percentages: []float64{120}}))
+	assert.False(t, Valid(domain.ProgressionRule{Type: domain.ProgressionLinear, Increment: 2.5, DeloadEvery: 1}))
+	assert.False(t, Valid(domain.ProgressionRule{Type: "random"}))
+}
+
+func TestLinear(t *testing.T) {
+	exercise := domain.Exercise{
+		Name:        "Squat",
+		Sets:        intPtr(5),
+		Reps:        intPtr(5),
+		Weight:      floatPtr(60),
+		Progression: &domain.ProgressionRule{Type: domain.ProgressionLinear, Increment: 2.5, DeloadEvery: 4},
+	}
+
+	target := func(week int, history []Performance) (float64, int, bool) {
+		t.Helper()
+		result, deload := Target(exercise, week, history, 0)
+		require.NotNil(t, result.Weight)
+		return *result.Weight, *result.Sets, deload
+	}
+
+	weight, sets, deload := target(1, nil)
+	assert.Equal(t, []interface{}{60.0, 5, false}, []interface{}{weight, sets, deload})
+	weight, _, _ = target(3, nil)
+	assert.Equal(t, 65.0, weight)
+
+	// 第 4 周减载：第 3 周重量的 70%，组数减半
+	weight, sets, deload = target(4, nil)
+	assert.Equal(t, []interface{}{45.5, 3, true}, []interface{}{weight, sets, deload})
+	weight, _, _ = target(5, nil)
+	assert.Equal(t, 67.5, weight)
+
+	// 从实际成绩继续进阶，没完成计划次数时重复一次
+	weight, _, _ = target(3, []Performance{{Week: 2, Weight: 70, Reps: 5}})
+	assert.Equal(t, 72.5, weight)
+	weight, _, _ = target(3, []Performance{{Week: 2, Weight: 70, Reps: 4}})
+	assert.Equal(t, 70.0, weight)
+	weight, _, _ = target(5, []Performance{{Week: 3, Weight: 70, Reps: 5}, {Week: 4, Weight: 40, Reps: 5}})
+	assert.Equal(t, 72.5, weight)
+}
+
+func TestDouble(t *testing.T) {
+	exercise := domain.Exercise{
+		Name:        "Curl",
+		Weight:      floatPtr(10),
+		Progression: &domain.ProgressionRule{Type: domain.ProgressionDouble, Increment: 2, MinReps: 8, MaxReps: 10},
+	}
+
+	got := [][2]float64{}
+	for week := 1; week <= 5; week++ {
+		result, _ := Target(exercise, week, nil, 0)
+		got = append(got, [2]float64{*result.Weight, float64(*result.Reps)})
+	}
+	assert.Equal(t, [][2]float64{{10, 8}, {10, 9}, {10, 10}, {12, 8}, {12, 9}}, got)
+
+	result, _ := Target(exercise, 3, []Performance{{Week: 2, Weight: 11, Reps: 10}}, 0)
+	assert.Equal(t, 13.0, *result.Weight)
+	assert.Equal(t, 8, *result.Reps)
+
+	result, _ = Target(exercise, 3, []Performance{{Week: 2, Weight: 11, Reps: 6}}, 0)
+	assert.Equal(t, 11.0, *result.Weight)
+	assert.Equal(t, 8, *result.Reps)
+}
+
+func TestWave(t *testing.T) {
+	exercise := domain.Exercise{
+		Name:        "Bench",
+		Reps:        intPtr(5),
+		Progression: &domain.ProgressionRule{Type: domain.ProgressionWave, Increment: 5, Percentages: []float64{70, 80, 90}, WaveReps: []int{5, 3, 1}},
+	}
+
+	result, _ := Target(exercise, 2, nil, 100)
+	assert.Equal(t, 80.0, *result.Weight)
+	assert.Equal(t, 3, *result.Reps)
+
+	// 第二轮训练最大重量 105
+	result, _ = Target(exercise, 4, nil, 100)
+	assert.Equal(t, 73.5, *result.Weight)
+	assert.Equal(t, 5, *result.Reps)
+
+	// 没有 1RM 时只调整次数
+	result, _ = Target(exercise, 3, nil, 0)
+	assert.Nil(t, result.Weight)
+	assert.Equal(t, 1, *result.Reps)
+	assert.Equal(t, 5, *exercise.Reps)
+}
+
+func TestHistory(t *testing.T) {
+	day := func(v int) *int { return &v }
+	start := time.Date(2025, 11, 5, 10, 0, 0, 0, time.UTC)
+	later := start.AddDate(0, 0, 7)
+	records := []domain.TrainingRecord{
+		{StartTime: &later, PlanDayID: day(8), Exercises: []domain.Exercise{{Name: "Squat", SetsData: []domain.SetDetail{
+			{SetType: domain.SetTypeWarmup, Weight: 100, Reps: 5, IsCompleted: true},
+			{SetType: domain.SetTypeWorking, Weight: 65, Reps: 5, IsCompleted: true},
+			{SetType: domain.SetTypeWorking, Weight: 65, Reps: 6, IsCompleted: true},
+			{SetType: domain.SetTypeWorking, Weight: 70, Reps: 3, IsCompleted: false},
+		}}}},
+		{StartTime: &start, PlanDayID: day(1), Exercises: []domain.Exercise{{Name: "Squat", Weight: floatPtr(60), Reps: intPtr(5), Sets: intPtr(3)}}},
+		{StartTime: &start, Exercises: []domain.Exercise{{Name: "Squat", Weight: floatPtr(200), Reps: intPtr(1)}}},
+	}
+
+	history := History(records, func(dayNumber int) int { return (dayNumber-1)/7 + 1 }, time.UTC)
+	assert.Equal(t, []Performance{
+		{Week: 1, Date: "2025-11-05", Weight: 60, Reps: 5},
+		{Week: 2, Date: "2025-11-12", Weight: 65, Reps: 6},
+	}, history["squat"])
+}
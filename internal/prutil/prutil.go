@@ -0,0 +1,268 @@
+package prutil
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EstimateOneRM 按公式根据单组重量和次数估算 1RM，无法估算时返回 0
+func EstimateOneRM(formula string, weight float64, reps int) float64 {
+	if weight <= 0 || reps <= 0 {
+		return 0
+	}
+	if reps == 1 {
+		return weight
+	}
+
+	switch formula {
+	case domain.OneRMFormulaBrzycki:
+		// 37 次及以上公式分母非正，没有意义
+		if reps >= 37 {
+			return 0
+		}
+		return weight * 36 / float64(37-reps)
+	default:
+		return weight * (1 + float64(reps)/30)
+	}
+}
+
+// Set 参与个人记录统计的一组
+type Set struct {
+	Index  int
+	Weight float64
+	Reps   int
+}
+
+// CompletedSets 返回训练项目中已完成的非热身组。
+// 没有 setsData 的旧数据按 weight/reps/sets 视为 sets 个相同的组。
+func CompletedSets(exercise domain.Exercise) []Set {
+	sets := []Set{}
+
+	if len(exercise.SetsData) > 0 {
+		for i, detail := range exercise.SetsData {
+			if !detail.IsCompleted || detail.SetType == domain.SetTypeWarmup || detail.Reps <= 0 {
+				continue
+			}
+			sets = append(sets, Set{Index: i, Weight: detail.Weight, Reps: detail.Reps})
+		}
+		return sets
+	}
+
+	if exercise.Weight == nil || exercise.Reps == nil || *exercise.Reps <= 0 {
+		return sets
+	}
+	count := 1
+	if exercise.Sets != nil && *exercise.Sets > 0 {
+		count = *exercise.Sets
+	}
+	for i := 0; i < count; i++ {
+		sets = append(sets, Set{Index: i, Weight: *exercise.Weight, Reps: *exercise.Reps})
+	}
+	return sets
+}
+
+// ExerciseKey 关联了动作库的项目按规范ID归并，自由文本项目按规范化名称归并
+func ExerciseKey(exercise domain.Exercise) string {
+	if exercise.ExerciseID != "" {
+		return exercise.ExerciseID
+	}
+	return domain.NormalizeExerciseTerm(exercise.Name)
+}
+
+// RecordDate 训练记录在 location 中的日期，优先取开始时间，没有时取创建时间
+func RecordDate(record *domain.TrainingRecord, location *time.Location) string {
+	if record.StartTime != nil {
+		return timeutil.Date(*record.StartTime, location)
+	}
+	return timeutil.Date(record.CreatedAt.Time(), location)
+}
+
+// Tracker 逐条累积训练记录并得出每个动作的个人记录。
+// 数值相同时保留日期更早的一组，即最先达成的记录。
+type Tracker struct {
+	formula  string
+	location *time.Location
+	records  map[string]*domain.PersonalRecord
+	order    []string
+}
+
+func NewTracker(formula string, location *time.Location) *Tracker {
+	return &Tracker{
+		formula:  formula,
+		location: location,
+		records:  make(map[string]*domain.PersonalRecord),
+	}
+}
+
+// Add 累积一条训练记录
+func (t *Tracker) Add(record *domain.TrainingRecord) {
+	recordID := record.ID.Hex()
+	date := RecordDate(record, t.location)
+
+	// 同一次训练中重复出现的动作合并计算训练量
+	volumes := make(map[string]float64)
+
+	for _, exercise := range record.Exercises {
+		key := ExerciseKey(exercise)
+		if key == "" {
+			continue
+		}
+
+		sets := CompletedSets(exercise)
+		if len(sets) == 0 {
+			continue
+		}
+
+		pr := t.get(key, exercise)
+		for _, set := range sets {
+			prSet := domain.PRSet{RecordID: recordID, SetIndex: set.Index, Weight: set.Weight, Reps: set.Reps, Date: date}
+
+			if pr.RecordID == "" || better(set.Weight, date, pr.MaxWeight, pr.Date) {
+				pr.MaxWeight = set.Weight
+				pr.Date = date
+				pr.RecordID = recordID
+				pr.SetIndex = set.Index
+			}
+
+			if value := round(EstimateOneRM(t.formula, set.Weight, set.Reps)); value > 0 {
+				if pr.EstimatedOneRM == nil || better(value, date, pr.EstimatedOneRM.Value, pr.EstimatedOneRM.Date) {
+					pr.EstimatedOneRM = &domain.EstimatedOneRM{Value: value, Formula: t.formula, PRSet: prSet}
+				}
+			}
+
+			for i, target := range domain.RepMaxTargets {
+				if set.Reps < target {
+					continue
+				}
+				current := &pr.RepMaxes[i]
+				if current.RecordID == "" || better(set.Weight, date, current.Weight, current.Date) {
+					*current = domain.RepMax{Target: target, PRSet: prSet}
+				}
+			}
+
+			volumes[key] += set.Weight * float64(set.Reps)
+		}
+	}
+
+	for key, volume := range volumes {
+		pr := t.records[key]
+		volume = round(volume)
+		if pr.BestSessionVolume == nil || better(volume, date, pr.BestSessionVolume.Volume, pr.BestSessionVolume.Date) {
+			pr.BestSessionVolume = &domain.SessionVolume{Volume: volume, RecordID: recordID, Date: date}
+		}
+	}
+}
+
+// Records 返回按动作名称排序的个人记录，未达成的次数档位不返回
+func (t *Tracker) Records() []domain.PersonalRecord {
+	result := []domain.PersonalRecord{}
+	for _, key := range t.order {
+		pr := *t.records[key]
+		repMaxes := []domain.RepMax{}
+		for _, repMax := range pr.RepMaxes {
+			if repMax.RecordID != "" {
+				repMaxes = append(repMaxes, repMax)
+			}
+		}
+		pr.RepMaxes = repMaxes
+		result = append(result, pr)
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].ExerciseName < result[j].ExerciseName
+	})
+	return result
+}
+
+func (t *Tracker) get(key string, exercise domain.Exercise) *domain.PersonalRecord {
+	if pr, exists := t.records[key]; exists {
+		return pr
+	}
+	pr := &domain.PersonalRecord{
+		ExerciseID:   exercise.ExerciseID,
+		ExerciseName: exercise.Name,
+		RepMaxes:     make([]domain.RepMax, len(domain.RepMaxTargets)),
+	}
+	t.records[key] = pr
+	t.order = append(t.order, key)
+	return pr
+}
+
+// better 数值更大，或数值相同但日期更早
+func better(value float64, date string, bestValue float64, bestDate string) bool {
+	if value != bestValue {
+		return value > bestValue
+	}
+	return date < bestDate
+}
+
+// round 保留两位小数
+func round(value float64) float64 {
+	return float64(int64(value*100+0.5)) / 100
+}
+
+// Events 把个人记录展开为逐项的历史条目，只填充成绩相关字段
+func Events(pr domain.PersonalRecord) []domain.PersonalRecordEvent {
+	key := ExerciseKey(domain.Exercise{ExerciseID: pr.ExerciseID, Name: pr.ExerciseName})
+	base := domain.PersonalRecordEvent{
+		ExerciseKey:  key,
+		ExerciseID:   pr.ExerciseID,
+		ExerciseName: pr.ExerciseName,
+	}
+
+	events := []domain.PersonalRecordEvent{}
+	if pr.RecordID != "" {
+		event := fromSet(base, domain.PRTypeMaxWeight, pr.MaxWeight, domain.PRSet{
+			RecordID: pr.RecordID,
+			SetIndex: pr.SetIndex,
+			Weight:   pr.MaxWeight,
+			Date:     pr.Date,
+		})
+		events = append(events, event)
+	}
+	if pr.EstimatedOneRM != nil {
+		event := fromSet(base, domain.PRTypeEstimatedOneRM, pr.EstimatedOneRM.Value, pr.EstimatedOneRM.PRSet)
+		event.Formula = pr.EstimatedOneRM.Formula
+		events = append(events, event)
+	}
+	for _, repMax := range pr.RepMaxes {
+		if repMax.RecordID == "" {
+			continue
+		}
+		event := fromSet(base, domain.PRTypeRepMax, repMax.Weight, repMax.PRSet)
+		event.Target = repMax.Target
+		events = append(events, event)
+	}
+	if pr.BestSessionVolume != nil {
+		event := base
+		event.Type = domain.PRTypeSessionVolume
+		event.Value = pr.BestSessionVolume.Volume
+		event.RecordID, _ = primitive.ObjectIDFromHex(pr.BestSessionVolume.RecordID)
+		event.Date = pr.BestSessionVolume.Date
+		events = append(events, event)
+	}
+	return events
+}
+
+// EventKey 同一动作、同一类型（及次数档位、估算公式）的历史互相比较
+func EventKey(event domain.PersonalRecordEvent) string {
+	return fmt.Sprintf("%s|%s|%d|%s", event.ExerciseKey, event.Type, event.Target, event.Formula)
+}
+
+func fromSet(base domain.PersonalRecordEvent, prType string, value float64, set domain.PRSet) domain.PersonalRecordEvent {
+	event := base
+	event.Type = prType
+	event.Value = value
+	event.RecordID, _ = primitive.ObjectIDFromHex(set.RecordID)
+	setIndex := set.SetIndex
+	event.SetIndex = &setIndex
+	event.Weight = set.Weight
+	event.Reps = set.Reps
+	event.Date = set.Date
+	return event
+}
@@ -0,0 +1,146 @@
+package prutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func intPtr(v int) *int           { return &v }
+func floatPtr(v float64) *float64 { return &v }
+
+func TestEstimateOneRM(t *testing.T) {
+	tests := []struct {
+		name    string
+		formula string
+		weight  float64
+		reps    int
+		want    float64
+	}{
+		{"epley", domain.OneRMFormulaEpley, 100, 5, 100 * (1 + 5.0/30)},
+		{"epley 10 reps", domain.OneRMFormulaEpley, 60, 10, 80},
+		{"unknown formula uses epley", "", 100, 5, 100 * (1 + 5.0/30)},
+		{"brzycki", domain.OneRMFormulaBrzycki, 100, 5, 100 * 36 / 32.0},
+		{"brzycki 36 reps", domain.OneRMFormulaBrzycki, 50, 36, 50 * 36},
+		{"brzycki 37 reps", domain.OneRMFormulaBrzycki, 50, 37, 0},
+		{"single rep epley", domain.OneRMFormulaEpley, 120, 1, 120},
+		{"single rep brzycki", domain.OneRMFormulaBrzycki, 120, 1, 120},
+		{"no weight", domain.OneRMFormulaEpley, 0, 5, 0},
+		{"no reps", domain.OneRMFormulaEpley, 100, 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, EstimateOneRM(tt.formula, tt.weight, tt.reps), 1e-9)
+		})
+	}
+}
+
+func TestCompletedSets(t *testing.T) {
+	tests := []struct {
+		name     string
+		exercise domain.Exercise
+		want     []Set
+	}{
+		{
+			name: "warm-up and unfinished sets excluded",
+			exercise: domain.Exercise{
+				// 有组数据时忽略汇总字段
+				Sets: intPtr(5), Reps: intPtr(5), Weight: floatPtr(200),
+				SetsData: []domain.SetDetail{
+					{SetType: domain.SetTypeWarmup, Weight: 40, Reps: 10, IsCompleted: true},
+					{SetType: domain.SetTypeWorking, Weight: 100, Reps: 5, IsCompleted: true},
+					{SetType: domain.SetTypeWorking, Weight: 100, Reps: 5, IsCompleted: false},
+					{SetType: domain.SetTypeWorking, Weight: 100, Reps: 0, IsCompleted: true},
+					{SetType: domain.SetTypeCooldown, Weight: 60, Reps: 8, IsCompleted: true},
+				},
+			},
+			want: []Set{{Index: 1, Weight: 100, Reps: 5}, {Index: 4, Weight: 60, Reps: 8}},
+		},
+		{
+			name: "only warm-up sets",
+			exercise: domain.Exercise{
+				SetsData: []domain.SetDetail{{SetType: domain.SetTypeWarmup, Weight: 40, Reps: 10, IsCompleted: true}},
+			},
+			want: []Set{},
+		},
+		{
+			name:     "summary fallback",
+			exercise: domain.Exercise{Sets: intPtr(3), Reps: intPtr(8), Weight: floatPtr(60)},
+			want:     []Set{{Index: 0, Weight: 60, Reps: 8}, {Index: 1, Weight: 60, Reps: 8}, {Index: 2, Weight: 60, Reps: 8}},
+		},
+		{
+			name:     "summary fallback without sets counts one set",
+			exercise: domain.Exercise{Reps: intPtr(8), Weight: floatPtr(60)},
+			want:     []Set{{Index: 0, Weight: 60, Reps: 8}},
+		},
+		{
+			name:     "summary without weight",
+			exercise: domain.Exercise{Sets: intPtr(3), Reps: intPtr(8)},
+			want:     []Set{},
+		},
+		{
+			name:     "summary without reps",
+			exercise: domain.Exercise{Sets: intPtr(3), Reps: intPtr(0), Weight: floatPtr(60)},
+			want:     []Set{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, CompletedSets(tt.exercise))
+		})
+	}
+}
+
+func TestTracker(t *testing.T) {
+	first := time.Date(2025, 11, 3, 18, 0, 0, 0, time.UTC)
+	second := first.AddDate(0, 0, 7)
+	earlier := &domain.TrainingRecord{
+		ID:        primitive.NewObjectID(),
+		StartTime: &first,
+		Exercises: []domain.Exercise{{
+			Name: "Squat",
+			SetsData: []domain.SetDetail{
+				{SetType: domain.SetTypeWarmup, Weight: 150, Reps: 1, IsCompleted: true},
+				{SetType: domain.SetTypeWorking, Weight: 100, Reps: 5, IsCompleted: true},
+			},
+		}},
+	}
+	later := &domain.TrainingRecord{
+		ID:        primitive.NewObjectID(),
+		StartTime: &second,
+		Exercises: []domain.Exercise{
+			{Name: "squat", Sets: intPtr(2), Reps: intPtr(3), Weight: floatPtr(100)},
+			{Name: "Bench Press", Sets: intPtr(1), Reps: intPtr(1), Weight: floatPtr(80)},
+		},
+	}
+
+	tracker := NewTracker(domain.OneRMFormulaEpley, time.UTC)
+	tracker.Add(earlier)
+	tracker.Add(later)
+	records := tracker.Records()
+	require.Len(t, records, 2)
+
+	bench, squat := records[0], records[1]
+	assert.Equal(t, "Bench Press", bench.ExerciseName)
+	assert.Equal(t, 80.0, bench.MaxWeight)
+	require.Len(t, bench.RepMaxes, 1)
+	assert.Equal(t, 1, bench.RepMaxes[0].Target)
+
+	// 热身组不计入，重量相同时保留更早的一组
+	assert.Equal(t, "Squat", squat.ExerciseName)
+	assert.Equal(t, 100.0, squat.MaxWeight)
+	assert.Equal(t, earlier.ID.Hex(), squat.RecordID)
+	assert.Equal(t, "2025-11-03", squat.Date)
+	require.NotNil(t, squat.EstimatedOneRM)
+	assert.Equal(t, 116.67, squat.EstimatedOneRM.Value)
+	assert.Equal(t, earlier.ID.Hex(), squat.EstimatedOneRM.RecordID)
+	require.NotNil(t, squat.BestSessionVolume)
+	assert.Equal(t, 600.0, squat.BestSessionVolume.Volume)
+	assert.Equal(t, later.ID.Hex(), squat.BestSessionVolume.RecordID)
+}
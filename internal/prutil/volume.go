@@ -0,0 +1,70 @@
+package prutil
+
+import "github.com/zhengshui/flow-link-server/domain"
+
+// ApplyVolume 按已完成的非热身组计算每个动作的训练量(重量 × 次数)和组数，以及各肌群训练量。
+// 记录中有组数据时总重量、总组数以计算值为准；没有组数据时保留客户端提交的值，未提交才使用计算值。
+func ApplyVolume(record *domain.TrainingRecord) {
+	hasSetsData := false
+	totalVolume := 0.0
+	totalSets := 0
+	muscleGroups := []domain.MuscleGroupVolume{}
+
+	for i := range record.Exercises {
+		exercise := &record.Exercises[i]
+		if len(exercise.SetsData) > 0 {
+			hasSetsData = true
+		}
+
+		sets := CompletedSets(*exercise)
+		volume := 0.0
+		for _, set := range sets {
+			volume += set.Weight * float64(set.Reps)
+		}
+		volume = round(volume)
+		count := len(sets)
+		exercise.Volume = &volume
+		exercise.WorkingSets = &count
+
+		totalVolume += volume
+		totalSets += count
+
+		if exercise.MuscleGroup != nil && *exercise.MuscleGroup != "" {
+			muscleGroups = addMuscleGroupVolume(muscleGroups, *exercise.MuscleGroup, volume, count)
+		}
+	}
+
+	record.MuscleGroupVolumes = muscleGroups
+	if len(record.Exercises) == 0 {
+		return
+	}
+	if hasSetsData || record.TotalWeight == nil {
+		totalVolume = round(totalVolume)
+		record.TotalWeight = &totalVolume
+	}
+	if hasSetsData || record.TotalSets == nil {
+		record.TotalSets = &totalSets
+	}
+}
+
+// ExerciseVolume 动作的训练量，服务端计算前保存的旧数据按 weight × sets × reps 估算
+func ExerciseVolume(exercise domain.Exercise) float64 {
+	if exercise.Volume != nil {
+		return *exercise.Volume
+	}
+	if exercise.Weight == nil || exercise.Sets == nil || exercise.Reps == nil {
+		return 0
+	}
+	return *exercise.Weight * float64(*exercise.Sets**exercise.Reps)
+}
+
+func addMuscleGroupVolume(muscleGroups []domain.MuscleGroupVolume, muscleGroup string, volume float64, sets int) []domain.MuscleGroupVolume {
+	for i := range muscleGroups {
+		if muscleGroups[i].MuscleGroup == muscleGroup {
+			muscleGroups[i].Volume = round(muscleGroups[i].Volume + volume)
+			muscleGroups[i].Sets += sets
+			return muscleGroups
+		}
+	}
+	return append(muscleGroups, domain.MuscleGroupVolume{MuscleGroup: muscleGroup, Volume: volume, Sets: sets})
+}
@@ -0,0 +1,107 @@
+package prutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+func strPtr(v string) *string { return &v }
+
+func TestApplyVolume(t *testing.T) {
+	tests := []struct {
+		name         string
+		record       domain.TrainingRecord
+		volumes      []float64
+		workingSets  []int
+		totalWeight  *float64
+		totalSets    *int
+		muscleGroups []domain.MuscleGroupVolume
+	}{
+		{
+			name: "sets data overrides submitted totals",
+			record: domain.TrainingRecord{
+				TotalWeight: floatPtr(9999),
+				TotalSets:   intPtr(99),
+				Exercises: []domain.Exercise{
+					{
+						MuscleGroup: strPtr("腿部"),
+						SetsData: []domain.SetDetail{
+							{SetType: domain.SetTypeWarmup, Weight: 40, Reps: 10, IsCompleted: true},
+							{SetType: domain.SetTypeWorking, Weight: 100, Reps: 5, IsCompleted: true},
+							{SetType: domain.SetTypeWorking, Weight: 100, Reps: 5, IsCompleted: false},
+						},
+					},
+					{MuscleGroup: strPtr("腿部"), Sets: intPtr(3), Reps: intPtr(10), Weight: floatPtr(50.5)},
+					{MuscleGroup: strPtr("胸部"), Sets: intPtr(2), Reps: intPtr(8), Weight: floatPtr(60)},
+				},
+			},
+			volumes:     []float64{500, 1515, 960},
+			workingSets: []int{1, 3, 2},
+			totalWeight: floatPtr(2975),
+			totalSets:   intPtr(6),
+			muscleGroups: []domain.MuscleGroupVolume{
+				{MuscleGroup: "腿部", Volume: 2015, Sets: 4},
+				{MuscleGroup: "胸部", Volume: 960, Sets: 2},
+			},
+		},
+		{
+			name: "summary only keeps submitted totals",
+			record: domain.TrainingRecord{
+				TotalWeight: floatPtr(1000),
+				TotalSets:   intPtr(4),
+				Exercises:   []domain.Exercise{{Sets: intPtr(3), Reps: intPtr(10), Weight: floatPtr(20)}},
+			},
+			volumes:      []float64{600},
+			workingSets:  []int{3},
+			totalWeight:  floatPtr(1000),
+			totalSets:    intPtr(4),
+			muscleGroups: []domain.MuscleGroupVolume{},
+		},
+		{
+			name: "summary only fills missing totals",
+			record: domain.TrainingRecord{
+				Exercises: []domain.Exercise{{Sets: intPtr(3), Reps: intPtr(10), Weight: floatPtr(20)}},
+			},
+			volumes:      []float64{600},
+			workingSets:  []int{3},
+			totalWeight:  floatPtr(600),
+			totalSets:    intPtr(3),
+			muscleGroups: []domain.MuscleGroupVolume{},
+		},
+		{
+			name: "no exercises leaves totals alone",
+			record: domain.TrainingRecord{
+				TotalWeight: floatPtr(100),
+			},
+			totalWeight:  floatPtr(100),
+			muscleGroups: []domain.MuscleGroupVolume{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := tt.record
+			ApplyVolume(&record)
+
+			require.Len(t, record.Exercises, len(tt.volumes))
+			for i, exercise := range record.Exercises {
+				require.NotNil(t, exercise.Volume)
+				require.NotNil(t, exercise.WorkingSets)
+				assert.Equal(t, tt.volumes[i], *exercise.Volume)
+				assert.Equal(t, tt.workingSets[i], *exercise.WorkingSets)
+			}
+			assert.Equal(t, tt.totalWeight, record.TotalWeight)
+			assert.Equal(t, tt.totalSets, record.TotalSets)
+			assert.Equal(t, tt.muscleGroups, record.MuscleGroupVolumes)
+		})
+	}
+}
+
+func TestExerciseVolume(t *testing.T) {
+	assert.Equal(t, 750.0, ExerciseVolume(domain.Exercise{Volume: floatPtr(750), Sets: intPtr(3), Reps: intPtr(10), Weight: floatPtr(100)}))
+	assert.Equal(t, 3000.0, ExerciseVolume(domain.Exercise{Sets: intPtr(3), Reps: intPtr(10), Weight: floatPtr(100)}))
+	assert.Equal(t, 0.0, ExerciseVolume(domain.Exercise{Sets: intPtr(3), Reps: intPtr(10)}))
+}
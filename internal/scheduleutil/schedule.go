@@ -0,0 +1,338 @@
+package scheduleutil
+
+import (
+	"sort"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+)
+
+// defaultWeekdays 没有指定训练星期时，按每周训练天数尽量隔天安排
+var defaultWeekdays = map[int][]int{
+	1: {1},
+	2: {1, 4},
+	3: {1, 3, 5},
+	4: {1, 2, 4, 5},
+	5: {1, 2, 3, 4, 5},
+	6: {1, 2, 3, 4, 5, 6},
+	7: {1, 2, 3, 4, 5, 6, 7},
+}
+
+// ValidWeekdays 星期取值为 1（周一）到 7（周日）且不重复
+func ValidWeekdays(weekdays []int) bool {
+	seen := make(map[int]bool)
+	for _, weekday := range weekdays {
+		if weekday < 1 || weekday > 7 || seen[weekday] {
+			return false
+		}
+		seen[weekday] = true
+	}
+	return true
+}
+
+// Weekdays 计划使用的训练星期：计划自身的设置优先，其次是用户偏好，都没有时按每周训练天数取默认安排
+func Weekdays(plan domain.FitnessPlan, preferred []int) []int {
+	if len(plan.TrainingWeekdays) > 0 {
+		return plan.TrainingWeekdays
+	}
+	if len(preferred) > 0 {
+		return preferred
+	}
+
+	perWeek := plan.TrainingDaysPerWeek
+	if perWeek <= 0 {
+		perWeek = len(workouts(plan.TrainingDays))
+	}
+	if perWeek > 7 {
+		perWeek = 7
+	}
+	return defaultWeekdays[perWeek]
+}
+
+// TotalDays 计划总天数，每次顺延都会让计划延长相应的天数
+func TotalDays(plan domain.FitnessPlan) int {
+	days := plan.DurationWeeks * 7
+	for _, change := range plan.Reschedules {
+		if change.Action == domain.RescheduleShift {
+			days += change.Days
+		}
+	}
+	return days
+}
+
+// Remap 返回计划日经过一次日程调整后的编号，没有受影响时原样返回
+func Remap(change domain.PlanReschedule, day int) int {
+	switch change.Action {
+	case domain.RescheduleShift:
+		if day >= change.DayNumber {
+			return day + change.Days
+		}
+	case domain.RescheduleSwap:
+		if day == change.DayNumber {
+			return change.TargetDay
+		}
+		if day == change.TargetDay {
+			return change.DayNumber
+		}
+	case domain.RescheduleMove:
+		if day == change.DayNumber {
+			return change.TargetDay
+		}
+	}
+	return day
+}
+
+// Schedule 将计划的训练日按开始日期和训练星期排到日历上。
+// 计划日从开始日期算起，开始日期为第 1 天；训练星期的每一天依次轮换计划中的非休息训练日，
+// 计划有阶段时每周从头安排所在阶段的训练日。之后按顺序应用计划的日程调整记录
+type Schedule struct {
+	plan      domain.FitnessPlan
+	start     time.Time
+	days      int
+	preferred []int
+	weekdays  map[int]bool
+	workouts  []domain.TrainingDay
+}
+
+// New 按计划或用户偏好的训练星期 preferred 创建计划的日程，开始日期格式错误时返回 error
+func New(plan domain.FitnessPlan, preferred []int) (Schedule, error) {
+	start, err := time.Parse(timeutil.DateLayout, plan.StartDate)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return Schedule{
+		plan:      plan,
+		start:     start,
+		days:      TotalDays(plan),
+		preferred: preferred,
+		weekdays:  dayset(Weekdays(plan, preferred)),
+		workouts:  workouts(plan.TrainingDays),
+	}, nil
+}
+
+// Sessions 返回日期在 [from, to] 内的训练安排，today 用于区分待训练和已错过
+func (s Schedule) Sessions(from, to, today string) []domain.PlanSession {
+	sessions := []domain.PlanSession{}
+	completed := dayset(s.plan.CompletedDays)
+	skipped := dayset(s.plan.SkippedDays)
+	overrides := make(map[int]domain.TrainingDay)
+	for _, day := range s.plan.TrainingDaysOverride {
+		overrides[day.DayNumber] = day
+	}
+
+	for _, slot := range s.slots() {
+		date := s.start.AddDate(0, 0, slot.dayNumber-1)
+		value := date.Format(timeutil.DateLayout)
+		if value < from || value > to {
+			continue
+		}
+
+		session := domain.PlanSession{
+			PlanID:        s.plan.ID.Hex(),
+			PlanName:      s.plan.Name,
+			Date:          value,
+			Weekday:       isoWeekday(date),
+			Week:          slot.week,
+			Phase:         slot.phase,
+			DayNumber:     slot.dayNumber,
+			DayName:       slot.workout.DayName,
+			Exercises:     slot.workout.Exercises,
+			Notes:         slot.workout.Notes,
+			IntensityHint: slot.workout.IntensityHint,
+			Deload:        slot.deload,
+		}
+		if override, ok := overrides[slot.dayNumber]; ok {
+			session.Exercises = override.Exercises
+			if override.Notes != "" {
+				session.Notes = override.Notes
+			}
+		}
+		if session.Exercises == nil {
+			session.Exercises = []domain.Exercise{}
+		}
+
+		switch {
+		case completed[slot.dayNumber]:
+			session.Status = domain.SessionStatusCompleted
+		case skipped[slot.dayNumber]:
+			session.Status = domain.SessionStatusSkipped
+		case value < today:
+			session.Status = domain.SessionStatusMissed
+		default:
+			session.Status = domain.SessionStatusPending
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// Day 返回安排在计划日 dayNumber 的训练
+func (s Schedule) Day(dayNumber int, today string) (domain.PlanSession, bool) {
+	date := s.start.AddDate(0, 0, dayNumber-1).Format(timeutil.DateLayout)
+	sessions := s.Sessions(date, date, today)
+	if len(sessions) == 0 {
+		return domain.PlanSession{}, false
+	}
+	return sessions[0], true
+}
+
+// Next 返回 date 当天及之后第一次待训练的安排
+func (s Schedule) Next(date string) (domain.PlanSession, bool) {
+	for _, session := range s.Sessions(date, s.EndDate(), date) {
+		if session.Status == domain.SessionStatusPending {
+			return session, true
+		}
+	}
+	return domain.PlanSession{}, false
+}
+
+// Week 返回计划日所属的计划周：安排了训练的计划日取训练原来所在的周，其余按日期计算
+func (s Schedule) Week(dayNumber int) int {
+	for _, slot := range s.slots() {
+		if slot.dayNumber == dayNumber {
+			return slot.week
+		}
+	}
+	return (dayNumber-1)/7 + 1
+}
+
+// Position 返回 date 所在的计划周和周内第几天，开始前为第 1 周第 1 天，结束后停在最后一天
+func (s Schedule) Position(date string) (week, day int) {
+	if s.days <= 0 {
+		return 1, 1
+	}
+	elapsed, err := timeutil.DaysBetween(s.plan.StartDate, date)
+	if err != nil || elapsed < 0 {
+		elapsed = 0
+	}
+	if elapsed >= s.days {
+		elapsed = s.days - 1
+	}
+	return elapsed/7 + 1, elapsed%7 + 1
+}
+
+// EndDate 计划最后一天的日期
+func (s Schedule) EndDate() string {
+	return s.start.AddDate(0, 0, s.days-1).Format(timeutil.DateLayout)
+}
+
+// slot 一次训练，week 为调整日程之前所在的计划周
+type slot struct {
+	dayNumber int
+	week      int
+	workout   domain.TrainingDay
+	phase     string
+	deload    bool
+}
+
+// slots 按计划日排序的全部训练：先排出原始日程，再依次应用日程调整
+func (s Schedule) slots() []slot {
+	var slots []slot
+	if len(s.plan.Phases) > 0 {
+		slots = s.phaseSlots()
+	} else {
+		slots = s.rotationSlots()
+	}
+
+	for _, change := range s.plan.Reschedules {
+		for i := range slots {
+			slots[i].dayNumber = Remap(change, slots[i].dayNumber)
+		}
+	}
+	sort.SliceStable(slots, func(i, j int) bool {
+		return slots[i].dayNumber < slots[j].dayNumber
+	})
+	return slots
+}
+
+// rotationSlots 训练星期的每一天依次轮换计划的训练日，轮换从计划第一天开始计数
+func (s Schedule) rotationSlots() []slot {
+	slots := []slot{}
+	if len(s.workouts) == 0 {
+		return slots
+	}
+
+	for dayNumber := 1; dayNumber <= s.plan.DurationWeeks*7; dayNumber++ {
+		date := s.start.AddDate(0, 0, dayNumber-1)
+		if !s.weekdays[isoWeekday(date)] {
+			continue
+		}
+		slots = append(slots, slot{
+			dayNumber: dayNumber,
+			week:      (dayNumber-1)/7 + 1,
+			workout:   s.workouts[len(slots)%len(s.workouts)],
+		})
+	}
+	return slots
+}
+
+// phaseSlots 每周按所在阶段的训练日依次排到该周的训练星期上，每周的训练次数等于阶段的训练日数
+func (s Schedule) phaseSlots() []slot {
+	slots := []slot{}
+	for week := 1; week <= s.plan.DurationWeeks; week++ {
+		phase := s.plan.PhaseAt(week)
+		if phase == nil {
+			continue
+		}
+		phaseWorkouts := workouts(phase.TrainingDays)
+		weekdays := dayset(s.phaseWeekdays(len(phaseWorkouts)))
+
+		index := 0
+		for offset := 0; offset < 7 && index < len(phaseWorkouts); offset++ {
+			dayNumber := (week-1)*7 + offset + 1
+			if !weekdays[isoWeekday(s.start.AddDate(0, 0, dayNumber-1))] {
+				continue
+			}
+			slots = append(slots, slot{
+				dayNumber: dayNumber,
+				week:      week,
+				workout:   phaseWorkouts[index],
+				phase:     phase.Name,
+				deload:    phase.Deload,
+			})
+			index++
+		}
+	}
+	return slots
+}
+
+// phaseWeekdays 阶段使用计划或用户指定的训练星期，天数不够安排阶段的训练日时按训练日数取默认安排
+func (s Schedule) phaseWeekdays(sessions int) []int {
+	weekdays := s.plan.TrainingWeekdays
+	if len(weekdays) == 0 {
+		weekdays = s.preferred
+	}
+	if len(weekdays) >= sessions {
+		return weekdays
+	}
+	return defaultWeekdays[min(sessions, 7)]
+}
+
+// workouts 非休息训练日，按 dayNumber 排序
+func workouts(trainingDays []domain.TrainingDay) []domain.TrainingDay {
+	days := []domain.TrainingDay{}
+	for _, day := range trainingDays {
+		if !day.IsRestDay {
+			days = append(days, day)
+		}
+	}
+	sort.SliceStable(days, func(i, j int) bool {
+		return days[i].DayNumber < days[j].DayNumber
+	})
+	return days
+}
+
+func dayset(days []int) map[int]bool {
+	set := make(map[int]bool, len(days))
+	for _, day := range days {
+		set[day] = true
+	}
+	return set
+}
+
+// isoWeekday 1 为周一，7 为周日
+func isoWeekday(t time.Time) int {
+	return (int(t.Weekday())+6)%7 + 1
+}
@@ -0,0 +1,214 @@
+package scheduleutil
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+// testPlan 2025-11-05（周三）开始的两周计划，A/B/C 三个训练日和一个休息日
+func testPlan() domain.FitnessPlan {
+	return domain.FitnessPlan{
+		Name:                "测试计划",
+		DurationWeeks:       2,
+		TrainingDaysPerWeek: 3,
+		StartDate:           "2025-11-05",
+		TrainingDays: []domain.TrainingDay{
+			{DayNumber: 4, DayName: "C"},
+			{DayNumber: 1, DayName: "A"},
+			{DayNumber: 3, DayName: "休息", IsRestDay: true},
+			{DayNumber: 2, DayName: "B"},
+		},
+	}
+}
+
+func TestWeekdays(t *testing.T) {
+	plan := testPlan()
+	assert.Equal(t, []int{1, 3, 5}, Weekdays(plan, nil))
+	assert.Equal(t, []int{2, 4, 6}, Weekdays(plan, []int{2, 4, 6}))
+
+	plan.TrainingWeekdays = []int{6, 7}
+	assert.Equal(t, []int{6, 7}, Weekdays(plan, []int{2, 4, 6}))
+
+	assert.True(t, ValidWeekdays([]int{1, 7}))
+	assert.False(t, ValidWeekdays([]int{0, 3}))
+	assert.False(t, ValidWeekdays([]int{8}))
+	assert.False(t, ValidWeekdays([]int{2, 2}))
+}
+
+func TestSessions(t *testing.T) {
+	plan := testPlan()
+	plan.CompletedDays = []int{1}
+	plan.SkippedDays = []int{3}
+	plan.TrainingDaysOverride = []domain.TrainingDay{
+		{DayNumber: 10, Exercises: []domain.Exercise{{ID: 1, Name: "Squat"}}, Notes: "临时调整"},
+	}
+
+	schedule, err := New(plan, []int{1, 3, 5})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-11-18", schedule.EndDate())
+
+	sessions := schedule.Sessions("2025-11-01", "2025-11-30", "2025-11-12")
+	type brief struct {
+		Date      string
+		DayNumber int
+		Week      int
+		Weekday   int
+		DayName   string
+		Status    string
+	}
+	got := []brief{}
+	for _, session := range sessions {
+		got = append(got, brief{session.Date, session.DayNumber, session.Week, session.Weekday, session.DayName, session.Status})
+	}
+	assert.Equal(t, []brief{
+		{"2025-11-05", 1, 1, 3, "A", domain.SessionStatusCompleted},
+		{"2025-11-07", 3, 1, 5, "B", domain.SessionStatusSkipped},
+		{"2025-11-10", 6, 1, 1, "C", domain.SessionStatusMissed},
+		{"2025-11-12", 8, 2, 3, "A", domain.SessionStatusPending},
+		{"2025-11-14", 10, 2, 5, "B", domain.SessionStatusPending},
+		{"2025-11-17", 13, 2, 1, "C", domain.SessionStatusPending},
+	}, got)
+
+	assert.Equal(t, "Squat", sessions[4].Exercises[0].Name)
+	assert.Equal(t, "临时调整", sessions[4].Notes)
+	assert.NotNil(t, sessions[0].Exercises)
+
+	// 范围之前的训练也参与轮换
+	window := schedule.Sessions("2025-11-10", "2025-11-12", "2025-11-12")
+	require.Len(t, window, 2)
+	assert.Equal(t, "C", window[0].DayName)
+	assert.Equal(t, "A", window[1].DayName)
+
+	next, ok := schedule.Next("2025-11-11")
+	require.True(t, ok)
+	assert.Equal(t, 8, next.DayNumber)
+
+	_, ok = schedule.Next("2025-11-18")
+	assert.False(t, ok)
+}
+
+func TestReschedules(t *testing.T) {
+	plan := testPlan()
+	plan.Reschedules = []domain.PlanReschedule{
+		{Action: domain.RescheduleShift, DayNumber: 6, Days: 2},
+		{Action: domain.RescheduleSwap, DayNumber: 1, TargetDay: 3},
+		{Action: domain.RescheduleMove, DayNumber: 15, TargetDay: 16},
+	}
+	assert.Equal(t, 16, TotalDays(plan))
+
+	schedule, err := New(plan, []int{1, 3, 5})
+	require.NoError(t, err)
+	assert.Equal(t, "2025-11-20", schedule.EndDate())
+
+	got := map[int]string{}
+	for _, session := range schedule.Sessions("", "9999-12-31", "2025-11-01") {
+		got[session.DayNumber] = session.DayName
+	}
+	assert.Equal(t, map[int]string{1: "B", 3: "A", 8: "C", 10: "A", 12: "B", 16: "C"}, got)
+
+	session, ok := schedule.Day(12, "2025-11-01")
+	require.True(t, ok)
+	assert.Equal(t, "2025-11-16", session.Date)
+	assert.Equal(t, 7, session.Weekday)
+
+	_, ok = schedule.Day(6, "2025-11-01")
+	assert.False(t, ok)
+
+	// 顺延后的训练仍属于原来的计划周
+	assert.Equal(t, 1, schedule.Week(8))
+	assert.Equal(t, 2, schedule.Week(16))
+	assert.Equal(t, 1, schedule.Week(6))
+}
+
+func TestPosition(t *testing.T) {
+	schedule, err := New(testPlan(), []int{1, 3, 5})
+	require.NoError(t, err)
+
+	for date, want := range map[string][2]int{
+		"2025-11-01": {1, 1},
+		"2025-11-05": {1, 1},
+		"2025-11-11": {1, 7},
+		"2025-11-12": {2, 1},
+		"2025-11-18": {2, 7},
+		"2025-12-31": {2, 7},
+	} {
+		week, day := schedule.Position(date)
+		assert.Equal(t, want, [2]int{week, day}, date)
+	}
+
+	_, err = New(domain.FitnessPlan{StartDate: "2025/11/05"}, nil)
+	assert.Error(t, err)
+}
+
+func TestPhases(t *testing.T) {
+	plan := testPlan()
+	plan.DurationWeeks = 3
+	plan.Phases = []domain.PlanPhase{
+		{Name: "增肌期", StartWeek: 1, EndWeek: 2, TrainingDays: plan.TrainingDays},
+		{Name: "减载周", StartWeek: 3, EndWeek: 3, Deload: true, TrainingDays: []domain.TrainingDay{
+			{DayNumber: 1, DayName: "D"},
+			{DayNumber: 2, DayName: "休息", IsRestDay: true},
+		}},
+	}
+	assert.Equal(t, 7, plan.TotalSessions())
+	assert.Equal(t, "减载周", plan.PhaseAt(3).Name)
+	assert.Nil(t, plan.PhaseAt(4))
+
+	schedule, err := New(plan, []int{1, 3, 5})
+	require.NoError(t, err)
+
+	type brief struct {
+		DayNumber int
+		Week      int
+		Phase     string
+		DayName   string
+		Deload    bool
+	}
+	got := []brief{}
+	for _, session := range schedule.Sessions("", "9999-12-31", "2025-11-01") {
+		got = append(got, brief{session.DayNumber, session.Week, session.Phase, session.DayName, session.Deload})
+	}
+	assert.Equal(t, []brief{
+		{1, 1, "增肌期", "A", false},
+		{3, 1, "增肌期", "B", false},
+		{6, 1, "增肌期", "C", false},
+		{8, 2, "增肌期", "A", false},
+		{10, 2, "增肌期", "B", false},
+		{13, 2, "增肌期", "C", false},
+		{15, 3, "减载周", "D", true},
+	}, got)
+}
+
+func TestRemap(t *testing.T) {
+	shift := domain.PlanReschedule{Action: domain.RescheduleShift, DayNumber: 6, Days: 2}
+	swap := domain.PlanReschedule{Action: domain.RescheduleSwap, DayNumber: 1, TargetDay: 3}
+	move := domain.PlanReschedule{Action: domain.RescheduleMove, DayNumber: 10, TargetDay: 11}
+
+	tests := []struct {
+		name   string
+		change domain.PlanReschedule
+		day    int
+		want   int
+	}{
+		{"shift before start", shift, 5, 5},
+		{"shift from start", shift, 6, 8},
+		{"shift after start", shift, 13, 15},
+		{"swap day", swap, 1, 3},
+		{"swap target", swap, 3, 1},
+		{"swap unrelated", swap, 2, 2},
+		{"move day", move, 10, 11},
+		// 移动只改变被移动的计划日，目标日原有的记录由调用方处理
+		{"move target", move, 11, 11},
+		{"move unrelated", move, 1, 1},
+		{"unknown action", domain.PlanReschedule{Action: "未知", DayNumber: 1, TargetDay: 2}, 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Remap(tt.change, tt.day))
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package timeutil
+
+import (
+	"errors"
+	"time"
+
+	// 运行环境不一定安装了时区数据库，内嵌一份保证 IANA 时区都能加载
+	_ "time/tzdata"
+)
+
+const (
+	DateLayout     = "2006-01-02"
+	DateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// ErrInvalidDateTime 无法解析的日期或时间
+var ErrInvalidDateTime = errors.New("invalid date time")
+
+// localLayouts 不带时区的时间格式，按用户时区解释
+var localLayouts = []string{
+	DateTimeLayout,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+}
+
+var defaultLocation = time.UTC
+
+// SetDefaultLocation 设置没有配置时区的用户使用的时区
+func SetDefaultLocation(name string) error {
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return err
+	}
+	defaultLocation = location
+	return nil
+}
+
+// ValidLocation 判断是否为可加载的 IANA 时区名称，如 Asia/Shanghai
+func ValidLocation(name string) bool {
+	if name == "" {
+		return false
+	}
+	_, err := time.LoadLocation(name)
+	return err == nil
+}
+
+// Location 返回时区，名称为空或无效时返回默认时区
+func Location(name string) *time.Location {
+	if name == "" {
+		return defaultLocation
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return defaultLocation
+	}
+	return location
+}
+
+// ParseDateTime 解析 RFC3339 时间；不带时区的 YYYY-MM-DD HH:mm:ss 等格式按 location 解释
+func ParseDateTime(value string, location *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, value, location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidDateTime
+}
+
+// Date 时间在 location 中的日期 YYYY-MM-DD
+func Date(t time.Time, location *time.Location) string {
+	return t.In(location).Format(DateLayout)
+}
+
+// Today location 中的今天
+func Today(location *time.Location) string {
+	return Date(time.Now(), location)
+}
+
+// DayRange 返回 [startDate 当天零点, endDate 次日零点) 的时间区间，日期按 location 解释
+func DayRange(startDate, endDate string, location *time.Location) (time.Time, time.Time, error) {
+	start, err := time.ParseInLocation(DateLayout, startDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDateTime
+	}
+	end, err := time.ParseInLocation(DateLayout, endDate, location)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrInvalidDateTime
+	}
+	return start, end.AddDate(0, 0, 1), nil
+}
+
+// DaysBetween 两个日期相差的天数，to 早于 from 时为负数
+func DaysBetween(from, to string) (int, error) {
+	start, err := time.Parse(DateLayout, from)
+	if err != nil {
+		return 0, ErrInvalidDateTime
+	}
+	end, err := time.Parse(DateLayout, to)
+	if err != nil {
+		return 0, ErrInvalidDateTime
+	}
+	return int(end.Sub(start).Hours() / 24), nil
+}
@@ -0,0 +1,87 @@
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDateTime(t *testing.T) {
+	shanghai, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	want := time.Date(2025, 11, 3, 23, 30, 0, 0, shanghai)
+
+	tests := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"legacy format in the user zone", "2025-11-03 23:30:00", true},
+		{"without seconds", "2025-11-03 23:30", true},
+		{"ISO without zone", "2025-11-03T23:30:00", true},
+		{"RFC3339 keeps its own offset", "2025-11-03T15:30:00Z", true},
+		{"date only", "2025-11-03", false},
+		{"garbage", "yesterday", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsed, err := ParseDateTime(tt.value, shanghai)
+			if !tt.ok {
+				assert.ErrorIs(t, err, ErrInvalidDateTime)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, want.Equal(parsed), parsed)
+		})
+	}
+}
+
+func TestDate(t *testing.T) {
+	// 北京时间深夜的训练在 UTC 中属于同一天更早的时刻，按用户时区归到当天
+	instant := time.Date(2025, 11, 3, 16, 30, 0, 0, time.UTC)
+	assert.Equal(t, "2025-11-03", Date(instant, time.UTC))
+	assert.Equal(t, "2025-11-04", Date(instant, Location("Asia/Shanghai")))
+	assert.Equal(t, "2025-11-03", Date(instant, Location("America/New_York")))
+}
+
+func TestLocation(t *testing.T) {
+	assert.True(t, ValidLocation("Asia/Shanghai"))
+	assert.False(t, ValidLocation(""))
+	assert.False(t, ValidLocation("Mars/Olympus"))
+
+	previous := defaultLocation
+	t.Cleanup(func() { defaultLocation = previous })
+
+	require.NoError(t, SetDefaultLocation("Asia/Tokyo"))
+	assert.Equal(t, "Asia/Tokyo", Location("").String())
+	assert.Equal(t, "Asia/Tokyo", Location("Mars/Olympus").String())
+	assert.Equal(t, "Europe/Berlin", Location("Europe/Berlin").String())
+	assert.Error(t, SetDefaultLocation("Mars/Olympus"))
+}
+
+func TestDayRange(t *testing.T) {
+	shanghai := Location("Asia/Shanghai")
+	start, end, err := DayRange("2025-11-03", "2025-11-04", shanghai)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 11, 2, 16, 0, 0, 0, time.UTC), start.UTC())
+	assert.Equal(t, time.Date(2025, 11, 4, 16, 0, 0, 0, time.UTC), end.UTC())
+
+	_, _, err = DayRange("2025-11-03", "11/04", shanghai)
+	assert.ErrorIs(t, err, ErrInvalidDateTime)
+}
+
+func TestDaysBetween(t *testing.T) {
+	days, err := DaysBetween("2025-10-30", "2025-11-02")
+	require.NoError(t, err)
+	assert.Equal(t, 3, days)
+
+	days, err = DaysBetween("2025-11-02", "2025-10-26")
+	require.NoError(t, err)
+	assert.Equal(t, -7, days)
+
+	_, err = DaysBetween("2025-11-02", "")
+	assert.ErrorIs(t, err, ErrInvalidDateTime)
+}
@@ -0,0 +1,248 @@
+package tokenutil
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+const (
+	AlgHS256 = "HS256"
+	AlgRS256 = "RS256"
+	AlgEdDSA = "EdDSA"
+)
+
+// jwtKey 单个签名/校验密钥
+type jwtKey struct {
+	id      string
+	method  jwt.SigningMethod
+	private crypto.PrivateKey // 仅签名密钥需要
+	public  crypto.PublicKey
+}
+
+// KeySet domain.JwtKeySet 的实现
+type KeySet struct {
+	signing      *jwtKey
+	verification map[string]*jwtKey // kid -> 校验密钥
+	hmacSecret   []byte             // 非空时接受 HS256 令牌
+}
+
+// NewHMACKeySet 使用共享密钥的 HS256 密钥集合（兼容旧配置）
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{
+		signing:    &jwtKey{method: jwt.SigningMethodHS256, private: []byte(secret)},
+		hmacSecret: []byte(secret),
+	}
+}
+
+// LoadKeySet 从 PEM 文件加载非对称密钥集合
+// privateKeyFile 为当前签名私钥；verificationKeyFiles 为轮换前仍需校验的旧密钥（公钥或私钥 PEM 均可）
+// legacySecret 非空时同时接受 HS256 令牌，用于从共享密钥迁移期间的过渡
+func LoadKeySet(alg string, privateKeyFile string, verificationKeyFiles []string, legacySecret string) (*KeySet, error) {
+	if alg == AlgHS256 {
+		return NewHMACKeySet(legacySecret), nil
+	}
+
+	method, err := signingMethod(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(privateKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("read private key: %w", err)
+	}
+	signing, err := parsePrivateKey(method, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", privateKeyFile, err)
+	}
+
+	ks := &KeySet{
+		signing:      signing,
+		verification: map[string]*jwtKey{signing.id: signing},
+	}
+	if legacySecret != "" {
+		ks.hmacSecret = []byte(legacySecret)
+	}
+
+	for _, file := range verificationKeyFiles {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read verification key: %w", err)
+		}
+		key, err := parseVerificationKey(method, data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", file, err)
+		}
+		ks.verification[key.id] = key
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(ks.signing.method, claims)
+	if ks.signing.id != "" {
+		token.Header["kid"] = ks.signing.id
+	}
+	return token.SignedString(ks.signing.private)
+}
+
+func (ks *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	// 按算法类型分别取密钥，防止用公钥充当 HMAC 密钥的算法混淆攻击
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); ok {
+		if len(ks.hmacSecret) == 0 {
+			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+		}
+		return ks.hmacSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.verification[kid]
+	if !ok {
+		return nil, fmt.Errorf("Unknown key id: %v", token.Header["kid"])
+	}
+	if key.method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.public, nil
+}
+
+func (ks *KeySet) PublicKeys() []domain.JWK {
+	keys := []domain.JWK{}
+	// 签名密钥排在最前，其余按 kid 排序保证输出稳定
+	if len(ks.verification) > 0 {
+		keys = append(keys, toJWK(ks.signing))
+	}
+	for _, kid := range sortedKeys(ks.verification) {
+		if kid == ks.signing.id {
+			continue
+		}
+		keys = append(keys, toJWK(ks.verification[kid]))
+	}
+	return keys
+}
+
+func signingMethod(alg string) (jwt.SigningMethod, error) {
+	switch alg {
+	case AlgRS256:
+		return jwt.SigningMethodRS256, nil
+	case AlgEdDSA:
+		return jwt.SigningMethodEdDSA, nil
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm: %s", alg)
+	}
+}
+
+func parsePrivateKey(method jwt.SigningMethod, data []byte) (*jwtKey, error) {
+	var private crypto.PrivateKey
+	var public crypto.PublicKey
+	switch method {
+	case jwt.SigningMethodRS256:
+		key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		private, public = key, &key.PublicKey
+	case jwt.SigningMethodEdDSA:
+		key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, err
+		}
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, jwt.ErrNotEdPrivateKey
+		}
+		private, public = edKey, edKey.Public()
+	}
+
+	kid, err := keyID(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwtKey{id: kid, method: method, private: private, public: public}, nil
+}
+
+// parseVerificationKey 解析校验密钥，同时接受公钥和私钥 PEM
+func parseVerificationKey(method jwt.SigningMethod, data []byte) (*jwtKey, error) {
+	if key, err := parsePrivateKey(method, data); err == nil {
+		key.private = nil
+		return key, nil
+	}
+
+	var public crypto.PublicKey
+	var err error
+	switch method {
+	case jwt.SigningMethodRS256:
+		public, err = jwt.ParseRSAPublicKeyFromPEM(data)
+	case jwt.SigningMethodEdDSA:
+		public, err = jwt.ParseEdPublicKeyFromPEM(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	kid, err := keyID(public)
+	if err != nil {
+		return nil, err
+	}
+
+	return &jwtKey{id: kid, method: method, public: public}, nil
+}
+
+// keyID 由公钥 DER 编码的 SHA-256 摘要派生 kid，同一密钥在各服务中得到相同的 kid
+func keyID(public crypto.PublicKey) (string, error) {
+	der, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:12]), nil
+}
+
+func toJWK(key *jwtKey) domain.JWK {
+	jwk := domain.JWK{Kid: key.id, Use: "sig", Alg: key.method.Alg()}
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.Kty = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.Kty = "OKP"
+		jwk.Crv = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+func sortedKeys(m map[string]*jwtKey) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SplitKeyFiles 解析逗号分隔的密钥文件列表
+func SplitKeyFiles(files string) []string {
+	var result []string
+	for _, f := range strings.Split(files, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			result = append(result, f)
+		}
+	}
+	return result
+}
@@ -0,0 +1,206 @@
+package tokenutil
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	jwt "github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const legacySecret = "legacy-secret"
+
+// writePEM 将 DER 编码的密钥以 PEM 格式写入临时文件
+func writePEM(t *testing.T, name, blockType string, der []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600))
+	return path
+}
+
+// generateKey 生成指定算法的私钥，返回私钥文件、公钥文件和公钥
+func generateKey(t *testing.T, alg string) (privateFile, publicFile string, public interface{}) {
+	var private interface{}
+	switch alg {
+	case AlgRS256:
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		private, public = key, &key.PublicKey
+	case AlgEdDSA:
+		pub, key, err := ed25519.GenerateKey(rand.Reader)
+		require.NoError(t, err)
+		private, public = key, pub
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	require.NoError(t, err)
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	require.NoError(t, err)
+	return writePEM(t, "private.pem", "PRIVATE KEY", privateDER), writePEM(t, "public.pem", "PUBLIC KEY", publicDER), public
+}
+
+func signAccessToken(t *testing.T, keys domain.JwtKeySet) string {
+	user := &domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: domain.RoleUser}
+	token, err := CreateAccessToken(user, "", nil, keys, 1)
+	require.NoError(t, err)
+	return token
+}
+
+// signHS256 使用任意密钥签发 HS256 令牌
+func signHS256(t *testing.T, secret []byte) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &domain.JwtCustomClaims{Name: "alice"}).SignedString(secret)
+	require.NoError(t, err)
+	return token
+}
+
+func TestLoadKeySetRoundTrip(t *testing.T) {
+	for _, alg := range []string{AlgRS256, AlgEdDSA} {
+		t.Run(alg, func(t *testing.T) {
+			privateFile, _, public := generateKey(t, alg)
+			keys, err := LoadKeySet(alg, privateFile, nil, "")
+			require.NoError(t, err)
+
+			token := signAccessToken(t, keys)
+			claims, err := ParseAccessToken(token, keys)
+			require.NoError(t, err)
+			assert.Equal(t, "alice", claims.Name)
+
+			parsed, _, err := new(jwt.Parser).ParseUnverified(token, &domain.JwtCustomClaims{})
+			require.NoError(t, err)
+			kid, err := keyID(public)
+			require.NoError(t, err)
+			assert.Equal(t, alg, parsed.Header["alg"])
+			assert.Equal(t, kid, parsed.Header["kid"])
+		})
+	}
+}
+
+func TestKeySetRejectsAlgorithmConfusion(t *testing.T) {
+	privateFile, publicFile, _ := generateKey(t, AlgRS256)
+	publicPEM, err := os.ReadFile(publicFile)
+	require.NoError(t, err)
+
+	// 即使接受 HS256，用公钥充当 HMAC 密钥签发的令牌也会被拒绝
+	for _, secret := range []string{"", legacySecret} {
+		keys, err := LoadKeySet(AlgRS256, privateFile, nil, secret)
+		require.NoError(t, err)
+
+		_, err = ParseAccessToken(signHS256(t, publicPEM), keys)
+		assert.Error(t, err)
+	}
+}
+
+func TestKeySetAcceptHS256(t *testing.T) {
+	privateFile, _, _ := generateKey(t, AlgEdDSA)
+	legacyToken := signHS256(t, []byte(legacySecret))
+
+	t.Run("off", func(t *testing.T) {
+		keys, err := LoadKeySet(AlgEdDSA, privateFile, nil, "")
+		require.NoError(t, err)
+
+		_, err = ParseAccessToken(legacyToken, keys)
+		assert.Error(t, err)
+	})
+
+	t.Run("on", func(t *testing.T) {
+		keys, err := LoadKeySet(AlgEdDSA, privateFile, nil, legacySecret)
+		require.NoError(t, err)
+
+		_, err = ParseAccessToken(legacyToken, keys)
+		assert.NoError(t, err)
+		_, err = ParseAccessToken(signHS256(t, []byte("other-secret")), keys)
+		assert.Error(t, err)
+
+		// 新令牌仍使用非对称算法签发
+		_, err = ParseAccessToken(signAccessToken(t, keys), keys)
+		assert.NoError(t, err)
+	})
+}
+
+func TestKeySetRotation(t *testing.T) {
+	oldPrivate, oldPublic, _ := generateKey(t, AlgEdDSA)
+	newPrivate, _, _ := generateKey(t, AlgEdDSA)
+
+	oldKeys, err := LoadKeySet(AlgEdDSA, oldPrivate, nil, "")
+	require.NoError(t, err)
+	oldToken := signAccessToken(t, oldKeys)
+
+	// 未登记旧密钥时 kid 未知
+	keys, err := LoadKeySet(AlgEdDSA, newPrivate, nil, "")
+	require.NoError(t, err)
+	_, err = ParseAccessToken(oldToken, keys)
+	assert.ErrorContains(t, err, "Unknown key id")
+
+	// 旧公钥留在校验密钥中时，轮换前签发的令牌仍然有效
+	keys, err = LoadKeySet(AlgEdDSA, newPrivate, []string{oldPublic}, "")
+	require.NoError(t, err)
+	_, err = ParseAccessToken(oldToken, keys)
+	assert.NoError(t, err)
+
+	// 校验密钥的算法与令牌不一致时拒绝
+	rsaPrivate, _, _ := generateKey(t, AlgRS256)
+	rsaKeys, err := LoadKeySet(AlgRS256, rsaPrivate, nil, "")
+	require.NoError(t, err)
+	_, err = ParseAccessToken(oldToken, rsaKeys)
+	assert.Error(t, err)
+}
+
+func TestKeySetPublicKeys(t *testing.T) {
+	rsaPrivate, rsaPublicFile, rsaPublic := generateKey(t, AlgRS256)
+	keys, err := LoadKeySet(AlgRS256, rsaPrivate, nil, "")
+	require.NoError(t, err)
+
+	rsaKid, err := keyID(rsaPublic)
+	require.NoError(t, err)
+	jwks := keys.PublicKeys()
+	require.Len(t, jwks, 1)
+	assert.Equal(t, domain.JWK{
+		Kty: "RSA",
+		Kid: rsaKid,
+		Use: "sig",
+		Alg: AlgRS256,
+		N:   base64.RawURLEncoding.EncodeToString(rsaPublic.(*rsa.PublicKey).N.Bytes()),
+		E:   "AQAB",
+	}, jwks[0])
+
+	// 同一密钥以公钥或私钥形式加载得到相同的 kid
+	fromPublic, err := LoadKeySet(AlgRS256, rsaPrivate, []string{rsaPublicFile}, "")
+	require.NoError(t, err)
+	assert.Len(t, fromPublic.PublicKeys(), 1)
+
+	edPrivate, _, edPublic := generateKey(t, AlgEdDSA)
+	oldPrivate, _, oldPublic := generateKey(t, AlgEdDSA)
+	keys, err = LoadKeySet(AlgEdDSA, edPrivate, []string{oldPrivate}, "")
+	require.NoError(t, err)
+
+	// 签名密钥排在最前，作为校验密钥加载的私钥只发布公钥
+	jwks = keys.PublicKeys()
+	require.Len(t, jwks, 2)
+	edKid, err := keyID(edPublic)
+	require.NoError(t, err)
+	oldKid, err := keyID(oldPublic)
+	require.NoError(t, err)
+	assert.Equal(t, edKid, jwks[0].Kid)
+	assert.Equal(t, oldKid, jwks[1].Kid)
+	assert.Equal(t, "OKP", jwks[1].Kty)
+	assert.Equal(t, "Ed25519", jwks[1].Crv)
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(oldPublic.(ed25519.PublicKey)), jwks[1].X)
+	assert.Nil(t, keys.verification[oldKid].private)
+
+	// 共享密钥不对外发布
+	assert.Empty(t, NewHMACKeySet(legacySecret).PublicKeys())
+}
+
+func TestSplitKeyFiles(t *testing.T) {
+	assert.Equal(t, []string{"a.pem", "b.pem"}, SplitKeyFiles(" a.pem, ,b.pem "))
+	assert.Nil(t, SplitKeyFiles(""))
+}
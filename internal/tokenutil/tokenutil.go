@@ -8,59 +8,87 @@ import (
 	"github.com/zhengshui/flow-link-server/domain"
 )
 
-func CreateAccessToken(user *domain.User, secret string, expiry int) (accessToken string, err error) {
+func CreateAccessToken(user *domain.User, sessionID string, permissions []string, keys domain.JwtKeySet, expiry int) (accessToken string, err error) {
 	exp := time.Now().Add(time.Hour * time.Duration(expiry)).Unix()
 	claims := &domain.JwtCustomClaims{
-		Name: user.Username,
-		ID:   user.ID.Hex(),
-		Role: user.Role,
+		Name:        user.Username,
+		ID:          user.ID.Hex(),
+		Role:        user.Role,
+		SessionID:   sessionID,
+		Roles:       user.RoleNames(),
+		Permissions: permissions,
 		StandardClaims: jwt.StandardClaims{
 			ExpiresAt: exp,
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	t, err := token.SignedString([]byte(secret))
+	t, err := keys.Sign(claims)
 	if err != nil {
 		return "", err
 	}
 	return t, err
 }
 
-func CreateRefreshToken(user *domain.User, secret string, expiry int) (refreshToken string, err error) {
+func CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (refreshToken string, err error) {
+	return CreateRefreshTokenWithID(user, "", "", keys, expiry)
+}
+
+// CreateRefreshTokenWithID 签发带令牌ID(jti)和家族ID的刷新令牌，用于服务端轮换校验
+func CreateRefreshTokenWithID(user *domain.User, tokenID, familyID string, keys domain.JwtKeySet, expiry int) (refreshToken string, err error) {
 	claimsRefresh := &domain.JwtCustomRefreshClaims{
-		ID: user.ID.Hex(),
+		ID:       user.ID.Hex(),
+		FamilyID: familyID,
 		StandardClaims: jwt.StandardClaims{
+			Id:        tokenID,
 			ExpiresAt: time.Now().Add(time.Hour * time.Duration(expiry)).Unix(),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claimsRefresh)
-	rt, err := token.SignedString([]byte(secret))
+	rt, err := keys.Sign(claimsRefresh)
 	if err != nil {
 		return "", err
 	}
 	return rt, err
 }
 
-func IsAuthorized(requestToken string, secret string) (bool, error) {
-	_, err := jwt.Parse(requestToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+// ParseAccessToken 校验访问令牌签名和有效期并返回其声明
+func ParseAccessToken(requestToken string, keys domain.JwtKeySet) (*domain.JwtCustomClaims, error) {
+	claims := &domain.JwtCustomClaims{}
+	token, err := jwt.ParseWithClaims(requestToken, claims, keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, fmt.Errorf("Invalid Token")
+	}
+
+	return claims, nil
+}
+
+// ParseRefreshToken 校验刷新令牌签名和有效期并返回其声明
+func ParseRefreshToken(requestToken string, keys domain.JwtKeySet) (*domain.JwtCustomRefreshClaims, error) {
+	claims := &domain.JwtCustomRefreshClaims{}
+	token, err := jwt.ParseWithClaims(requestToken, claims, keys.Keyfunc)
+	if err != nil {
+		return nil, err
+	}
+
+	if !token.Valid {
+		return nil, fmt.Errorf("Invalid Token")
+	}
+
+	return claims, nil
+}
+
+func IsAuthorized(requestToken string, keys domain.JwtKeySet) (bool, error) {
+	_, err := jwt.Parse(requestToken, keys.Keyfunc)
 	if err != nil {
 		return false, err
 	}
 	return true, nil
 }
 
-func ExtractIDFromToken(requestToken string, secret string) (string, error) {
-	token, err := jwt.Parse(requestToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+func ExtractIDFromToken(requestToken string, keys domain.JwtKeySet) (string, error) {
+	token, err := jwt.Parse(requestToken, keys.Keyfunc)
 
 	if err != nil {
 		return "", err
@@ -75,13 +103,8 @@ func ExtractIDFromToken(requestToken string, secret string) (string, error) {
 	return claims["id"].(string), nil
 }
 
-func ExtractRoleFromToken(requestToken string, secret string) (string, error) {
-	token, err := jwt.Parse(requestToken, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("Unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(secret), nil
-	})
+func ExtractRoleFromToken(requestToken string, keys domain.JwtKeySet) (string, error) {
+	token, err := jwt.Parse(requestToken, keys.Keyfunc)
 
 	if err != nil {
 		return "", err
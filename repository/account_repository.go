@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// personalDataCollections 以 userId 关联用户的集合，注销账号时全部删除
+// plan_templates 中官方模板没有 userId，不受影响
+var personalDataCollections = []string{
+	domain.CollectionTrainingRecord,
+	domain.CollectionFitnessPlan,
+	domain.CollectionPlanTemplate,
+	domain.CollectionFeedback,
+	domain.CollectionSession,
+	domain.CollectionRefreshToken,
+	domain.CollectionPasswordReset,
+	domain.CollectionEmailVerification,
+	domain.CollectionPersonalRecord,
+	domain.CollectionTrainingRollup,
+	domain.CollectionBodyMetric,
+	domain.CollectionProgressPhoto,
+}
+
+type accountRepository struct {
+	database mongo.Database
+}
+
+func NewAccountRepository(db mongo.Database) domain.AccountRepository {
+	return &accountRepository{
+		database: db,
+	}
+}
+
+func (ar *accountRepository) GetUserData(c context.Context, userID string) (domain.AccountData, error) {
+	data := domain.AccountData{
+		TrainingRecords: []domain.TrainingRecord{},
+		FitnessPlans:    []domain.FitnessPlan{},
+		PlanTemplates:   []domain.PlanTemplate{},
+		Feedbacks:       []domain.Feedback{},
+		BodyMetrics:     []domain.BodyMetric{},
+		ProgressPhotos:  []domain.ProgressPhoto{},
+	}
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return data, err
+	}
+
+	err = ar.findAll(c, domain.CollectionTrainingRecord, userIDHex, &data.TrainingRecords)
+	if err != nil {
+		return data, err
+	}
+	err = ar.findAll(c, domain.CollectionFitnessPlan, userIDHex, &data.FitnessPlans)
+	if err != nil {
+		return data, err
+	}
+	err = ar.findAll(c, domain.CollectionPlanTemplate, userIDHex, &data.PlanTemplates)
+	if err != nil {
+		return data, err
+	}
+	err = ar.findAll(c, domain.CollectionFeedback, userIDHex, &data.Feedbacks)
+	if err != nil {
+		return data, err
+	}
+	err = ar.findAll(c, domain.CollectionBodyMetric, userIDHex, &data.BodyMetrics)
+	if err != nil {
+		return data, err
+	}
+	err = ar.findAll(c, domain.CollectionProgressPhoto, userIDHex, &data.ProgressPhotos)
+	return data, err
+}
+
+func (ar *accountRepository) DeleteUserData(c context.Context, userID string) error {
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range personalDataCollections {
+		_, err := ar.database.Collection(name).DeleteMany(c, bson.M{"userId": userIDHex})
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// findAll 按创建时间读取集合中属于该用户的全部文档，results 为空时保持调用方传入的空切片
+func (ar *accountRepository) findAll(c context.Context, name string, userID primitive.ObjectID, results interface{}) error {
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	cursor, err := ar.database.Collection(name).Find(c, bson.M{"userId": userID}, opts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	return cursor.All(c, results)
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type bodyMetricRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewBodyMetricRepository(db mongo.Database, collection string) domain.BodyMetricRepository {
+	return &bodyMetricRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (br *bodyMetricRepository) Create(c context.Context, metric *domain.BodyMetric) error {
+	collection := br.database.Collection(br.collection)
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	metric.CreatedAt = now
+	metric.UpdatedAt = now
+
+	_, err := collection.InsertOne(c, metric)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return domain.ErrBodyMetricConflict
+	}
+	return err
+}
+
+func (br *bodyMetricRepository) GetByID(c context.Context, id string) (domain.BodyMetric, error) {
+	collection := br.database.Collection(br.collection)
+
+	var metric domain.BodyMetric
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return metric, domain.ErrBodyMetricNotFound
+	}
+
+	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&metric)
+	if err == mongodriver.ErrNoDocuments {
+		return metric, domain.ErrBodyMetricNotFound
+	}
+	return metric, err
+}
+
+func (br *bodyMetricRepository) GetByUserID(c context.Context, userID, startDate, endDate string) ([]domain.BodyMetric, error) {
+	collection := br.database.Collection(br.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"userId": userIDHex}
+	dateFilter := bson.M{}
+	if startDate != "" {
+		dateFilter["$gte"] = startDate
+	}
+	if endDate != "" {
+		dateFilter["$lte"] = endDate
+	}
+	if len(dateFilter) > 0 {
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: 1}})
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics := []domain.BodyMetric{}
+	err = cursor.All(c, &metrics)
+	return metrics, err
+}
+
+func (br *bodyMetricRepository) Update(c context.Context, metric *domain.BodyMetric) error {
+	collection := br.database.Collection(br.collection)
+
+	metric.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{
+		"$set": bson.M{
+			"date":      metric.Date,
+			"weight":    metric.Weight,
+			"bodyFat":   metric.BodyFat,
+			"waist":     metric.Waist,
+			"chest":     metric.Chest,
+			"arm":       metric.Arm,
+			"thigh":     metric.Thigh,
+			"notes":     metric.Notes,
+			"updatedAt": metric.UpdatedAt,
+		},
+	}
+
+	result, err := collection.UpdateOne(c, bson.M{"_id": metric.ID}, update)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return domain.ErrBodyMetricConflict
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrBodyMetricNotFound
+	}
+	return nil
+}
+
+func (br *bodyMetricRepository) Delete(c context.Context, id string) error {
+	collection := br.database.Collection(br.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrBodyMetricNotFound
+	}
+
+	deleted, err := collection.DeleteOne(c, bson.M{"_id": idHex})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrBodyMetricNotFound
+	}
+	return nil
+}
+
+func (br *bodyMetricRepository) UpsertWeight(c context.Context, userID, date string, weight float64) error {
+	collection := br.database.Collection(br.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	filter := bson.M{"userId": userIDHex, "date": date}
+	update := bson.M{
+		"$set": bson.M{"weight": weight, "updatedAt": now},
+		"$setOnInsert": bson.M{
+			"_id":       primitive.NewObjectID(),
+			"createdAt": now,
+		},
+	}
+
+	_, err = collection.UpdateOne(c, filter, update, options.Update().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type emailVerificationRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewEmailVerificationRepository(db mongo.Database, collection string) domain.EmailVerificationRepository {
+	return &emailVerificationRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (er *emailVerificationRepository) Create(c context.Context, verification *domain.EmailVerification) error {
+	collection := er.database.Collection(er.collection)
+	verification.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+	_, err := collection.InsertOne(c, verification)
+	return err
+}
+
+func (er *emailVerificationRepository) GetLatestByUserID(c context.Context, userID string) (domain.EmailVerification, error) {
+	collection := er.database.Collection(er.collection)
+
+	var verification domain.EmailVerification
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return verification, err
+	}
+
+	filter := bson.M{
+		"userId": userIDHex,
+		"usedAt": bson.M{"$exists": false},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: -1}}).SetLimit(1)
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return verification, err
+	}
+
+	var verifications []domain.EmailVerification
+	if err = cursor.All(c, &verifications); err != nil {
+		return verification, err
+	}
+	if len(verifications) == 0 {
+		return verification, mongodriver.ErrNoDocuments
+	}
+
+	return verifications[0], nil
+}
+
+func (er *emailVerificationRepository) IncrementAttempts(c context.Context, id string) error {
+	collection := er.database.Collection(er.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, bson.M{"$inc": bson.M{"attempts": 1}})
+	return err
+}
+
+func (er *emailVerificationRepository) MarkUsed(c context.Context, id string) (bool, error) {
+	collection := er.database.Collection(er.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// 只有未使用的验证码才能被消费，保证并发提交时只有一个请求成功
+	filter := bson.M{
+		"_id":    idHex,
+		"usedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"usedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (er *emailVerificationRepository) InvalidateByUserID(c context.Context, userID string) error {
+	collection := er.database.Collection(er.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"userId": userIDHex,
+		"usedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"usedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateMany(c, filter, update)
+	return err
+}
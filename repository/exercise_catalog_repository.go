@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type exerciseCatalogRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewExerciseCatalogRepository(db mongo.Database, collection string) domain.ExerciseCatalogRepository {
+	return &exerciseCatalogRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (er *exerciseCatalogRepository) Create(c context.Context, exercise *domain.CatalogExercise) error {
+	collection := er.database.Collection(er.collection)
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	exercise.CreatedAt = now
+	exercise.UpdatedAt = now
+
+	_, err := collection.InsertOne(c, exercise)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return domain.ErrExerciseConflict
+	}
+	return err
+}
+
+func (er *exerciseCatalogRepository) GetByID(c context.Context, id string) (domain.CatalogExercise, error) {
+	collection := er.database.Collection(er.collection)
+
+	var exercise domain.CatalogExercise
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return exercise, domain.ErrExerciseNotFound
+	}
+
+	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&exercise)
+	if err == mongodriver.ErrNoDocuments {
+		return exercise, domain.ErrExerciseNotFound
+	}
+	return exercise, err
+}
+
+func (er *exerciseCatalogRepository) GetByIDs(c context.Context, ids []string) ([]domain.CatalogExercise, error) {
+	collection := er.database.Collection(er.collection)
+
+	objectIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		idHex, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		objectIDs = append(objectIDs, idHex)
+	}
+	if len(objectIDs) == 0 {
+		return []domain.CatalogExercise{}, nil
+	}
+
+	cursor, err := collection.Find(c, bson.M{"_id": bson.M{"$in": objectIDs}})
+	if err != nil {
+		return nil, err
+	}
+
+	var exercises []domain.CatalogExercise
+	err = cursor.All(c, &exercises)
+	if exercises == nil {
+		return []domain.CatalogExercise{}, err
+	}
+
+	return exercises, err
+}
+
+func (er *exerciseCatalogRepository) Update(c context.Context, exercise *domain.CatalogExercise) error {
+	collection := er.database.Collection(er.collection)
+
+	exercise.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{
+		"$set": bson.M{
+			"name":             exercise.Name,
+			"aliases":          exercise.Aliases,
+			"primaryMuscles":   exercise.PrimaryMuscles,
+			"secondaryMuscles": exercise.SecondaryMuscles,
+			"equipment":        exercise.Equipment,
+			"movementPattern":  exercise.MovementPattern,
+			"category":         exercise.Category,
+			"met":              exercise.MET,
+			"searchTerms":      exercise.SearchTerms,
+			"updatedAt":        exercise.UpdatedAt,
+		},
+	}
+
+	result, err := collection.UpdateOne(c, bson.M{"_id": exercise.ID}, update)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return domain.ErrExerciseConflict
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return domain.ErrExerciseNotFound
+	}
+	return nil
+}
+
+func (er *exerciseCatalogRepository) Delete(c context.Context, id string) error {
+	collection := er.database.Collection(er.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrExerciseNotFound
+	}
+
+	deleted, err := collection.DeleteOne(c, bson.M{"_id": idHex})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrExerciseNotFound
+	}
+	return nil
+}
+
+func (er *exerciseCatalogRepository) Search(c context.Context, keyword, muscle, equipment string, page, pageSize int) ([]domain.CatalogExercise, int64, error) {
+	collection := er.database.Collection(er.collection)
+
+	filter := bson.M{}
+	if term := domain.NormalizeExerciseTerm(keyword); term != "" {
+		filter["searchTerms"] = primitive.Regex{Pattern: regexp.QuoteMeta(term)}
+	}
+	if muscle != "" {
+		filter["$or"] = []bson.M{
+			{"primaryMuscles": muscle},
+			{"secondaryMuscles": muscle},
+		}
+	}
+	if equipment != "" {
+		filter["equipment"] = equipment
+	}
+
+	total, err := collection.CountDocuments(c, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (page - 1) * pageSize
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var exercises []domain.CatalogExercise
+	err = cursor.All(c, &exercises)
+	if exercises == nil {
+		return []domain.CatalogExercise{}, total, err
+	}
+
+	return exercises, total, err
+}
+
+func (er *exerciseCatalogRepository) Autocomplete(c context.Context, prefix string, limit int) ([]domain.CatalogExercise, error) {
+	collection := er.database.Collection(er.collection)
+
+	// 锚定开头的区分大小写正则可以走 searchTerms 索引
+	filter := bson.M{
+		"searchTerms": primitive.Regex{Pattern: "^" + regexp.QuoteMeta(domain.NormalizeExerciseTerm(prefix))},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "name", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var exercises []domain.CatalogExercise
+	err = cursor.All(c, &exercises)
+	if exercises == nil {
+		return []domain.CatalogExercise{}, err
+	}
+
+	return exercises, err
+}
+
+func (er *exerciseCatalogRepository) FindByTerms(c context.Context, terms []string) ([]domain.CatalogExercise, error) {
+	collection := er.database.Collection(er.collection)
+
+	if len(terms) == 0 {
+		return []domain.CatalogExercise{}, nil
+	}
+
+	cursor, err := collection.Find(c, bson.M{"searchTerms": bson.M{"$in": terms}})
+	if err != nil {
+		return nil, err
+	}
+
+	var exercises []domain.CatalogExercise
+	err = cursor.All(c, &exercises)
+	if exercises == nil {
+		return []domain.CatalogExercise{}, err
+	}
+
+	return exercises, err
+}
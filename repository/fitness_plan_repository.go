@@ -9,6 +9,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
 )
 
 type fitnessPlanRepository struct {
@@ -37,10 +39,13 @@ func (fp *fitnessPlanRepository) GetByID(c context.Context, id string) (domain.F
 
 	idHex, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return plan, err
+		return plan, domain.ErrFitnessPlanNotFound
 	}
 
 	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&plan)
+	if err == mongodriver.ErrNoDocuments {
+		return plan, domain.ErrFitnessPlanNotFound
+	}
 	return plan, err
 }
 
@@ -158,11 +163,14 @@ func (fp *fitnessPlanRepository) CompletePlanDay(c context.Context, id string, d
 		return err
 	}
 
-	// 添加dayNumber到completedDays数组，如果不存在的话
+	// 添加dayNumber到completedDays数组，如果不存在的话；完成的天不再算作跳过
 	update := bson.M{
 		"$addToSet": bson.M{
 			"completedDays": dayNumber,
 		},
+		"$pull": bson.M{
+			"skippedDays": dayNumber,
+		},
 		"$set": bson.M{
 			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
 		},
@@ -180,7 +188,7 @@ func (fp *fitnessPlanRepository) CompletePlanDay(c context.Context, id string, d
 		return err
 	}
 
-	totalDays := plan.DurationWeeks * plan.TrainingDaysPerWeek
+	totalDays := plan.TotalSessions()
 	totalCompletedDays := len(plan.CompletedDays)
 	completionRate := 0
 	if totalDays > 0 {
@@ -230,7 +238,7 @@ func (fp *fitnessPlanRepository) UncompletePlanDay(c context.Context, id string,
 		return err
 	}
 
-	totalDays := plan.DurationWeeks * plan.TrainingDaysPerWeek
+	totalDays := plan.TotalSessions()
 	totalCompletedDays := len(plan.CompletedDays)
 	completionRate := 0
 	if totalDays > 0 {
@@ -250,7 +258,7 @@ func (fp *fitnessPlanRepository) UncompletePlanDay(c context.Context, id string,
 	return err
 }
 
-func (fp *fitnessPlanRepository) SkipPlanDay(c context.Context, id string, dayNumber int) error {
+func (fp *fitnessPlanRepository) SkipPlanDay(c context.Context, id string, dayNumber int, reason string) error {
 	collection := fp.database.Collection(fp.collection)
 
 	idHex, err := primitive.ObjectIDFromHex(id)
@@ -258,13 +266,17 @@ func (fp *fitnessPlanRepository) SkipPlanDay(c context.Context, id string, dayNu
 		return err
 	}
 
-	// 添加dayNumber到skippedDays数组，如果不存在的话
+	// 添加dayNumber到skippedDays数组，如果不存在的话，同时留存跳过原因
+	now := primitive.NewDateTimeFromTime(time.Now())
 	update := bson.M{
 		"$addToSet": bson.M{
 			"skippedDays": dayNumber,
 		},
+		"$push": bson.M{
+			"skipLog": domain.PlanSkip{DayNumber: dayNumber, Reason: reason, SkippedAt: now},
+		},
 		"$set": bson.M{
-			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+			"updatedAt": now,
 		},
 	}
 
@@ -280,7 +292,7 @@ func (fp *fitnessPlanRepository) SkipPlanDay(c context.Context, id string, dayNu
 		return err
 	}
 
-	totalDays := plan.DurationWeeks * plan.TrainingDaysPerWeek
+	totalDays := plan.TotalSessions()
 	skippedDays := len(plan.SkippedDays)
 	completedDays := len(plan.CompletedDays)
 	// 完成率 = (已完成 / (总天数 - 跳过天数)) * 100
@@ -369,3 +381,52 @@ func (fp *fitnessPlanRepository) UpdateTrainingDay(c context.Context, id string,
 	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
 	return err
 }
+
+func (fp *fitnessPlanRepository) SetTotals(c context.Context, id string, totals domain.PlanTotals) error {
+	collection := fp.database.Collection(fp.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"totalWeight":   totals.TotalWeight,
+			"totalDuration": totals.TotalDuration,
+			"totalCalories": totals.TotalCalories,
+			"updatedAt":     primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
+
+func (fp *fitnessPlanRepository) UpdateSchedule(c context.Context, id string, plan *domain.FitnessPlan) error {
+	collection := fp.database.Collection(fp.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	plan.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	update := bson.M{
+		"$set": bson.M{
+			"completedDays":        plan.CompletedDays,
+			"skippedDays":          plan.SkippedDays,
+			"skipLog":              plan.SkipLog,
+			"trainingDaysOverride": plan.TrainingDaysOverride,
+			"reschedules":          plan.Reschedules,
+			"endDate":              plan.EndDate,
+			"totalCompletedDays":   plan.TotalCompletedDays,
+			"completionRate":       plan.CompletionRate,
+			"updatedAt":            plan.UpdatedAt,
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
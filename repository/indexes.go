@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// collectionIndexes 各集合需要的索引定义
+func collectionIndexes() map[string][]mongodriver.IndexModel {
+	return map[string][]mongodriver.IndexModel{
+		domain.CollectionRefreshToken: {
+			{Keys: bson.D{{Key: "familyId", Value: 1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+			// 过期的刷新令牌由 MongoDB TTL 自动清理
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		domain.CollectionLoginAttempt: {
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		domain.CollectionPasswordReset: {
+			{Keys: bson.D{{Key: "tokenHash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "userId", Value: 1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		domain.CollectionRole: {
+			{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		domain.CollectionUser: {
+			{Keys: bson.D{{Key: "username", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// 邮箱、手机号选填，部分索引只约束非空值
+			{
+				Keys:    bson.D{{Key: "email", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"email": bson.M{"$type": "string", "$gt": ""}}),
+			},
+			{
+				Keys:    bson.D{{Key: "phone", Value: 1}},
+				Options: options.Index().SetUnique(true).SetPartialFilterExpression(bson.M{"phone": bson.M{"$type": "string", "$gt": ""}}),
+			},
+			{Keys: bson.D{{Key: "deletionScheduledAt", Value: 1}}, Options: options.Index().SetSparse(true)},
+		},
+		domain.CollectionEmailVerification: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		domain.CollectionExerciseCatalog: {
+			{Keys: bson.D{{Key: "name", Value: 1}}, Options: options.Index().SetUnique(true)},
+			// 名称和别名的规范化词，精确匹配与前缀补全共用
+			{Keys: bson.D{{Key: "searchTerms", Value: 1}}},
+			{Keys: bson.D{{Key: "primaryMuscles", Value: 1}}},
+		},
+		domain.CollectionPersonalRecord: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "exerciseKey", Value: 1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "recordId", Value: 1}}},
+		},
+		domain.CollectionTrainingRecord: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "startTime", Value: -1}, {Key: "createdAt", Value: -1}}},
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "planId", Value: 1}, {Key: "startTime", Value: -1}}},
+			{Keys: bson.D{{Key: "planId", Value: 1}, {Key: "planDayId", Value: 1}}},
+		},
+		domain.CollectionTrainingRollup: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "period", Value: 1}, {Key: "periodStart", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		domain.CollectionBodyMetric: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		domain.CollectionProgressPhoto: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "date", Value: -1}, {Key: "createdAt", Value: -1}}},
+		},
+		domain.CollectionSession: {
+			{Keys: bson.D{{Key: "userId", Value: 1}, {Key: "lastUsedAt", Value: -1}}},
+			{Keys: bson.D{{Key: "expiresAt", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+	}
+}
+
+// indexName 按 MongoDB 默认规则生成索引名，如 username_1、userId_1_date_-1
+func indexName(keys interface{}) string {
+	parts := []string{}
+	if d, ok := keys.(bson.D); ok {
+		for _, e := range d {
+			parts = append(parts, fmt.Sprintf("%s_%v", e.Key, e.Value))
+		}
+	}
+	return strings.Join(parts, "_")
+}
+
+// EnsureIndexes 启动时创建索引，已存在的索引会被忽略
+// 索引逐个创建，某个索引失败（例如已有重复的邮箱导致唯一索引无法建立）不影响其它索引，
+// 返回所有失败的索引及原因
+func EnsureIndexes(c context.Context, db mongo.Database) error {
+	var errs []error
+	for collection, models := range collectionIndexes() {
+		for _, model := range models {
+			if _, err := db.Collection(collection).CreateIndexes(c, []mongodriver.IndexModel{model}); err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s: %w", collection, indexName(model.Keys), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
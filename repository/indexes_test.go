@@ -0,0 +1,42 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo/mocks"
+	"github.com/zhengshui/flow-link-server/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// indexOn 匹配只包含指定字段索引的 CreateIndexes 调用
+func indexOn(field string) interface{} {
+	return mock.MatchedBy(func(models []mongodriver.IndexModel) bool {
+		return len(models) == 1 && models[0].Keys.(bson.D)[0].Key == field
+	})
+}
+
+func TestEnsureIndexesCreatesEachIndex(t *testing.T) {
+	users := mocks.NewCollection(t)
+	// 已有重复邮箱时邮箱唯一索引创建失败，用户名、手机号索引仍然创建
+	users.On("CreateIndexes", mock.Anything, indexOn("email")).Return(nil, assert.AnError).Once()
+	users.On("CreateIndexes", mock.Anything, indexOn("username")).Return([]string{"username_1"}, nil).Once()
+	users.On("CreateIndexes", mock.Anything, indexOn("phone")).Return([]string{"phone_1"}, nil).Once()
+	users.On("CreateIndexes", mock.Anything, indexOn("deletionScheduledAt")).Return([]string{"deletionScheduledAt_1"}, nil).Once()
+
+	others := mocks.NewCollection(t)
+	others.On("CreateIndexes", mock.Anything, mock.Anything).Return([]string{}, nil)
+
+	db := mocks.NewDatabase(t)
+	db.On("Collection", domain.CollectionUser).Return(users)
+	db.On("Collection", mock.Anything).Return(others)
+
+	err := repository.EnsureIndexes(context.Background(), db)
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.ErrorContains(t, err, domain.CollectionUser+".email_1")
+	assert.NotContains(t, err.Error(), "username_1")
+}
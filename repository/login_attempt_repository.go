@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type loginAttemptRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewLoginAttemptRepository(db mongo.Database, collection string) domain.LoginAttemptRepository {
+	return &loginAttemptRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (lr *loginAttemptRepository) GetByKey(c context.Context, key string) (domain.LoginAttempt, error) {
+	collection := lr.database.Collection(lr.collection)
+	var attempt domain.LoginAttempt
+	err := collection.FindOne(c, bson.M{"key": key}).Decode(&attempt)
+	if errors.Is(err, mongodriver.ErrNoDocuments) {
+		// 没有失败记录视为零次失败
+		return domain.LoginAttempt{Key: key}, nil
+	}
+	return attempt, err
+}
+
+func (lr *loginAttemptRepository) IncrementFailures(c context.Context, key string, expiresAt primitive.DateTime) (domain.LoginAttempt, error) {
+	collection := lr.database.Collection(lr.collection)
+
+	// 过期时间只后移，不会提前到仍在锁定中的截止时间之前
+	update := bson.M{
+		"$inc":         bson.M{"failures": 1},
+		"$set":         bson.M{"lastFailedAt": primitive.NewDateTimeFromTime(time.Now())},
+		"$max":         bson.M{"expiresAt": expiresAt},
+		"$setOnInsert": bson.M{"lockouts": 0},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := collection.UpdateOne(c, bson.M{"key": key}, update, opts)
+	if err != nil {
+		return domain.LoginAttempt{}, err
+	}
+
+	return lr.GetByKey(c, key)
+}
+
+func (lr *loginAttemptRepository) Lock(c context.Context, key string, lockedUntil primitive.DateTime) error {
+	collection := lr.database.Collection(lr.collection)
+
+	update := bson.M{
+		"$inc": bson.M{"lockouts": 1},
+		"$set": bson.M{
+			"failures":    0,
+			"lockedUntil": lockedUntil,
+		},
+		// 锁定结束前不能被 TTL 索引清理
+		"$max": bson.M{"expiresAt": lockedUntil},
+	}
+
+	_, err := collection.UpdateOne(c, bson.M{"key": key}, update)
+	return err
+}
+
+func (lr *loginAttemptRepository) DeleteByKey(c context.Context, key string) error {
+	collection := lr.database.Collection(lr.collection)
+	_, err := collection.DeleteOne(c, bson.M{"key": key})
+	return err
+}
+
+func (lr *loginAttemptRepository) DeleteByKeys(c context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	collection := lr.database.Collection(lr.collection)
+	_, err := collection.DeleteMany(c, bson.M{"key": bson.M{"$in": keys}})
+	return err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type passwordResetRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewPasswordResetRepository(db mongo.Database, collection string) domain.PasswordResetRepository {
+	return &passwordResetRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (pr *passwordResetRepository) Create(c context.Context, reset *domain.PasswordReset) error {
+	collection := pr.database.Collection(pr.collection)
+	reset.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+	_, err := collection.InsertOne(c, reset)
+	return err
+}
+
+func (pr *passwordResetRepository) GetByTokenHash(c context.Context, tokenHash string) (domain.PasswordReset, error) {
+	collection := pr.database.Collection(pr.collection)
+	var reset domain.PasswordReset
+	err := collection.FindOne(c, bson.M{"tokenHash": tokenHash}).Decode(&reset)
+	return reset, err
+}
+
+func (pr *passwordResetRepository) MarkUsed(c context.Context, id string) (bool, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// 只有未使用的令牌才能被消费，保证同一令牌并发提交时只有一个请求成功
+	filter := bson.M{
+		"_id":    idHex,
+		"usedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"usedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (pr *passwordResetRepository) InvalidateByUserID(c context.Context, userID string) error {
+	collection := pr.database.Collection(pr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"userId": userIDHex,
+		"usedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"usedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateMany(c, filter, update)
+	return err
+}
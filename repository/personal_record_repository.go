@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type personalRecordRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewPersonalRecordRepository(db mongo.Database, collection string) domain.PersonalRecordRepository {
+	return &personalRecordRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (pr *personalRecordRepository) CreateMany(c context.Context, events []domain.PersonalRecordEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	collection := pr.database.Collection(pr.collection)
+
+	documents := make([]interface{}, len(events))
+	for i := range events {
+		documents[i] = events[i]
+	}
+
+	_, err := collection.InsertMany(c, documents)
+	return err
+}
+
+func (pr *personalRecordRepository) CountByUserID(c context.Context, userID string) (int64, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return 0, err
+	}
+
+	return collection.CountDocuments(c, bson.M{"userId": userIDHex})
+}
+
+func (pr *personalRecordRepository) GetByExerciseKeys(c context.Context, userID string, exerciseKeys []string, excludeRecordID string) ([]domain.PersonalRecordEvent, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"userId":      userIDHex,
+		"exerciseKey": bson.M{"$in": exerciseKeys},
+	}
+	if excludeRecordID != "" {
+		recordIDHex, err := primitive.ObjectIDFromHex(excludeRecordID)
+		if err != nil {
+			return nil, err
+		}
+		filter["recordId"] = bson.M{"$ne": recordIDHex}
+	}
+
+	cursor, err := collection.Find(c, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []domain.PersonalRecordEvent
+	err = cursor.All(c, &events)
+	if events == nil {
+		return []domain.PersonalRecordEvent{}, err
+	}
+
+	return events, err
+}
+
+func (pr *personalRecordRepository) GetTimeline(c context.Context, userID, exerciseKey string, page, pageSize int) ([]domain.PersonalRecordEvent, int64, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter := bson.M{"userId": userIDHex}
+	if exerciseKey != "" {
+		filter["exerciseKey"] = exerciseKey
+	}
+
+	total, err := collection.CountDocuments(c, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	skip := (page - 1) * pageSize
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date", Value: -1}, {Key: "createdAt", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var events []domain.PersonalRecordEvent
+	err = cursor.All(c, &events)
+	if events == nil {
+		return []domain.PersonalRecordEvent{}, total, err
+	}
+
+	return events, total, err
+}
+
+func (pr *personalRecordRepository) DeleteByRecordID(c context.Context, recordID string) error {
+	collection := pr.database.Collection(pr.collection)
+
+	recordIDHex, err := primitive.ObjectIDFromHex(recordID)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteMany(c, bson.M{"recordId": recordIDHex})
+	return err
+}
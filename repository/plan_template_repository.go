@@ -162,6 +162,7 @@ func (pt *planTemplateRepository) Update(c context.Context, id string, template
 			"durationWeeks":        template.DurationWeeks,
 			"trainingDaysPerWeek":  template.TrainingDaysPerWeek,
 			"trainingDays":         template.TrainingDays,
+			"phases":               template.Phases,
 			"tags":                 template.Tags,
 			"imageUrl":             template.ImageUrl,
 			"recommendedIntensity": template.RecommendedIntensity,
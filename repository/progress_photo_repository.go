@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type progressPhotoRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewProgressPhotoRepository(db mongo.Database, collection string) domain.ProgressPhotoRepository {
+	return &progressPhotoRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (pr *progressPhotoRepository) Create(c context.Context, photo *domain.ProgressPhoto) error {
+	collection := pr.database.Collection(pr.collection)
+
+	photo.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	_, err := collection.InsertOne(c, photo)
+	return err
+}
+
+func (pr *progressPhotoRepository) GetByID(c context.Context, id string) (domain.ProgressPhoto, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	var photo domain.ProgressPhoto
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return photo, domain.ErrProgressPhotoNotFound
+	}
+
+	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&photo)
+	if err == mongodriver.ErrNoDocuments {
+		return photo, domain.ErrProgressPhotoNotFound
+	}
+	return photo, err
+}
+
+func (pr *progressPhotoRepository) GetByUserID(c context.Context, userID, startDate, endDate string) ([]domain.ProgressPhoto, error) {
+	collection := pr.database.Collection(pr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"userId": userIDHex}
+	dateFilter := bson.M{}
+	if startDate != "" {
+		dateFilter["$gte"] = startDate
+	}
+	if endDate != "" {
+		dateFilter["$lte"] = endDate
+	}
+	if len(dateFilter) > 0 {
+		filter["date"] = dateFilter
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "date", Value: -1}, {Key: "createdAt", Value: -1}})
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	photos := []domain.ProgressPhoto{}
+	err = cursor.All(c, &photos)
+	return photos, err
+}
+
+func (pr *progressPhotoRepository) Delete(c context.Context, id string) error {
+	collection := pr.database.Collection(pr.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrProgressPhotoNotFound
+	}
+
+	deleted, err := collection.DeleteOne(c, bson.M{"_id": idHex})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return domain.ErrProgressPhotoNotFound
+	}
+	return nil
+}
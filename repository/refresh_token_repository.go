@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type refreshTokenRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewRefreshTokenRepository(db mongo.Database, collection string) domain.RefreshTokenRepository {
+	return &refreshTokenRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (rr *refreshTokenRepository) Create(c context.Context, token *domain.RefreshToken) error {
+	collection := rr.database.Collection(rr.collection)
+	token.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+	_, err := collection.InsertOne(c, token)
+	return err
+}
+
+func (rr *refreshTokenRepository) GetByID(c context.Context, id string) (domain.RefreshToken, error) {
+	collection := rr.database.Collection(rr.collection)
+	var token domain.RefreshToken
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return token, err
+	}
+
+	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&token)
+	return token, err
+}
+
+func (rr *refreshTokenRepository) MarkUsed(c context.Context, id string, replacedBy primitive.ObjectID) (bool, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// 只有未使用且未吊销的令牌才能被消费，保证并发刷新时只有一个请求成功
+	filter := bson.M{
+		"_id":       idHex,
+		"usedAt":    bson.M{"$exists": false},
+		"revokedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"usedAt":     primitive.NewDateTimeFromTime(time.Now()),
+			"replacedBy": replacedBy,
+		},
+	}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (rr *refreshTokenRepository) RevokeFamily(c context.Context, familyID string) error {
+	collection := rr.database.Collection(rr.collection)
+
+	familyIDHex, err := primitive.ObjectIDFromHex(familyID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"familyId":  familyIDHex,
+		"revokedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateMany(c, filter, update)
+	return err
+}
+
+func (rr *refreshTokenRepository) RevokeByUserID(c context.Context, userID string) error {
+	collection := rr.database.Collection(rr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"userId":    userIDHex,
+		"revokedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateMany(c, filter, update)
+	return err
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type roleRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewRoleRepository(db mongo.Database, collection string) domain.RoleRepository {
+	return &roleRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (rr *roleRepository) Fetch(c context.Context) ([]domain.Role, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := collection.Find(c, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []domain.Role
+	err = cursor.All(c, &roles)
+	if roles == nil {
+		return []domain.Role{}, err
+	}
+
+	return roles, err
+}
+
+func (rr *roleRepository) GetByNames(c context.Context, names []string) ([]domain.Role, error) {
+	collection := rr.database.Collection(rr.collection)
+
+	cursor, err := collection.Find(c, bson.M{"name": bson.M{"$in": names}})
+	if err != nil {
+		return nil, err
+	}
+
+	var roles []domain.Role
+	err = cursor.All(c, &roles)
+	if roles == nil {
+		return []domain.Role{}, err
+	}
+
+	return roles, err
+}
+
+func (rr *roleRepository) Upsert(c context.Context, role *domain.Role) error {
+	collection := rr.database.Collection(rr.collection)
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{
+		"$set": bson.M{
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"updatedAt":   now,
+		},
+		"$setOnInsert": bson.M{
+			"createdAt": now,
+		},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := collection.UpdateOne(c, bson.M{"name": role.Name}, update, opts)
+	return err
+}
+
+func (rr *roleRepository) InsertIfMissing(c context.Context, role *domain.Role) error {
+	collection := rr.database.Collection(rr.collection)
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{
+		"$setOnInsert": bson.M{
+			"description": role.Description,
+			"permissions": role.Permissions,
+			"createdAt":   now,
+			"updatedAt":   now,
+		},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := collection.UpdateOne(c, bson.M{"name": role.Name}, update, opts)
+	return err
+}
+
+// EnsureDefaultRoles 启动时写入内置角色，管理员修改过的角色保持不变；
+// 管理员角色不可修改，每次启动同步为最新的全部权限
+func EnsureDefaultRoles(c context.Context, db mongo.Database) error {
+	rr := NewRoleRepository(db, domain.CollectionRole)
+	for _, role := range domain.DefaultRoles() {
+		role := role
+		var err error
+		if role.Name == domain.RoleAdmin {
+			err = rr.Upsert(c, &role)
+		} else {
+			err = rr.InsertIfMissing(c, &role)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
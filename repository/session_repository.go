@@ -0,0 +1,138 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type sessionRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewSessionRepository(db mongo.Database, collection string) domain.SessionRepository {
+	return &sessionRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (sr *sessionRepository) Create(c context.Context, session *domain.Session) error {
+	collection := sr.database.Collection(sr.collection)
+	now := primitive.NewDateTimeFromTime(time.Now())
+	session.CreatedAt = now
+	session.LastUsedAt = now
+	_, err := collection.InsertOne(c, session)
+	return err
+}
+
+func (sr *sessionRepository) GetByID(c context.Context, id string) (domain.Session, error) {
+	collection := sr.database.Collection(sr.collection)
+	var session domain.Session
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return session, err
+	}
+
+	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&session)
+	return session, err
+}
+
+func (sr *sessionRepository) GetActiveByUserID(c context.Context, userID string) ([]domain.Session, error) {
+	collection := sr.database.Collection(sr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"userId":    userIDHex,
+		"revokedAt": bson.M{"$exists": false},
+		"expiresAt": bson.M{"$gt": primitive.NewDateTimeFromTime(time.Now())},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "lastUsedAt", Value: -1}})
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []domain.Session
+	err = cursor.All(c, &sessions)
+	if sessions == nil {
+		return []domain.Session{}, err
+	}
+
+	return sessions, err
+}
+
+func (sr *sessionRepository) Touch(c context.Context, id string, expiresAt primitive.DateTime) error {
+	collection := sr.database.Collection(sr.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"lastUsedAt": primitive.NewDateTimeFromTime(time.Now()),
+			"expiresAt":  expiresAt,
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
+
+func (sr *sessionRepository) Revoke(c context.Context, id string) error {
+	collection := sr.database.Collection(sr.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"_id":       idHex,
+		"revokedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateOne(c, filter, update)
+	return err
+}
+
+func (sr *sessionRepository) RevokeByUserID(c context.Context, userID string) error {
+	collection := sr.database.Collection(sr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	filter := bson.M{
+		"userId":    userIDHex,
+		"revokedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateMany(c, filter, update)
+	return err
+}
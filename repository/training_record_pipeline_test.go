@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo/mocks"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func stage(t *testing.T, pipeline bson.A, i int, name string) interface{} {
+	t.Helper()
+	require.Greater(t, len(pipeline), i)
+	s, ok := pipeline[i].(bson.M)
+	require.True(t, ok)
+	value, ok := s[name]
+	require.True(t, ok, "stage %d is not %s", i, name)
+	return value
+}
+
+func TestPlanTotalsPipeline(t *testing.T) {
+	userID := primitive.NewObjectID()
+	start := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 7)
+
+	pipeline := planTotalsPipeline(userID, "plan-1", start, end)
+	require.Len(t, pipeline, 3)
+
+	assert.Equal(t, bson.M{
+		"userId":    userID,
+		"planId":    "plan-1",
+		"startTime": bson.M{"$gte": start, "$lt": end},
+	}, stage(t, pipeline, 0, "$match"))
+	assert.Equal(t, bson.M{
+		"_id":           nil,
+		"trainingCount": bson.M{"$sum": 1},
+		"totalDuration": bson.M{"$sum": "$duration"},
+		"totalWeight":   bson.M{"$sum": "$totalWeight"},
+		"totalCalories": bson.M{"$sum": "$caloriesBurned"},
+	}, stage(t, pipeline, 1, "$group"))
+
+	// 不限时间和计划时只按用户筛选
+	pipeline = planTotalsPipeline(userID, "", time.Time{}, time.Time{})
+	assert.Equal(t, bson.M{"userId": userID}, stage(t, pipeline, 0, "$match"))
+}
+
+func TestDayTotalsPipeline(t *testing.T) {
+	userID := primitive.NewObjectID()
+	location, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+	start := time.Date(2025, 11, 1, 0, 0, 0, 0, location)
+	end := time.Date(2025, 12, 1, 0, 0, 0, 0, location)
+
+	pipeline := dayTotalsPipeline(userID, start, end, location)
+	require.Len(t, pipeline, 4)
+
+	assert.Equal(t, bson.M{
+		"userId":    userID,
+		"startTime": bson.M{"$gte": start, "$lt": end},
+	}, stage(t, pipeline, 0, "$match"))
+
+	// 按用户时区的日期分组
+	group, ok := stage(t, pipeline, 1, "$group").(bson.M)
+	require.True(t, ok)
+	assert.Equal(t, bson.M{"$dateToString": bson.M{
+		"format":   "%Y-%m-%d",
+		"date":     "$startTime",
+		"timezone": "Asia/Shanghai",
+	}}, group["_id"])
+	assert.Equal(t, bson.M{"$sum": 1}, group["trainingCount"])
+	assert.Equal(t, bson.M{"$sum": "$duration"}, group["totalDuration"])
+
+	assert.Equal(t, bson.M{"_id": 1}, stage(t, pipeline, 2, "$sort"))
+	assert.Equal(t, bson.M{"_id": 0, "date": "$_id", "trainingCount": 1, "totalDuration": 1}, stage(t, pipeline, 3, "$project"))
+
+	// 不限时间时也排除没有开始时间的记录
+	pipeline = dayTotalsPipeline(userID, time.Time{}, time.Time{}, time.UTC)
+	assert.Equal(t, bson.M{
+		"userId":    userID,
+		"startTime": bson.M{"$type": "date"},
+	}, stage(t, pipeline, 0, "$match"))
+}
+
+func TestSummarizePipeline(t *testing.T) {
+	userID := primitive.NewObjectID()
+
+	pipeline := summarizePipeline(userID, domain.RollupPeriodDay, "2025-11-01", "2025-11-30")
+	require.Len(t, pipeline, 2)
+
+	assert.Equal(t, bson.M{
+		"userId":      userID,
+		"period":      domain.RollupPeriodDay,
+		"periodStart": bson.M{"$gte": "2025-11-01", "$lte": "2025-11-30"},
+	}, stage(t, pipeline, 0, "$match"))
+
+	facet, ok := stage(t, pipeline, 1, "$facet").(bson.M)
+	require.True(t, ok)
+	assert.ElementsMatch(t, []string{"totals", "muscleGroups", "exercises"}, keys(facet))
+
+	muscleGroups, ok := facet["muscleGroups"].(bson.A)
+	require.True(t, ok)
+	assert.Equal(t, "$muscleGroups", stage(t, muscleGroups, 0, "$unwind"))
+
+	exercises, ok := facet["exercises"].(bson.A)
+	require.True(t, ok)
+	// 先按日期排序，分组时 $first 取最早出现的动作名称
+	assert.Equal(t, bson.D{{Key: "periodStart", Value: 1}}, stage(t, exercises, 0, "$sort"))
+	assert.Equal(t, "$exercises", stage(t, exercises, 1, "$unwind"))
+}
+
+func TestSumByDay(t *testing.T) {
+	userID := primitive.NewObjectID()
+	start := time.Date(2025, 11, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 1, 0)
+
+	database := &mocks.Database{}
+	collection := &mocks.Collection{}
+	cursor := &mocks.Cursor{}
+	database.On("Collection", domain.CollectionTrainingRecord).Return(collection)
+	collection.On("Aggregate", mock.Anything, dayTotalsPipeline(userID, start, end, time.UTC)).Return(cursor, nil).Once()
+	cursor.On("All", mock.Anything, mock.AnythingOfType("*[]domain.DayTotals")).Run(func(args mock.Arguments) {
+		days := args.Get(1).(*[]domain.DayTotals)
+		*days = append(*days, domain.DayTotals{Date: "2025-11-03", TrainingCount: 2, TotalDuration: 90})
+	}).Return(nil).Once()
+
+	tr := NewTrainingRecordRepository(database, domain.CollectionTrainingRecord)
+	days, err := tr.SumByDay(context.Background(), userID.Hex(), start, end, time.UTC)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.DayTotals{{Date: "2025-11-03", TrainingCount: 2, TotalDuration: 90}}, days)
+
+	collection.AssertExpectations(t)
+	cursor.AssertExpectations(t)
+
+	_, err = tr.SumByDay(context.Background(), "invalid", start, end, time.UTC)
+	assert.Error(t, err)
+}
+
+func keys(m bson.M) []string {
+	result := []string{}
+	for key := range m {
+		result = append(result, key)
+	}
+	return result
+}
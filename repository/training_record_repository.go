@@ -9,6 +9,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
 )
 
 type trainingRecordRepository struct {
@@ -37,14 +39,17 @@ func (tr *trainingRecordRepository) GetByID(c context.Context, id string) (domai
 
 	idHex, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return record, err
+		return record, domain.ErrTrainingRecordNotFound
 	}
 
 	err = collection.FindOne(c, bson.M{"_id": idHex}).Decode(&record)
+	if err == mongodriver.ErrNoDocuments {
+		return record, domain.ErrTrainingRecordNotFound
+	}
 	return record, err
 }
 
-func (tr *trainingRecordRepository) GetByUserID(c context.Context, userID string, page, pageSize int, startDate, endDate string, planID string) ([]domain.TrainingRecord, int64, error) {
+func (tr *trainingRecordRepository) GetByUserID(c context.Context, userID string, page, pageSize int, start, end time.Time, planID string) ([]domain.TrainingRecord, int64, error) {
 	collection := tr.database.Collection(tr.collection)
 
 	userIDHex, err := primitive.ObjectIDFromHex(userID)
@@ -52,27 +57,7 @@ func (tr *trainingRecordRepository) GetByUserID(c context.Context, userID string
 		return nil, 0, err
 	}
 
-	// 构建查询条件
-	filter := bson.M{"userId": userIDHex}
-
-	if startDate != "" && endDate != "" {
-		// 如果日期格式是 YYYY-MM-DD，补充时间部分以确保查询正确
-		if len(startDate) == 10 {
-			startDate = startDate + " 00:00:00"
-		}
-		if len(endDate) == 10 {
-			endDate = endDate + " 23:59:59"
-		}
-
-		filter["startTime"] = bson.M{
-			"$gte": startDate,
-			"$lte": endDate,
-		}
-	}
-
-	if planID != "" {
-		filter["planId"] = planID
-	}
+	filter := recordFilter(userIDHex, start, end, planID)
 
 	// 计算总数
 	total, err := collection.CountDocuments(c, filter)
@@ -83,7 +68,7 @@ func (tr *trainingRecordRepository) GetByUserID(c context.Context, userID string
 	// 分页查询
 	skip := (page - 1) * pageSize
 	opts := options.Find().
-		SetSort(bson.D{{Key: "startTime", Value: -1}, {Key: "createdAt", Value: -1}}).
+		SetSort(bson.D{{Key: "startTime", Value: -1}, {Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}}).
 		SetSkip(int64(skip)).
 		SetLimit(int64(pageSize))
 
@@ -113,18 +98,22 @@ func (tr *trainingRecordRepository) Update(c context.Context, id string, record
 
 	update := bson.M{
 		"$set": bson.M{
-			"title":          record.Title,
-			"startTime":      record.StartTime,
-			"endTime":        record.EndTime,
-			"duration":       record.Duration,
-			"exercises":      record.Exercises,
-			"totalWeight":    record.TotalWeight,
-			"totalSets":      record.TotalSets,
-			"caloriesBurned": record.CaloriesBurned,
-			"notes":          record.Notes,
-			"mood":           record.Mood,
-			"planId":         record.PlanID,
-			"updatedAt":      record.UpdatedAt,
+			"title":              record.Title,
+			"startTime":          record.StartTime,
+			"endTime":            record.EndTime,
+			"duration":           record.Duration,
+			"exercises":          record.Exercises,
+			"totalWeight":        record.TotalWeight,
+			"totalSets":          record.TotalSets,
+			"muscleGroupVolumes": record.MuscleGroupVolumes,
+			"caloriesBurned":     record.CaloriesBurned,
+			"caloriesEstimated":  record.CaloriesEstimated,
+			"notes":              record.Notes,
+			"mood":               record.Mood,
+			"planId":             record.PlanID,
+			"planDayId":          record.PlanDayID,
+			"completionStatus":   record.CompletionStatus,
+			"updatedAt":          record.UpdatedAt,
 		},
 	}
 
@@ -143,3 +132,127 @@ func (tr *trainingRecordRepository) Delete(c context.Context, id string) error {
 	_, err = collection.DeleteOne(c, bson.M{"_id": idHex})
 	return err
 }
+
+func (tr *trainingRecordRepository) SumByPlan(c context.Context, userID, planID string, start, end time.Time) (domain.PlanTotals, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.PlanTotals{}, err
+	}
+
+	cursor, err := collection.Aggregate(c, planTotalsPipeline(userIDHex, planID, start, end))
+	if err != nil {
+		return domain.PlanTotals{}, err
+	}
+
+	var totals []domain.PlanTotals
+	if err := cursor.All(c, &totals); err != nil {
+		return domain.PlanTotals{}, err
+	}
+	if len(totals) == 0 {
+		return domain.PlanTotals{}, nil
+	}
+
+	return totals[0], nil
+}
+
+func (tr *trainingRecordRepository) SumByDay(c context.Context, userID string, start, end time.Time, location *time.Location) ([]domain.DayTotals, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := collection.Aggregate(c, dayTotalsPipeline(userIDHex, start, end, location))
+	if err != nil {
+		return nil, err
+	}
+
+	days := []domain.DayTotals{}
+	if err := cursor.All(c, &days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// planTotalsPipeline 汇总计划在开始时间 [start, end) 内的训练记录。
+// $sum 忽略缺失字段，未填写时长、重量、卡路里的记录只计入次数
+func planTotalsPipeline(userID primitive.ObjectID, planID string, start, end time.Time) bson.A {
+	return bson.A{
+		bson.M{"$match": recordFilter(userID, start, end, planID)},
+		bson.M{"$group": bson.M{
+			"_id":           nil,
+			"trainingCount": bson.M{"$sum": 1},
+			"totalDuration": bson.M{"$sum": "$duration"},
+			"totalWeight":   bson.M{"$sum": "$totalWeight"},
+			"totalCalories": bson.M{"$sum": "$caloriesBurned"},
+		}},
+		bson.M{"$project": bson.M{"_id": 0}},
+	}
+}
+
+// dayTotalsPipeline 按开始时间在 location 时区的日期分组统计训练次数和时长。
+// 没有开始时间的记录不属于任何一天，与训练汇总一致
+func dayTotalsPipeline(userID primitive.ObjectID, start, end time.Time, location *time.Location) bson.A {
+	filter := recordFilter(userID, start, end, "")
+	if _, exists := filter["startTime"]; !exists {
+		filter["startTime"] = bson.M{"$type": "date"}
+	}
+
+	return bson.A{
+		bson.M{"$match": filter},
+		bson.M{"$group": bson.M{
+			"_id": bson.M{"$dateToString": bson.M{
+				"format":   "%Y-%m-%d",
+				"date":     "$startTime",
+				"timezone": location.String(),
+			}},
+			"trainingCount": bson.M{"$sum": 1},
+			"totalDuration": bson.M{"$sum": "$duration"},
+		}},
+		bson.M{"$sort": bson.M{"_id": 1}},
+		bson.M{"$project": bson.M{"_id": 0, "date": "$_id", "trainingCount": 1, "totalDuration": 1}},
+	}
+}
+
+func (tr *trainingRecordRepository) CountByPlanDay(c context.Context, planID string, dayNumber int) (int64, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	return collection.CountDocuments(c, bson.M{"planId": planID, "planDayId": dayNumber})
+}
+
+func (tr *trainingRecordRepository) ClearPlanDay(c context.Context, planID string, dayNumber int) error {
+	collection := tr.database.Collection(tr.collection)
+
+	update := bson.M{
+		"$unset": bson.M{"planDayId": ""},
+		"$set":   bson.M{"updatedAt": primitive.NewDateTimeFromTime(time.Now())},
+	}
+
+	_, err := collection.UpdateMany(c, bson.M{"planId": planID, "planDayId": dayNumber}, update)
+	return err
+}
+
+// recordFilter 构建按用户、开始时间 [start, end) 和计划筛选训练记录的条件，时间零值表示不限
+func recordFilter(userID primitive.ObjectID, start, end time.Time, planID string) bson.M {
+	filter := bson.M{"userId": userID}
+
+	startTime := bson.M{}
+	if !start.IsZero() {
+		startTime["$gte"] = start
+	}
+	if !end.IsZero() {
+		startTime["$lt"] = end
+	}
+	if len(startTime) > 0 {
+		filter["startTime"] = startTime
+	}
+
+	if planID != "" {
+		filter["planId"] = planID
+	}
+
+	return filter
+}
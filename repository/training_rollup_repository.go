@@ -0,0 +1,200 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type trainingRollupRepository struct {
+	database   mongo.Database
+	collection string
+}
+
+func NewTrainingRollupRepository(db mongo.Database, collection string) domain.TrainingRollupRepository {
+	return &trainingRollupRepository{
+		database:   db,
+		collection: collection,
+	}
+}
+
+func (tr *trainingRollupRepository) Upsert(c context.Context, rollup *domain.TrainingRollup) error {
+	collection := tr.database.Collection(tr.collection)
+
+	rollup.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	filter := bson.M{
+		"userId":      rollup.UserID,
+		"period":      rollup.Period,
+		"periodStart": rollup.PeriodStart,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"trainingCount": rollup.TrainingCount,
+			"duration":      rollup.Duration,
+			"weight":        rollup.Weight,
+			"sets":          rollup.Sets,
+			"calories":      rollup.Calories,
+			"muscleGroups":  rollup.MuscleGroups,
+			"exercises":     rollup.Exercises,
+			"updatedAt":     rollup.UpdatedAt,
+		},
+	}
+	opts := options.Update().SetUpsert(true)
+
+	_, err := collection.UpdateOne(c, filter, update, opts)
+	return err
+}
+
+func (tr *trainingRollupRepository) Delete(c context.Context, userID, period, periodStart string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteMany(c, bson.M{"userId": userIDHex, "period": period, "periodStart": periodStart})
+	return err
+}
+
+func (tr *trainingRollupRepository) DeleteByUserID(c context.Context, userID string) error {
+	collection := tr.database.Collection(tr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteMany(c, bson.M{"userId": userIDHex})
+	return err
+}
+
+func (tr *trainingRollupRepository) GetRange(c context.Context, userID, period, start, end string) ([]domain.TrainingRollup, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{
+		"userId":      userIDHex,
+		"period":      period,
+		"periodStart": bson.M{"$gte": start, "$lte": end},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "periodStart", Value: 1}})
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var rollups []domain.TrainingRollup
+	err = cursor.All(c, &rollups)
+	if rollups == nil {
+		return []domain.TrainingRollup{}, err
+	}
+
+	return rollups, err
+}
+
+func (tr *trainingRollupRepository) Summarize(c context.Context, userID, period, start, end string) (domain.TrainingRollup, error) {
+	collection := tr.database.Collection(tr.collection)
+
+	userIDHex, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return domain.TrainingRollup{}, err
+	}
+
+	cursor, err := collection.Aggregate(c, summarizePipeline(userIDHex, period, start, end))
+	if err != nil {
+		return domain.TrainingRollup{}, err
+	}
+
+	var results []struct {
+		Totals       []domain.TrainingRollup    `bson:"totals"`
+		MuscleGroups []domain.MuscleGroupRollup `bson:"muscleGroups"`
+		Exercises    []domain.ExerciseRollup    `bson:"exercises"`
+	}
+	if err := cursor.All(c, &results); err != nil {
+		return domain.TrainingRollup{}, err
+	}
+
+	summary := domain.TrainingRollup{
+		UserID:       userIDHex,
+		Period:       period,
+		PeriodStart:  start,
+		MuscleGroups: []domain.MuscleGroupRollup{},
+		Exercises:    []domain.ExerciseRollup{},
+	}
+	if len(results) == 0 {
+		return summary, nil
+	}
+	if len(results[0].Totals) > 0 {
+		totals := results[0].Totals[0]
+		summary.TrainingCount = totals.TrainingCount
+		summary.Duration = totals.Duration
+		summary.Weight = totals.Weight
+		summary.Sets = totals.Sets
+		summary.Calories = totals.Calories
+	}
+	if results[0].MuscleGroups != nil {
+		summary.MuscleGroups = results[0].MuscleGroups
+	}
+	if results[0].Exercises != nil {
+		summary.Exercises = results[0].Exercises
+	}
+
+	return summary, nil
+}
+
+// summarizePipeline 合并 periodStart 在 [start, end] 内的汇总：总计求和，肌群和动作展开后分组计数
+func summarizePipeline(userID primitive.ObjectID, period, start, end string) bson.A {
+	return bson.A{
+		bson.M{"$match": bson.M{
+			"userId":      userID,
+			"period":      period,
+			"periodStart": bson.M{"$gte": start, "$lte": end},
+		}},
+		bson.M{"$facet": bson.M{
+			"totals": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":           nil,
+					"trainingCount": bson.M{"$sum": "$trainingCount"},
+					"duration":      bson.M{"$sum": "$duration"},
+					"weight":        bson.M{"$sum": "$weight"},
+					"sets":          bson.M{"$sum": "$sets"},
+					"calories":      bson.M{"$sum": "$calories"},
+				}},
+				bson.M{"$project": bson.M{"_id": 0}},
+			},
+			"muscleGroups": bson.A{
+				bson.M{"$unwind": "$muscleGroups"},
+				bson.M{"$group": bson.M{
+					"_id":           "$muscleGroups.muscleGroup",
+					"trainingCount": bson.M{"$sum": "$muscleGroups.trainingCount"},
+					"totalWeight":   bson.M{"$sum": "$muscleGroups.totalWeight"},
+				}},
+				bson.M{"$sort": bson.D{{Key: "trainingCount", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$project": bson.M{"_id": 0, "muscleGroup": "$_id", "trainingCount": 1, "totalWeight": 1}},
+			},
+			// 同一动作取最早出现的名称
+			"exercises": bson.A{
+				bson.M{"$sort": bson.D{{Key: "periodStart", Value: 1}}},
+				bson.M{"$unwind": "$exercises"},
+				bson.M{"$group": bson.M{
+					"_id":   "$exercises.key",
+					"name":  bson.M{"$first": "$exercises.name"},
+					"count": bson.M{"$sum": "$exercises.count"},
+				}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+				bson.M{"$project": bson.M{"_id": 0, "key": "$_id", "name": 1, "count": 1}},
+			},
+		}},
+	}
+}
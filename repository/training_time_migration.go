@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// legacyTimeFields 无法解析的旧版训练时间保留到的字段
+var legacyTimeFields = map[string]string{
+	"startTime": "legacyStartTime",
+	"endTime":   "legacyEndTime",
+}
+
+// MigrateTrainingTimes 将旧版 "YYYY-MM-DD HH:mm:ss" 字符串格式的 startTime/endTime 转换为日期类型，
+// 字符串按记录所属用户的时区解释，无法解析的值原样移到 legacyStartTime/legacyEndTime 保留并记录日志。
+// 可重复执行，返回涉及的用户ID，调用方需要重建这些用户的训练汇总。
+func MigrateTrainingTimes(c context.Context, db mongo.Database) ([]string, error) {
+	collection := db.Collection(domain.CollectionTrainingRecord)
+	ur := NewUserRepository(db, domain.CollectionUser)
+
+	filter := bson.M{"$or": bson.A{
+		bson.M{"startTime": bson.M{"$type": "string"}},
+		bson.M{"endTime": bson.M{"$type": "string"}},
+	}}
+	opts := options.Find().SetProjection(bson.M{"userId": 1, "startTime": 1, "endTime": 1})
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var documents []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		UserID    primitive.ObjectID `bson:"userId"`
+		StartTime interface{}        `bson:"startTime"`
+		EndTime   interface{}        `bson:"endTime"`
+	}
+	if err := cursor.All(c, &documents); err != nil {
+		return nil, err
+	}
+
+	locations := make(map[primitive.ObjectID]*time.Location)
+	userIDs := []string{}
+	for _, document := range documents {
+		location, exists := locations[document.UserID]
+		if !exists {
+			// 用户已删除时按默认时区处理
+			user, err := ur.GetByID(c, document.UserID.Hex())
+			location = timeutil.Location("")
+			if err == nil {
+				location = timeutil.Location(user.TimeZone)
+			}
+			locations[document.UserID] = location
+			userIDs = append(userIDs, document.UserID.Hex())
+		}
+
+		set := bson.M{}
+		rename := bson.M{}
+		for field, value := range map[string]interface{}{"startTime": document.StartTime, "endTime": document.EndTime} {
+			text, ok := value.(string)
+			if !ok {
+				continue
+			}
+			if t, err := timeutil.ParseDateTime(text, location); err == nil {
+				set[field] = t
+			} else {
+				// 无法解析的值不能丢弃，移到 legacy 字段留待人工处理
+				legacyField := legacyTimeFields[field]
+				rename[field] = legacyField
+				log.Printf("Training record %s has unparseable %s %q, moved to %s", document.ID.Hex(), field, text, legacyField)
+			}
+		}
+
+		update := bson.M{}
+		if len(set) > 0 {
+			update["$set"] = set
+		}
+		if len(rename) > 0 {
+			update["$rename"] = rename
+		}
+		if _, err := collection.UpdateOne(c, bson.M{"_id": document.ID}, update); err != nil {
+			return userIDs, err
+		}
+	}
+
+	return userIDs, nil
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync/atomic"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"go.mongodb.org/mongo-driver/bson"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+type mongoTransactor struct {
+	client mongo.Client
+	// allowStandalone 服务端不支持事务时是否退化为不使用事务，仅用于单节点 mongod 的开发环境
+	allowStandalone bool
+	// standalone 已确认服务端不支持事务（单节点 mongod），之后直接执行
+	standalone atomic.Bool
+}
+
+// NewTransactor 创建基于 MongoDB 会话的事务执行器。服务端不支持事务时默认返回
+// ErrTransactionsUnsupported；allowStandalone 为 true 时记录一次警告后不使用事务执行
+func NewTransactor(db mongo.Database, allowStandalone bool) domain.Transactor {
+	return &mongoTransactor{
+		client:          db.Client(),
+		allowStandalone: allowStandalone,
+	}
+}
+
+func (mt *mongoTransactor) WithTransaction(c context.Context, fn func(ctx context.Context) error) error {
+	if mt.standalone.Load() {
+		return fn(c)
+	}
+
+	err := runTransaction(c, mt.client, fn)
+	if !transactionsUnsupported(err) {
+		return err
+	}
+	if !mt.allowStandalone {
+		return fmt.Errorf("%w: %v", domain.ErrTransactionsUnsupported, err)
+	}
+
+	// 事务中的第一个命令就会被拒绝，此时还没有写入任何数据，可以直接重新执行
+	if mt.standalone.CompareAndSwap(false, true) {
+		log.Println("MongoDB does not support transactions, running transactional writes without a transaction (MONGO_ALLOW_STANDALONE=true)")
+	}
+	return fn(c)
+}
+
+// CheckTransactions 启动时在事务中执行一次只读查询，服务端为单节点 mongod 等不支持事务时
+// 返回 ErrTransactionsUnsupported，避免到写入请求时才逐个失败
+func CheckTransactions(c context.Context, db mongo.Database) error {
+	err := runTransaction(c, db.Client(), func(ctx context.Context) error {
+		_, err := db.Collection(domain.CollectionUser).CountDocuments(ctx, bson.M{"_id": nil})
+		return err
+	})
+	if transactionsUnsupported(err) {
+		return fmt.Errorf("%w: %v", domain.ErrTransactionsUnsupported, err)
+	}
+	return err
+}
+
+func runTransaction(c context.Context, client mongo.Client, fn func(ctx context.Context) error) error {
+	return client.UseSession(c, func(sc mongodriver.SessionContext) error {
+		_, err := sc.WithTransaction(sc, func(txc mongodriver.SessionContext) (interface{}, error) {
+			return nil, fn(txc)
+		})
+		return err
+	})
+}
+
+// transactionsUnsupported 服务端不是副本集或分片集群时拒绝带事务号的命令
+func transactionsUnsupported(err error) bool {
+	var serverError mongodriver.ServerError
+	return errors.As(err, &serverError) &&
+		serverError.HasErrorCodeWithMessage(20, "Transaction numbers are only allowed")
+}
@@ -0,0 +1,59 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/mongo/mocks"
+	"github.com/zhengshui/flow-link-server/repository"
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
+)
+
+// errStandalone 单节点 mongod 拒绝事务时返回的错误
+var errStandalone = mongodriver.CommandError{
+	Code:    20,
+	Message: "Transaction numbers are only allowed on a replica set member or mongos",
+	Name:    "IllegalOperation",
+}
+
+func mockDatabase(t *testing.T, sessionErr error) *mocks.Database {
+	client := mocks.NewClient(t)
+	client.On("UseSession", mock.Anything, mock.Anything).Return(sessionErr)
+
+	db := mocks.NewDatabase(t)
+	db.On("Client").Return(client)
+	return db
+}
+
+func TestCheckTransactions(t *testing.T) {
+	assert.NoError(t, repository.CheckTransactions(context.Background(), mockDatabase(t, nil)))
+
+	err := repository.CheckTransactions(context.Background(), mockDatabase(t, errStandalone))
+	assert.ErrorIs(t, err, domain.ErrTransactionsUnsupported)
+
+	err = repository.CheckTransactions(context.Background(), mockDatabase(t, assert.AnError))
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.NotErrorIs(t, err, domain.ErrTransactionsUnsupported)
+}
+
+func TestTransactorStandalone(t *testing.T) {
+	ran := 0
+	fn := func(ctx context.Context) error {
+		ran++
+		return nil
+	}
+
+	// 默认不支持事务时直接失败，不执行写入
+	err := repository.NewTransactor(mockDatabase(t, errStandalone), false).WithTransaction(context.Background(), fn)
+	assert.ErrorIs(t, err, domain.ErrTransactionsUnsupported)
+	assert.Zero(t, ran)
+
+	// 显式允许时不使用事务执行，之后不再尝试开启事务
+	transactor := repository.NewTransactor(mockDatabase(t, errStandalone), true)
+	assert.NoError(t, transactor.WithTransaction(context.Background(), fn))
+	assert.NoError(t, transactor.WithTransaction(context.Background(), fn))
+	assert.Equal(t, 2, ran)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"regexp"
 	"time"
 
 	"github.com/zhengshui/flow-link-server/domain"
@@ -9,6 +10,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	mongodriver "go.mongodb.org/mongo-driver/mongo"
 )
 
 type userRepository struct {
@@ -27,6 +30,9 @@ func (ur *userRepository) Create(c context.Context, user *domain.User) error {
 	collection := ur.database.Collection(ur.collection)
 
 	_, err := collection.InsertOne(c, user)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return domain.ErrUserConflict
+	}
 
 	return err
 }
@@ -58,6 +64,40 @@ func (ur *userRepository) GetByEmail(c context.Context, email string) (domain.Us
 	return user, err
 }
 
+func (ur *userRepository) GetByPhone(c context.Context, phone string) (domain.User, error) {
+	collection := ur.database.Collection(ur.collection)
+	var user domain.User
+	err := collection.FindOne(c, bson.M{"phone": phone}).Decode(&user)
+	return user, err
+}
+
+func (ur *userRepository) FindByIdentifier(c context.Context, identifier string, limit int) ([]domain.User, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	// 三个字段各自有索引，$or 的每个分支都走索引
+	filter := bson.M{
+		"$or": []bson.M{
+			{"username": identifier},
+			{"email": domain.NormalizeEmail(identifier)},
+			{"phone": identifier},
+		},
+	}
+	opts := options.Find().SetLimit(int64(limit))
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []domain.User
+	err = cursor.All(c, &users)
+	if users == nil {
+		return []domain.User{}, err
+	}
+
+	return users, err
+}
+
 func (ur *userRepository) GetByID(c context.Context, id string) (domain.User, error) {
 	collection := ur.database.Collection(ur.collection)
 
@@ -89,20 +129,245 @@ func (ur *userRepository) Update(c context.Context, id string, user *domain.User
 
 	update := bson.M{
 		"$set": bson.M{
-			"nickname":     user.Nickname,
-			"avatarUrl":    user.AvatarUrl,
-			"email":        user.Email,
-			"phone":        user.Phone,
-			"gender":       user.Gender,
-			"age":          user.Age,
-			"height":       user.Height,
-			"weight":       user.Weight,
-			"targetWeight": user.TargetWeight,
-			"fitnessGoal":  user.FitnessGoal,
-			"updatedAt":    primitive.NewDateTimeFromTime(time.Now()),
+			"nickname":         user.Nickname,
+			"avatarUrl":        user.AvatarUrl,
+			"avatarKey":        user.AvatarKey,
+			"email":            user.Email,
+			"emailVerified":    user.EmailVerified,
+			"phone":            user.Phone,
+			"gender":           user.Gender,
+			"age":              user.Age,
+			"height":           user.Height,
+			"weight":           user.Weight,
+			"targetWeight":     user.TargetWeight,
+			"fitnessGoal":      user.FitnessGoal,
+			"timeZone":         user.TimeZone,
+			"trainingWeekdays": user.TrainingWeekdays,
+			"updatedAt":        primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	if mongodriver.IsDuplicateKeyError(err) {
+		return domain.ErrUserConflict
+	}
+	return err
+}
+
+func (ur *userRepository) UpdatePassword(c context.Context, id string, hashedPassword string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"password":  hashedPassword,
+			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
+
+func (ur *userRepository) UpdateWeight(c context.Context, id string, weight float64) error {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"weight":    weight,
+			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
+
+func (ur *userRepository) UpdateRoles(c context.Context, id string, roles []string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"roles":     roles,
+			"role":      domain.LegacyRole(roles),
+			"updatedAt": primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
+
+func (ur *userRepository) Search(c context.Context, keyword string, page, pageSize int) ([]domain.User, int64, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	filter := bson.M{}
+	if keyword != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(keyword), Options: "i"}
+		filter["$or"] = []bson.M{
+			{"username": pattern},
+			{"nickname": pattern},
+			{"email": pattern},
+			{"phone": pattern},
+		}
+	}
+
+	// 计算总数
+	total, err := collection.CountDocuments(c, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 分页查询
+	skip := (page - 1) * pageSize
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "password", Value: 0}}).
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetSkip(int64(skip)).
+		SetLimit(int64(pageSize))
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var users []domain.User
+	err = cursor.All(c, &users)
+	if users == nil {
+		return []domain.User{}, total, err
+	}
+
+	return users, total, err
+}
+
+func (ur *userRepository) SetDisabled(c context.Context, id string, disabled bool) error {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{
+		"$set": bson.M{
+			"disabled":   true,
+			"disabledAt": now,
+			"updatedAt":  now,
 		},
 	}
+	if !disabled {
+		update = bson.M{
+			"$set":   bson.M{"updatedAt": now},
+			"$unset": bson.M{"disabled": "", "disabledAt": ""},
+		}
+	}
+
+	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
+	return err
+}
+
+func (ur *userRepository) SetEmailVerified(c context.Context, id string, email string) (bool, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, err
+	}
+
+	// 以邮箱为条件，防止验证码发出后用户改了邮箱，新邮箱被误标记为已验证
+	filter := bson.M{
+		"_id":   idHex,
+		"email": email,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"emailVerified": true,
+			"updatedAt":     primitive.NewDateTimeFromTime(time.Now()),
+		},
+	}
+
+	result, err := collection.UpdateOne(c, filter, update)
+	if err != nil {
+		return false, err
+	}
+
+	return result.MatchedCount == 1, nil
+}
+
+func (ur *userRepository) ScheduleDeletion(c context.Context, id string, at *time.Time) error {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	update := bson.M{
+		"$set":   bson.M{"updatedAt": now},
+		"$unset": bson.M{"deletionScheduledAt": ""},
+	}
+	if at != nil {
+		update = bson.M{
+			"$set": bson.M{
+				"deletionScheduledAt": primitive.NewDateTimeFromTime(*at),
+				"updatedAt":           now,
+			},
+		}
+	}
 
 	_, err = collection.UpdateOne(c, bson.M{"_id": idHex}, update)
 	return err
 }
+
+func (ur *userRepository) GetDueForDeletion(c context.Context, before time.Time, limit int) ([]domain.User, error) {
+	collection := ur.database.Collection(ur.collection)
+
+	filter := bson.M{
+		"deletionScheduledAt": bson.M{"$lte": primitive.NewDateTimeFromTime(before)},
+	}
+	opts := options.Find().
+		SetProjection(bson.D{{Key: "password", Value: 0}}).
+		SetSort(bson.D{{Key: "deletionScheduledAt", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := collection.Find(c, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []domain.User
+	err = cursor.All(c, &users)
+	if users == nil {
+		return []domain.User{}, err
+	}
+
+	return users, err
+}
+
+func (ur *userRepository) Delete(c context.Context, id string) error {
+	collection := ur.database.Collection(ur.collection)
+
+	idHex, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+
+	_, err = collection.DeleteOne(c, bson.M{"_id": idHex})
+	return err
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// accountPurgeBatchSize 每轮最多删除的账号数
+const accountPurgeBatchSize = 100
+
+type accountUsecase struct {
+	userRepository         domain.UserRepository
+	accountRepository      domain.AccountRepository
+	loginAttemptRepository domain.LoginAttemptRepository
+	blobStore              domain.BlobStore
+	contextTimeout         time.Duration
+}
+
+func NewAccountUsecase(
+	userRepository domain.UserRepository,
+	accountRepository domain.AccountRepository,
+	loginAttemptRepository domain.LoginAttemptRepository,
+	blobStore domain.BlobStore,
+	timeout time.Duration,
+) domain.AccountUsecase {
+	return &accountUsecase{
+		userRepository:         userRepository,
+		accountRepository:      accountRepository,
+		loginAttemptRepository: loginAttemptRepository,
+		blobStore:              blobStore,
+		contextTimeout:         timeout,
+	}
+}
+
+func (au *accountUsecase) Export(c context.Context, userID string) (domain.AccountExport, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	user, err := au.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+
+	data, err := au.accountRepository.GetUserData(ctx, userID)
+	if err != nil {
+		return domain.AccountExport{}, err
+	}
+
+	return domain.AccountExport{
+		ExportedAt:  time.Now().Format(time.RFC3339),
+		User:        user,
+		AccountData: data,
+	}, nil
+}
+
+func (au *accountUsecase) RequestDeletion(c context.Context, userID, password string, graceDays int) (time.Time, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	user, err := au.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)) != nil {
+		return time.Time{}, domain.ErrPasswordIncorrect
+	}
+
+	// 重复申请不顺延已安排的删除时间
+	if user.DeletionScheduledAt != nil {
+		return user.DeletionScheduledAt.Time(), nil
+	}
+
+	scheduledAt := time.Now().AddDate(0, 0, graceDays)
+	err = au.userRepository.ScheduleDeletion(ctx, userID, &scheduledAt)
+	return scheduledAt, err
+}
+
+func (au *accountUsecase) CancelDeletion(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	user, err := au.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.DeletionScheduledAt == nil {
+		return domain.ErrAccountDeletionNotRequested
+	}
+
+	return au.userRepository.ScheduleDeletion(ctx, userID, nil)
+}
+
+func (au *accountUsecase) PurgeDueAccounts(c context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	users, err := au.userRepository.GetDueForDeletion(ctx, time.Now(), accountPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, user := range users {
+		userID := user.ID.Hex()
+
+		// 先删关联数据再删用户文档，中途失败时下一轮仍能找到该用户继续删除
+		err = au.accountRepository.DeleteUserData(ctx, userID)
+		if err != nil {
+			return purged, err
+		}
+
+		err = au.loginAttemptRepository.DeleteByKeys(ctx, userAttemptKeys(user))
+		if err != nil {
+			return purged, err
+		}
+
+		// 进度照片和头像等上传文件
+		err = au.blobStore.DeletePrefix(ctx, userBlobPrefix(userID))
+		if err != nil {
+			return purged, err
+		}
+
+		err = au.userRepository.Delete(ctx, userID)
+		if err != nil {
+			return purged, err
+		}
+		purged++
+	}
+
+	return purged, nil
+}
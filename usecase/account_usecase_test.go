@@ -0,0 +1,113 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type accountFixture struct {
+	user     domain.User
+	users    *mocks.UserRepository
+	accounts *mocks.AccountRepository
+	attempts *mocks.LoginAttemptRepository
+	blobs    *mocks.BlobStore
+	usecase  domain.AccountUsecase
+}
+
+func newAccountFixture(t *testing.T) *accountFixture {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	f := &accountFixture{
+		user:     domain.User{ID: primitive.NewObjectID(), Username: "alice", Email: "alice@example.com", Password: string(hashed)},
+		users:    mocks.NewUserRepository(t),
+		accounts: mocks.NewAccountRepository(t),
+		attempts: mocks.NewLoginAttemptRepository(t),
+		blobs:    mocks.NewBlobStore(t),
+	}
+	f.usecase = usecase.NewAccountUsecase(f.users, f.accounts, f.attempts, f.blobs, time.Second)
+	return f
+}
+
+func TestRequestDeletion(t *testing.T) {
+	t.Run("schedules after the grace period", func(t *testing.T) {
+		f := newAccountFixture(t)
+		userID := f.user.ID.Hex()
+		f.users.On("GetByID", mock.Anything, userID).Return(f.user, nil).Once()
+		f.users.On("ScheduleDeletion", mock.Anything, userID, mock.AnythingOfType("*time.Time")).Return(nil).Once()
+
+		scheduledAt, err := f.usecase.RequestDeletion(context.Background(), userID, "secret123", 30)
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().AddDate(0, 0, 30), scheduledAt, time.Minute)
+	})
+
+	t.Run("wrong password", func(t *testing.T) {
+		f := newAccountFixture(t)
+		f.users.On("GetByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil).Once()
+
+		_, err := f.usecase.RequestDeletion(context.Background(), f.user.ID.Hex(), "wrong", 30)
+		assert.ErrorIs(t, err, domain.ErrPasswordIncorrect)
+	})
+
+	t.Run("repeated request keeps the scheduled time", func(t *testing.T) {
+		f := newAccountFixture(t)
+		scheduled := primitive.NewDateTimeFromTime(time.Now().AddDate(0, 0, 3))
+		f.user.DeletionScheduledAt = &scheduled
+		f.users.On("GetByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil).Once()
+
+		scheduledAt, err := f.usecase.RequestDeletion(context.Background(), f.user.ID.Hex(), "secret123", 30)
+		require.NoError(t, err)
+		assert.Equal(t, scheduled.Time(), scheduledAt)
+	})
+}
+
+func TestCancelDeletion(t *testing.T) {
+	f := newAccountFixture(t)
+	userID := f.user.ID.Hex()
+	f.users.On("GetByID", mock.Anything, userID).Return(f.user, nil).Once()
+	assert.ErrorIs(t, f.usecase.CancelDeletion(context.Background(), userID), domain.ErrAccountDeletionNotRequested)
+
+	scheduled := primitive.NewDateTimeFromTime(time.Now())
+	f.user.DeletionScheduledAt = &scheduled
+	f.users.On("GetByID", mock.Anything, userID).Return(f.user, nil).Once()
+	f.users.On("ScheduleDeletion", mock.Anything, userID, (*time.Time)(nil)).Return(nil).Once()
+	assert.NoError(t, f.usecase.CancelDeletion(context.Background(), userID))
+}
+
+func TestPurgeDueAccounts(t *testing.T) {
+	t.Run("deletes data, attempt keys, files and the user", func(t *testing.T) {
+		f := newAccountFixture(t)
+		f.user.Phone = "13800000000"
+		userID := f.user.ID.Hex()
+		f.users.On("GetDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return([]domain.User{f.user}, nil).Once()
+		f.accounts.On("DeleteUserData", mock.Anything, userID).Return(nil).Once()
+		f.attempts.On("DeleteByKeys", mock.Anything, []string{"username:alice", "username:alice@example.com", "username:13800000000"}).Return(nil).Once()
+		f.blobs.On("DeletePrefix", mock.Anything, "users/"+userID+"/").Return(nil).Once()
+		f.users.On("Delete", mock.Anything, userID).Return(nil).Once()
+
+		purged, err := f.usecase.PurgeDueAccounts(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, 1, purged)
+	})
+
+	t.Run("keeps the user document when data deletion fails", func(t *testing.T) {
+		// 用户文档保留，下一轮仍能找到该用户继续删除
+		f := newAccountFixture(t)
+		f.users.On("GetDueForDeletion", mock.Anything, mock.Anything, mock.Anything).Return([]domain.User{f.user}, nil).Once()
+		f.accounts.On("DeleteUserData", mock.Anything, f.user.ID.Hex()).Return(assert.AnError).Once()
+
+		purged, err := f.usecase.PurgeDueAccounts(context.Background())
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Zero(t, purged)
+	})
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type adminUserUsecase struct {
+	userRepository         domain.UserRepository
+	sessionRepository      domain.SessionRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	contextTimeout         time.Duration
+}
+
+func NewAdminUserUsecase(userRepository domain.UserRepository, sessionRepository domain.SessionRepository, refreshTokenRepository domain.RefreshTokenRepository, timeout time.Duration) domain.AdminUserUsecase {
+	return &adminUserUsecase{
+		userRepository:         userRepository,
+		sessionRepository:      sessionRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+func (au *adminUserUsecase) Search(c context.Context, keyword string, page, pageSize int) ([]domain.User, int64, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	users, total, err := au.userRepository.Search(ctx, keyword, page, pageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Initialize empty array to avoid null in JSON
+	if users == nil {
+		users = []domain.User{}
+	}
+
+	return users, total, nil
+}
+
+func (au *adminUserUsecase) GetByID(c context.Context, userID string) (domain.User, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+	return au.userRepository.GetByID(ctx, userID)
+}
+
+func (au *adminUserUsecase) SetDisabled(c context.Context, userID string, disabled bool) error {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	if _, err := au.userRepository.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	err := au.userRepository.SetDisabled(ctx, userID, disabled)
+	if err != nil {
+		return err
+	}
+
+	if !disabled {
+		return nil
+	}
+
+	return au.revokeAll(ctx, userID)
+}
+
+func (au *adminUserUsecase) ForceLogout(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	if _, err := au.userRepository.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	return au.revokeAll(ctx, userID)
+}
+
+func (au *adminUserUsecase) IsDisabled(c context.Context, userID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, au.contextTimeout)
+	defer cancel()
+
+	user, err := au.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.Disabled, nil
+}
+
+// revokeAll 吊销用户所有刷新令牌和登录会话，已签发的访问令牌随会话一并失效
+func (au *adminUserUsecase) revokeAll(ctx context.Context, userID string) error {
+	err := au.refreshTokenRepository.RevokeByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return au.sessionRepository.RevokeByUserID(ctx, userID)
+}
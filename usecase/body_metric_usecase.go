@@ -0,0 +1,317 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// defaultTrendDays 未指定开始日期时趋势覆盖的天数
+	defaultTrendDays = 90
+	// maxTrendWindow 移动平均窗口的上限(天)
+	maxTrendWindow = 90
+)
+
+type bodyMetricUsecase struct {
+	bodyMetricRepository domain.BodyMetricRepository
+	userRepository       domain.UserRepository
+	contextTimeout       time.Duration
+}
+
+func NewBodyMetricUsecase(bodyMetricRepository domain.BodyMetricRepository, userRepository domain.UserRepository, timeout time.Duration) domain.BodyMetricUsecase {
+	return &bodyMetricUsecase{
+		bodyMetricRepository: bodyMetricRepository,
+		userRepository:       userRepository,
+		contextTimeout:       timeout,
+	}
+}
+
+func (bu *bodyMetricUsecase) Create(c context.Context, userID string, request *domain.CreateBodyMetricRequest) (domain.BodyMetric, error) {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	user, err := bu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.BodyMetric{}, err
+	}
+
+	// 未指定日期时记在用户时区的今天
+	date := request.Date
+	if date == "" {
+		date = timeutil.Today(timeutil.Location(user.TimeZone))
+	}
+	if !validDate(date) {
+		return domain.BodyMetric{}, domain.ErrInvalidBodyMetric
+	}
+
+	metric := domain.BodyMetric{
+		ID:      primitive.NewObjectID(),
+		UserID:  user.ID,
+		Date:    date,
+		Weight:  request.Weight,
+		BodyFat: request.BodyFat,
+		Waist:   request.Waist,
+		Chest:   request.Chest,
+		Arm:     request.Arm,
+		Thigh:   request.Thigh,
+		Notes:   request.Notes,
+	}
+	if !hasBodyMetricValues(metric) {
+		return domain.BodyMetric{}, domain.ErrInvalidBodyMetric
+	}
+
+	err = bu.bodyMetricRepository.Create(ctx, &metric)
+	if err != nil {
+		return domain.BodyMetric{}, err
+	}
+
+	if metric.Weight != nil {
+		err = syncUserWeight(ctx, bu.bodyMetricRepository, bu.userRepository, user)
+	}
+	return metric, err
+}
+
+func (bu *bodyMetricUsecase) GetByID(c context.Context, userID, id string) (domain.BodyMetric, error) {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	return bu.getOwned(ctx, userID, id)
+}
+
+func (bu *bodyMetricUsecase) GetList(c context.Context, userID, startDate, endDate string) ([]domain.BodyMetric, error) {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	if (startDate != "" && !validDate(startDate)) || (endDate != "" && !validDate(endDate)) {
+		return nil, domain.ErrInvalidBodyMetric
+	}
+
+	return bu.bodyMetricRepository.GetByUserID(ctx, userID, startDate, endDate)
+}
+
+func (bu *bodyMetricUsecase) Update(c context.Context, userID, id string, request *domain.UpdateBodyMetricRequest) (domain.BodyMetric, error) {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	metric, err := bu.getOwned(ctx, userID, id)
+	if err != nil {
+		return metric, err
+	}
+
+	// 修改日期可能改变最近一次体重
+	weightChanged := request.Weight != nil || (request.Date != nil && metric.Weight != nil)
+
+	if request.Date != nil {
+		if !validDate(*request.Date) {
+			return metric, domain.ErrInvalidBodyMetric
+		}
+		metric.Date = *request.Date
+	}
+	if request.Weight != nil {
+		metric.Weight = request.Weight
+	}
+	if request.BodyFat != nil {
+		metric.BodyFat = request.BodyFat
+	}
+	if request.Waist != nil {
+		metric.Waist = request.Waist
+	}
+	if request.Chest != nil {
+		metric.Chest = request.Chest
+	}
+	if request.Arm != nil {
+		metric.Arm = request.Arm
+	}
+	if request.Thigh != nil {
+		metric.Thigh = request.Thigh
+	}
+	if request.Notes != nil {
+		metric.Notes = request.Notes
+	}
+
+	err = bu.bodyMetricRepository.Update(ctx, &metric)
+	if err != nil {
+		return metric, err
+	}
+
+	if weightChanged {
+		user, err := bu.userRepository.GetByID(ctx, userID)
+		if err != nil {
+			return metric, err
+		}
+		err = syncUserWeight(ctx, bu.bodyMetricRepository, bu.userRepository, user)
+		if err != nil {
+			return metric, err
+		}
+	}
+	return metric, nil
+}
+
+func (bu *bodyMetricUsecase) Delete(c context.Context, userID, id string) error {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	metric, err := bu.getOwned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	err = bu.bodyMetricRepository.Delete(ctx, id)
+	if err != nil || metric.Weight == nil {
+		return err
+	}
+
+	user, err := bu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return syncUserWeight(ctx, bu.bodyMetricRepository, bu.userRepository, user)
+}
+
+func (bu *bodyMetricUsecase) GetTrend(c context.Context, userID, metric, startDate, endDate string, window int) (domain.BodyMetricTrend, error) {
+	ctx, cancel := context.WithTimeout(c, bu.contextTimeout)
+	defer cancel()
+
+	if !domain.ValidBodyMetric(metric) {
+		return domain.BodyMetricTrend{}, domain.ErrInvalidBodyMetric
+	}
+	if window <= 0 {
+		window = domain.DefaultTrendWindow
+	}
+	if window > maxTrendWindow {
+		window = maxTrendWindow
+	}
+
+	user, err := bu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.BodyMetricTrend{}, err
+	}
+
+	if endDate == "" {
+		endDate = timeutil.Today(timeutil.Location(user.TimeZone))
+	}
+	if startDate == "" {
+		startDate = addDays(endDate, -(defaultTrendDays - 1))
+	}
+	if !validDate(startDate) || !validDate(endDate) {
+		return domain.BodyMetricTrend{}, domain.ErrInvalidBodyMetric
+	}
+
+	// 区间开头的移动平均需要用到之前 window-1 天的记录
+	metrics, err := bu.bodyMetricRepository.GetByUserID(ctx, userID, addDays(startDate, -(window-1)), endDate)
+	if err != nil {
+		return domain.BodyMetricTrend{}, err
+	}
+
+	return buildTrend(metric, metrics, startDate, window), nil
+}
+
+// getOwned 读取属于该用户的记录，不属于该用户时按不存在处理
+func (bu *bodyMetricUsecase) getOwned(ctx context.Context, userID, id string) (domain.BodyMetric, error) {
+	metric, err := bu.bodyMetricRepository.GetByID(ctx, id)
+	if err != nil {
+		return metric, err
+	}
+	if metric.UserID.Hex() != userID {
+		return domain.BodyMetric{}, domain.ErrBodyMetricNotFound
+	}
+	return metric, nil
+}
+
+// buildTrend metrics 按日期升序，startDate 之前的记录只参与移动平均
+func buildTrend(metric string, metrics []domain.BodyMetric, startDate string, window int) domain.BodyMetricTrend {
+	trend := domain.BodyMetricTrend{
+		Metric: metric,
+		Window: window,
+		Points: []domain.TrendPoint{},
+	}
+
+	type sample struct {
+		date  string
+		value float64
+	}
+	samples := []sample{}
+	for _, m := range metrics {
+		if value := m.Value(metric); value != nil {
+			samples = append(samples, sample{date: m.Date, value: *value})
+		}
+	}
+
+	for i, s := range samples {
+		if s.date < startDate {
+			continue
+		}
+		windowStart := addDays(s.date, -(window - 1))
+		sum := 0.0
+		count := 0
+		for j := i; j >= 0 && samples[j].date >= windowStart; j-- {
+			sum += samples[j].value
+			count++
+		}
+		trend.Points = append(trend.Points, domain.TrendPoint{
+			Date:          s.date,
+			Value:         s.value,
+			MovingAverage: roundMetric(sum / float64(count)),
+		})
+	}
+
+	if len(trend.Points) == 0 {
+		return trend
+	}
+	first := trend.Points[0]
+	last := trend.Points[len(trend.Points)-1]
+	latest := last.Value
+	trend.Latest = &latest
+	trend.Change = roundMetric(last.Value - first.Value)
+	trend.WeeklyRate = weeklyRate(first.Date, first.MovingAverage, last.Date, last.MovingAverage)
+	return trend
+}
+
+// syncUserWeight 个人资料中的体重保持为最近一次记录的体重，没有体重记录时清空，
+// 只更新 weight 字段以免覆盖同时进行的资料修改
+func syncUserWeight(ctx context.Context, bodyMetricRepository domain.BodyMetricRepository, userRepository domain.UserRepository, user domain.User) error {
+	metrics, err := bodyMetricRepository.GetByUserID(ctx, user.ID.Hex(), "", "")
+	if err != nil {
+		return err
+	}
+
+	weight := 0.0
+	for i := len(metrics) - 1; i >= 0; i-- {
+		if metrics[i].Weight != nil {
+			weight = *metrics[i].Weight
+			break
+		}
+	}
+	if weight == user.Weight {
+		return nil
+	}
+	return userRepository.UpdateWeight(ctx, user.ID.Hex(), weight)
+}
+
+func hasBodyMetricValues(metric domain.BodyMetric) bool {
+	return metric.Weight != nil || metric.BodyFat != nil || metric.Waist != nil ||
+		metric.Chest != nil || metric.Arm != nil || metric.Thigh != nil
+}
+
+// weeklyRate 两个日期之间每周的平均变化量，同一天时为 0
+func weeklyRate(fromDate string, fromValue float64, toDate string, toValue float64) float64 {
+	days, err := timeutil.DaysBetween(fromDate, toDate)
+	if err != nil || days <= 0 {
+		return 0
+	}
+	return roundMetric((toValue - fromValue) / float64(days) * 7)
+}
+
+func validDate(date string) bool {
+	_, err := time.Parse(timeutil.DateLayout, date)
+	return err == nil
+}
+
+func roundMetric(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func weightEntry(date string, weight float64) domain.BodyMetric {
+	return domain.BodyMetric{Date: date, Weight: &weight}
+}
+
+func TestBuildTrend(t *testing.T) {
+	waist := 80.0
+	metrics := []domain.BodyMetric{
+		weightEntry("2025-10-30", 81),
+		weightEntry("2025-11-01", 80),
+		{Date: "2025-11-02", Waist: &waist},
+		weightEntry("2025-11-03", 79),
+		weightEntry("2025-11-08", 78),
+	}
+
+	// 区间之前的记录只参与移动平均，没有该指标的记录跳过
+	trend := buildTrend(domain.BodyMetricWeight, metrics, "2025-11-01", 3)
+	assert.Equal(t, []domain.TrendPoint{
+		{Date: "2025-11-01", Value: 80, MovingAverage: 80.5},
+		{Date: "2025-11-03", Value: 79, MovingAverage: 79.5},
+		{Date: "2025-11-08", Value: 78, MovingAverage: 78},
+	}, trend.Points)
+	require.NotNil(t, trend.Latest)
+	assert.Equal(t, 78.0, *trend.Latest)
+	assert.Equal(t, -2.0, trend.Change)
+	// 7 天内移动平均从 80.5 降到 78
+	assert.Equal(t, -2.5, trend.WeeklyRate)
+
+	empty := buildTrend(domain.BodyMetricBodyFat, metrics, "2025-11-01", 3)
+	assert.Empty(t, empty.Points)
+	assert.Nil(t, empty.Latest)
+}
+
+func TestGetWeightProgress(t *testing.T) {
+	user := domain.User{ID: primitive.NewObjectID(), Weight: 78, TargetWeight: 75}
+	userID := user.ID.Hex()
+
+	newStatsUsecase := func(t *testing.T, metrics []domain.BodyMetric) *statsUsecase {
+		users := mocks.NewUserRepository(t)
+		users.On("GetByID", mock.Anything, userID).Return(user, nil).Once()
+		bodyMetrics := mocks.NewBodyMetricRepository(t)
+		bodyMetrics.On("GetByUserID", mock.Anything, userID, "", "").Return(metrics, nil).Once()
+		return &statsUsecase{userRepository: users, bodyMetricRepository: bodyMetrics, contextTimeout: time.Second}
+	}
+
+	t.Run("losing toward the target", func(t *testing.T) {
+		su := newStatsUsecase(t, []domain.BodyMetric{
+			weightEntry("2025-09-01", 85),
+			weightEntry("2025-10-20", 80),
+			weightEntry("2025-11-03", 79),
+		})
+		progress, err := su.GetWeightProgress(context.Background(), userID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 85.0, progress.StartWeight)
+		assert.Equal(t, 79.0, progress.CurrentWeight)
+		assert.Equal(t, -6.0, progress.Change)
+		assert.Equal(t, -4.0, progress.Remaining)
+		assert.Equal(t, 60.0, progress.Progress)
+		// 最近 4 周每周下降 0.5kg，还需 8 周
+		assert.Equal(t, -0.5, progress.WeeklyRate)
+		assert.Equal(t, "2025-12-29", progress.EstimatedDate)
+	})
+
+	t.Run("moving away from the target has no estimate", func(t *testing.T) {
+		su := newStatsUsecase(t, []domain.BodyMetric{
+			weightEntry("2025-10-20", 77),
+			weightEntry("2025-11-03", 79),
+		})
+		progress, err := su.GetWeightProgress(context.Background(), userID)
+		require.NoError(t, err)
+
+		assert.Zero(t, progress.Progress)
+		assert.Equal(t, 1.0, progress.WeeklyRate)
+		assert.Empty(t, progress.EstimatedDate)
+	})
+
+	t.Run("falls back to the profile weight", func(t *testing.T) {
+		progress, err := newStatsUsecase(t, nil).GetWeightProgress(context.Background(), userID)
+		require.NoError(t, err)
+
+		assert.Equal(t, 78.0, progress.StartWeight)
+		assert.Equal(t, 78.0, progress.CurrentWeight)
+		assert.Equal(t, -3.0, progress.Remaining)
+		assert.Empty(t, progress.CurrentDate)
+	})
+}
@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// verificationCodeMaxAttempts 单个验证码允许的最大错误次数，超过后需重新获取
+	verificationCodeMaxAttempts = 5
+	// verificationCodeResendInterval 两次发送验证码的最小间隔
+	verificationCodeResendInterval = time.Minute
+)
+
+type emailVerificationUsecase struct {
+	userRepository              domain.UserRepository
+	emailVerificationRepository domain.EmailVerificationRepository
+	notifier                    domain.Notifier
+	contextTimeout              time.Duration
+}
+
+func NewEmailVerificationUsecase(
+	userRepository domain.UserRepository,
+	emailVerificationRepository domain.EmailVerificationRepository,
+	notifier domain.Notifier,
+	timeout time.Duration,
+) domain.EmailVerificationUsecase {
+	return &emailVerificationUsecase{
+		userRepository:              userRepository,
+		emailVerificationRepository: emailVerificationRepository,
+		notifier:                    notifier,
+		contextTimeout:              timeout,
+	}
+}
+
+func (eu *emailVerificationUsecase) SendCode(c context.Context, userID string, expiryMinute int) error {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	user, err := eu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if user.Email == "" {
+		return domain.ErrEmailNotSet
+	}
+	if user.EmailVerified {
+		return domain.ErrEmailAlreadyVerified
+	}
+
+	latest, err := eu.emailVerificationRepository.GetLatestByUserID(ctx, userID)
+	if err == nil && time.Since(latest.CreatedAt.Time()) < verificationCodeResendInterval {
+		return domain.ErrVerificationCodeTooFrequent
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return err
+	}
+
+	// 同一用户只保留最新的一个验证码
+	err = eu.emailVerificationRepository.InvalidateByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	verification := domain.EmailVerification{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		Email:     user.Email,
+		CodeHash:  hashVerificationCode(userID, code),
+		ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(time.Minute * time.Duration(expiryMinute))),
+	}
+	err = eu.emailVerificationRepository.Create(ctx, &verification)
+	if err != nil {
+		return err
+	}
+
+	return eu.notifier.Send(ctx, domain.Notification{
+		To:      user.Email,
+		Subject: "验证邮箱",
+		Body:    fmt.Sprintf("您的邮箱验证码为：%s\n%d 分钟内有效。如非本人操作请忽略。", code, expiryMinute),
+	})
+}
+
+func (eu *emailVerificationUsecase) Verify(c context.Context, userID string, code string) error {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	verification, err := eu.emailVerificationRepository.GetLatestByUserID(ctx, userID)
+	if err != nil {
+		return domain.ErrVerificationCodeInvalid
+	}
+
+	if verification.Attempts >= verificationCodeMaxAttempts || verification.ExpiresAt.Time().Before(time.Now()) {
+		return domain.ErrVerificationCodeInvalid
+	}
+
+	if subtle.ConstantTimeCompare([]byte(verification.CodeHash), []byte(hashVerificationCode(userID, code))) != 1 {
+		// 记录错误次数，防止暴力猜测 6 位验证码
+		if err := eu.emailVerificationRepository.IncrementAttempts(ctx, verification.ID.Hex()); err != nil {
+			return err
+		}
+		return domain.ErrVerificationCodeInvalid
+	}
+
+	consumed, err := eu.emailVerificationRepository.MarkUsed(ctx, verification.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return domain.ErrVerificationCodeInvalid
+	}
+
+	updated, err := eu.userRepository.SetEmailVerified(ctx, userID, verification.Email)
+	if err != nil {
+		return err
+	}
+	if !updated {
+		// 验证码发出后邮箱已被修改
+		return domain.ErrVerificationCodeInvalid
+	}
+
+	return nil
+}
+
+func (eu *emailVerificationUsecase) IsEmailVerified(c context.Context, userID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	user, err := eu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	return user.Email != "" && user.EmailVerified, nil
+}
+
+// generateVerificationCode 生成 6 位数字验证码
+func generateVerificationCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// hashVerificationCode 验证码摘要混入用户ID，相同验证码在不同用户下摘要不同
+func hashVerificationCode(userID, code string) string {
+	sum := sha256.Sum256([]byte(userID + ":" + code))
+	return hex.EncodeToString(sum[:])
+}
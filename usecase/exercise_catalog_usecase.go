@@ -0,0 +1,240 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/calorieutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type exerciseCatalogUsecase struct {
+	exerciseCatalogRepository domain.ExerciseCatalogRepository
+	contextTimeout            time.Duration
+}
+
+func NewExerciseCatalogUsecase(exerciseCatalogRepository domain.ExerciseCatalogRepository, timeout time.Duration) domain.ExerciseCatalogUsecase {
+	return &exerciseCatalogUsecase{
+		exerciseCatalogRepository: exerciseCatalogRepository,
+		contextTimeout:            timeout,
+	}
+}
+
+func (eu *exerciseCatalogUsecase) Create(c context.Context, request *domain.CreateCatalogExerciseRequest) (domain.CatalogExercise, error) {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	exercise := domain.CatalogExercise{
+		ID:               primitive.NewObjectID(),
+		Name:             strings.TrimSpace(request.Name),
+		Aliases:          cleanTerms(request.Aliases),
+		PrimaryMuscles:   cleanTerms(request.PrimaryMuscles),
+		SecondaryMuscles: cleanTerms(request.SecondaryMuscles),
+		Equipment:        strings.TrimSpace(request.Equipment),
+		MovementPattern:  strings.TrimSpace(request.MovementPattern),
+		Category:         strings.TrimSpace(request.Category),
+		MET:              request.MET,
+	}
+	exercise.SearchTerms = searchTerms(exercise.Name, exercise.Aliases)
+
+	err := eu.exerciseCatalogRepository.Create(ctx, &exercise)
+	return exercise, err
+}
+
+func (eu *exerciseCatalogUsecase) GetByID(c context.Context, id string) (domain.CatalogExercise, error) {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	return eu.exerciseCatalogRepository.GetByID(ctx, id)
+}
+
+func (eu *exerciseCatalogUsecase) Update(c context.Context, id string, request *domain.UpdateCatalogExerciseRequest) (domain.CatalogExercise, error) {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	exercise, err := eu.exerciseCatalogRepository.GetByID(ctx, id)
+	if err != nil {
+		return exercise, err
+	}
+
+	if request.Name != nil {
+		exercise.Name = strings.TrimSpace(*request.Name)
+	}
+	if request.Aliases != nil {
+		exercise.Aliases = cleanTerms(request.Aliases)
+	}
+	if request.PrimaryMuscles != nil {
+		exercise.PrimaryMuscles = cleanTerms(request.PrimaryMuscles)
+	}
+	if request.SecondaryMuscles != nil {
+		exercise.SecondaryMuscles = cleanTerms(request.SecondaryMuscles)
+	}
+	if request.Equipment != nil {
+		exercise.Equipment = strings.TrimSpace(*request.Equipment)
+	}
+	if request.MovementPattern != nil {
+		exercise.MovementPattern = strings.TrimSpace(*request.MovementPattern)
+	}
+	if request.Category != nil {
+		exercise.Category = strings.TrimSpace(*request.Category)
+	}
+	if request.MET != nil {
+		exercise.MET = *request.MET
+	}
+	exercise.SearchTerms = searchTerms(exercise.Name, exercise.Aliases)
+
+	err = eu.exerciseCatalogRepository.Update(ctx, &exercise)
+	return exercise, err
+}
+
+func (eu *exerciseCatalogUsecase) Delete(c context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	return eu.exerciseCatalogRepository.Delete(ctx, id)
+}
+
+func (eu *exerciseCatalogUsecase) Search(c context.Context, keyword, muscle, equipment string, page, pageSize int) ([]domain.CatalogExercise, int64, error) {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	return eu.exerciseCatalogRepository.Search(ctx, keyword, muscle, equipment, page, pageSize)
+}
+
+func (eu *exerciseCatalogUsecase) Autocomplete(c context.Context, prefix string, limit int) ([]domain.CatalogExercise, error) {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	if domain.NormalizeExerciseTerm(prefix) == "" {
+		return []domain.CatalogExercise{}, nil
+	}
+
+	return eu.exerciseCatalogRepository.Autocomplete(ctx, prefix, limit)
+}
+
+func (eu *exerciseCatalogUsecase) Resolve(c context.Context, exercises []domain.Exercise) error {
+	ctx, cancel := context.WithTimeout(c, eu.contextTimeout)
+	defer cancel()
+
+	var ids, terms []string
+	for _, exercise := range exercises {
+		if exercise.ExerciseID != "" {
+			ids = append(ids, exercise.ExerciseID)
+		} else if term := domain.NormalizeExerciseTerm(exercise.Name); term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	byID := map[string]domain.CatalogExercise{}
+	if len(ids) > 0 {
+		found, err := eu.exerciseCatalogRepository.GetByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		for _, entry := range found {
+			byID[entry.ID.Hex()] = entry
+		}
+	}
+
+	byTerm := map[string][]domain.CatalogExercise{}
+	if len(terms) > 0 {
+		found, err := eu.exerciseCatalogRepository.FindByTerms(ctx, terms)
+		if err != nil {
+			return err
+		}
+		for _, entry := range found {
+			for _, term := range entry.SearchTerms {
+				byTerm[term] = append(byTerm[term], entry)
+			}
+		}
+	}
+
+	for i := range exercises {
+		exercise := &exercises[i]
+		if exercise.ExerciseID != "" {
+			entry, ok := byID[exercise.ExerciseID]
+			if !ok {
+				return domain.ErrExerciseNotFound
+			}
+			applyCatalogEntry(exercise, entry)
+			continue
+		}
+
+		// 自由文本名称只在唯一匹配时关联，存在歧义的别名保持原样
+		matches := byTerm[domain.NormalizeExerciseTerm(exercise.Name)]
+		if len(matches) == 1 {
+			exercise.ExerciseID = matches[0].ID.Hex()
+			applyCatalogEntry(exercise, matches[0])
+		}
+	}
+
+	return nil
+}
+
+// applyCatalogEntry 用动作库信息补全用户未填写的名称、肌群和代谢当量
+func applyCatalogEntry(exercise *domain.Exercise, entry domain.CatalogExercise) {
+	if exercise.MET == nil {
+		met := calorieutil.CatalogMET(entry)
+		exercise.MET = &met
+	}
+	if strings.TrimSpace(exercise.Name) == "" {
+		exercise.Name = entry.Name
+	}
+	if exercise.MuscleGroup == nil && len(entry.PrimaryMuscles) > 0 {
+		muscleGroup := entry.PrimaryMuscles[0]
+		exercise.MuscleGroup = &muscleGroup
+	}
+}
+
+// resolveTrainingDays 校验训练日中动作的进阶规则，并一次查询关联所有动作
+func resolveTrainingDays(c context.Context, resolver domain.ExerciseResolver, days []domain.TrainingDay) error {
+	var exercises []domain.Exercise
+	for _, day := range days {
+		exercises = append(exercises, day.Exercises...)
+	}
+	if len(exercises) == 0 {
+		return nil
+	}
+
+	if err := validateProgressions(exercises); err != nil {
+		return err
+	}
+
+	if err := resolver.Resolve(c, exercises); err != nil {
+		return err
+	}
+
+	offset := 0
+	for i := range days {
+		n := len(days[i].Exercises)
+		copy(days[i].Exercises, exercises[offset:offset+n])
+		offset += n
+	}
+	return nil
+}
+
+func cleanTerms(values []string) []string {
+	cleaned := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	return cleaned
+}
+
+func searchTerms(name string, aliases []string) []string {
+	seen := map[string]bool{}
+	terms := []string{}
+	for _, value := range append([]string{name}, aliases...) {
+		term := domain.NormalizeExerciseTerm(value)
+		if term == "" || seen[term] {
+			continue
+		}
+		seen[term] = true
+		terms = append(terms, term)
+	}
+	return terms
+}
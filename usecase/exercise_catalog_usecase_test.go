@@ -0,0 +1,88 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var benchPress = domain.CatalogExercise{
+	ID:             primitive.NewObjectID(),
+	Name:           "杠铃卧推",
+	Aliases:        []string{"卧推", "Bench Press"},
+	PrimaryMuscles: []string{"胸"},
+	Category:       domain.ExerciseCategoryStrength,
+	MET:            5,
+	SearchTerms:    []string{"杠铃卧推", "卧推", "bench press"},
+}
+
+func TestCreateCatalogExercise(t *testing.T) {
+	catalog := mocks.NewExerciseCatalogRepository(t)
+	catalog.On("Create", mock.Anything, mock.AnythingOfType("*domain.CatalogExercise")).Return(nil).Once()
+
+	exercise, err := usecase.NewExerciseCatalogUsecase(catalog, time.Second).Create(context.Background(), &domain.CreateCatalogExerciseRequest{
+		Name:           " 杠铃卧推 ",
+		Aliases:        []string{"卧推", " ", "BENCH PRESS", "bench press "},
+		PrimaryMuscles: []string{"胸", ""},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "杠铃卧推", exercise.Name)
+	assert.Equal(t, []string{"胸"}, exercise.PrimaryMuscles)
+	// 名称和别名规范化后去重
+	assert.Equal(t, []string{"杠铃卧推", "卧推", "bench press"}, exercise.SearchTerms)
+}
+
+func TestResolveExercises(t *testing.T) {
+	t.Run("links names and fills catalog fields", func(t *testing.T) {
+		catalog := mocks.NewExerciseCatalogRepository(t)
+		catalog.On("GetByIDs", mock.Anything, []string{benchPress.ID.Hex()}).Return([]domain.CatalogExercise{benchPress}, nil).Once()
+		catalog.On("FindByTerms", mock.Anything, []string{"bench press", "深蹲"}).Return([]domain.CatalogExercise{benchPress}, nil).Once()
+
+		back := "背"
+		exercises := []domain.Exercise{
+			{ExerciseID: benchPress.ID.Hex()},
+			{Name: " Bench Press ", MuscleGroup: &back},
+			{Name: "深蹲"},
+		}
+		require.NoError(t, usecase.NewExerciseCatalogUsecase(catalog, time.Second).Resolve(context.Background(), exercises))
+
+		assert.Equal(t, "杠铃卧推", exercises[0].Name)
+		assert.Equal(t, "胸", *exercises[0].MuscleGroup)
+		assert.Equal(t, 5.0, *exercises[0].MET)
+
+		// 用户填写的名称和肌群保留
+		assert.Equal(t, benchPress.ID.Hex(), exercises[1].ExerciseID)
+		assert.Equal(t, " Bench Press ", exercises[1].Name)
+		assert.Equal(t, "背", *exercises[1].MuscleGroup)
+
+		// 动作库中没有的自由文本保持原样
+		assert.Empty(t, exercises[2].ExerciseID)
+		assert.Nil(t, exercises[2].MET)
+	})
+
+	t.Run("ambiguous alias is not linked", func(t *testing.T) {
+		dumbbellPress := domain.CatalogExercise{ID: primitive.NewObjectID(), Name: "哑铃卧推", SearchTerms: []string{"哑铃卧推", "卧推"}}
+		catalog := mocks.NewExerciseCatalogRepository(t)
+		catalog.On("FindByTerms", mock.Anything, []string{"卧推"}).Return([]domain.CatalogExercise{benchPress, dumbbellPress}, nil).Once()
+
+		exercises := []domain.Exercise{{Name: "卧推"}}
+		require.NoError(t, usecase.NewExerciseCatalogUsecase(catalog, time.Second).Resolve(context.Background(), exercises))
+		assert.Empty(t, exercises[0].ExerciseID)
+	})
+
+	t.Run("unknown exercise id", func(t *testing.T) {
+		catalog := mocks.NewExerciseCatalogRepository(t)
+		catalog.On("GetByIDs", mock.Anything, mock.Anything).Return([]domain.CatalogExercise{}, nil).Once()
+
+		err := usecase.NewExerciseCatalogUsecase(catalog, time.Second).Resolve(context.Background(), []domain.Exercise{{ExerciseID: primitive.NewObjectID().Hex()}})
+		assert.ErrorIs(t, err, domain.ErrExerciseNotFound)
+	})
+}
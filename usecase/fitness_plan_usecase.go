@@ -3,23 +3,41 @@ package usecase
 import (
 	"context"
 	"errors"
+	"sort"
 	"time"
 
 	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type fitnessPlanUsecase struct {
-	fitnessPlanRepository  domain.FitnessPlanRepository
-	planTemplateRepository domain.PlanTemplateRepository
-	contextTimeout         time.Duration
+	fitnessPlanRepository    domain.FitnessPlanRepository
+	planTemplateRepository   domain.PlanTemplateRepository
+	trainingRecordRepository domain.TrainingRecordRepository
+	personalRecordRepository domain.PersonalRecordRepository
+	userRepository           domain.UserRepository
+	exerciseResolver         domain.ExerciseResolver
+	planDayLinker            planDayLinker
+	transactor               domain.Transactor
+	contextTimeout           time.Duration
 }
 
-func NewFitnessPlanUsecase(fitnessPlanRepository domain.FitnessPlanRepository, planTemplateRepository domain.PlanTemplateRepository, timeout time.Duration) domain.FitnessPlanUsecase {
+func NewFitnessPlanUsecase(fitnessPlanRepository domain.FitnessPlanRepository, planTemplateRepository domain.PlanTemplateRepository, trainingRecordRepository domain.TrainingRecordRepository, personalRecordRepository domain.PersonalRecordRepository, userRepository domain.UserRepository, exerciseResolver domain.ExerciseResolver, transactor domain.Transactor, timeout time.Duration) domain.FitnessPlanUsecase {
 	return &fitnessPlanUsecase{
-		fitnessPlanRepository:  fitnessPlanRepository,
-		planTemplateRepository: planTemplateRepository,
-		contextTimeout:         timeout,
+		fitnessPlanRepository:    fitnessPlanRepository,
+		planTemplateRepository:   planTemplateRepository,
+		trainingRecordRepository: trainingRecordRepository,
+		personalRecordRepository: personalRecordRepository,
+		userRepository:           userRepository,
+		exerciseResolver:         exerciseResolver,
+		planDayLinker: planDayLinker{
+			fitnessPlanRepository:    fitnessPlanRepository,
+			trainingRecordRepository: trainingRecordRepository,
+		},
+		transactor:     transactor,
+		contextTimeout: timeout,
 	}
 }
 
@@ -46,7 +64,7 @@ func (fu *fitnessPlanUsecase) CreateFromTemplate(c context.Context, userID strin
 	}
 
 	// Parse start date and calculate end date
-	startDate, err := time.Parse("2006-01-02", request.StartDate)
+	startDate, err := time.Parse(timeutil.DateLayout, request.StartDate)
 	if err != nil {
 		return nil, errors.New("invalid start date format")
 	}
@@ -72,10 +90,24 @@ func (fu *fitnessPlanUsecase) CreateFromTemplate(c context.Context, userID strin
 		trainingDays = []domain.TrainingDay{}
 	}
 
+	// 模板阶段需覆盖实际的计划周数
+	phases := append([]domain.PlanPhase{}, template.Phases...)
+	if err := validatePhases(phases, durationWeeks); err != nil {
+		return nil, err
+	}
+
+	trainingWeekdays, err := planWeekdays(request.TrainingWeekdays)
+	if err != nil {
+		return nil, err
+	}
+
 	// Handle training days override
 	var trainingDaysOverride []domain.TrainingDay
 	if request.TrainingDaysOverride != nil && len(request.TrainingDaysOverride) > 0 {
 		trainingDaysOverride = request.TrainingDaysOverride
+		if err := resolveTrainingDays(ctx, fu.exerciseResolver, trainingDaysOverride); err != nil {
+			return nil, err
+		}
 	}
 
 	completedDays := []int{}
@@ -94,8 +126,10 @@ func (fu *fitnessPlanUsecase) CreateFromTemplate(c context.Context, userID strin
 		TrainingDaysPerWeek:   template.TrainingDaysPerWeek,
 		TrainingDays:          trainingDays,
 		TrainingDaysOverride:  trainingDaysOverride,
+		Phases:                phases,
+		TrainingWeekdays:      trainingWeekdays,
 		StartDate:             request.StartDate,
-		EndDate:               endDate.Format("2006-01-02"),
+		EndDate:               endDate.Format(timeutil.DateLayout),
 		Status:                "进行中",
 		CurrentWeek:           1,
 		CurrentDay:            1,
@@ -134,7 +168,7 @@ func (fu *fitnessPlanUsecase) CreateCustom(c context.Context, userID string, req
 	}
 
 	// Parse start date and calculate end date
-	startDate, err := time.Parse("2006-01-02", request.StartDate)
+	startDate, err := time.Parse(timeutil.DateLayout, request.StartDate)
 	if err != nil {
 		return nil, errors.New("invalid start date format")
 	}
@@ -148,6 +182,19 @@ func (fu *fitnessPlanUsecase) CreateCustom(c context.Context, userID string, req
 		trainingDays = []domain.TrainingDay{}
 	}
 
+	// Link exercises to the catalog
+	if err := resolveTrainingDays(ctx, fu.exerciseResolver, trainingDays); err != nil {
+		return nil, err
+	}
+	if err := resolvePhases(ctx, fu.exerciseResolver, request.Phases, request.DurationWeeks); err != nil {
+		return nil, err
+	}
+
+	trainingWeekdays, err := planWeekdays(request.TrainingWeekdays)
+	if err != nil {
+		return nil, err
+	}
+
 	completedDays := []int{}
 	skippedDays := []int{}
 
@@ -162,8 +209,10 @@ func (fu *fitnessPlanUsecase) CreateCustom(c context.Context, userID string, req
 		DurationWeeks:       request.DurationWeeks,
 		TrainingDaysPerWeek: request.TrainingDaysPerWeek,
 		TrainingDays:        trainingDays,
+		Phases:              request.Phases,
+		TrainingWeekdays:    trainingWeekdays,
 		StartDate:           request.StartDate,
-		EndDate:             endDate.Format("2006-01-02"),
+		EndDate:             endDate.Format(timeutil.DateLayout),
 		Status:              "进行中",
 		CurrentWeek:         1,
 		CurrentDay:          1,
@@ -205,6 +254,12 @@ func (fu *fitnessPlanUsecase) GetByID(c context.Context, userID, planID string)
 		return domain.FitnessPlan{}, errors.New("unauthorized access to fitness plan")
 	}
 
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.FitnessPlan{}, err
+	}
+	applyPlanPosition(&plan, user, timeutil.Today(timeutil.Location(user.TimeZone)))
+
 	return plan, nil
 }
 
@@ -222,6 +277,15 @@ func (fu *fitnessPlanUsecase) GetList(c context.Context, userID string, status s
 		plans = []domain.FitnessPlan{}
 	}
 
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	today := timeutil.Today(timeutil.Location(user.TimeZone))
+	for i := range plans {
+		applyPlanPosition(&plans[i], user, today)
+	}
+
 	return plans, total, nil
 }
 
@@ -256,6 +320,15 @@ func (fu *fitnessPlanUsecase) CompleteDay(c context.Context, userID, planID stri
 		return nil, errors.New("unauthorized access to fitness plan")
 	}
 
+	if !validPlanDay(plan, dayNumber) {
+		return nil, domain.ErrInvalidPlanDay
+	}
+
+	// 指定训练记录时将记录关联到该计划日，完成状态和计划累计数据随记录维护
+	if recordID != "" {
+		return fu.linkRecord(ctx, userID, planID, dayNumber, recordID)
+	}
+
 	// Check if day is already completed
 	for _, completedDay := range plan.CompletedDays {
 		if completedDay == dayNumber {
@@ -267,17 +340,9 @@ func (fu *fitnessPlanUsecase) CompleteDay(c context.Context, userID, planID stri
 	plan.CompletedDays = append(plan.CompletedDays, dayNumber)
 	plan.TotalCompletedDays++
 
-	// Calculate total training days (non-rest days)
-	totalTrainingDays := 0
-	for _, day := range plan.TrainingDays {
-		if !day.IsRestDay {
-			totalTrainingDays++
-		}
-	}
-
 	// Calculate completion rate
-	if totalTrainingDays > 0 {
-		plan.CompletionRate = (plan.TotalCompletedDays * 100) / (totalTrainingDays * plan.DurationWeeks)
+	if totalSessions := plan.TotalSessions(); totalSessions > 0 {
+		plan.CompletionRate = (plan.TotalCompletedDays * 100) / totalSessions
 	}
 
 	plan.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
@@ -321,8 +386,13 @@ func (fu *fitnessPlanUsecase) UncompleteDay(c context.Context, userID, planID st
 		return nil, errors.New("day is not completed")
 	}
 
-	// Uncomplete the day
-	err = fu.fitnessPlanRepository.UncompletePlanDay(ctx, planID, dayNumber)
+	// 解除该日关联的训练记录，记录仍计入计划累计数据
+	err = fu.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := fu.trainingRecordRepository.ClearPlanDay(ctx, planID, dayNumber); err != nil {
+			return err
+		}
+		return fu.fitnessPlanRepository.UncompletePlanDay(ctx, planID, dayNumber)
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -339,6 +409,138 @@ func (fu *fitnessPlanUsecase) UncompleteDay(c context.Context, userID, planID st
 	}, nil
 }
 
+func (fu *fitnessPlanUsecase) GetUpcomingSessions(c context.Context, userID string, days int) ([]domain.PlanSession, error) {
+	ctx, cancel := context.WithTimeout(c, fu.contextTimeout)
+	defer cancel()
+
+	if days <= 0 {
+		days = domain.DefaultUpcomingDays
+	}
+	if days > maxUpcomingDays {
+		days = maxUpcomingDays
+	}
+
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := timeutil.Today(timeutil.Location(user.TimeZone))
+	endDate := addDays(today, days-1)
+
+	plans, _, err := fu.fitnessPlanRepository.GetByUserID(ctx, userID, "进行中", 1, maxActivePlans)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []domain.PlanSession{}
+	for _, plan := range plans {
+		schedule, err := planSchedule(plan, user)
+		if err != nil {
+			// 开始日期无效的计划无法排期
+			continue
+		}
+		planSessions := schedule.Sessions(today, endDate, today)
+		if err := fu.applyProgressions(ctx, user, plan, schedule, planSessions); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, planSessions...)
+	}
+
+	sort.SliceStable(sessions, func(i, j int) bool {
+		if sessions[i].Date != sessions[j].Date {
+			return sessions[i].Date < sessions[j].Date
+		}
+		return sessions[i].PlanName < sessions[j].PlanName
+	})
+
+	return sessions, nil
+}
+
+func (fu *fitnessPlanUsecase) GetWeek(c context.Context, userID, planID string, week int) ([]domain.PlanSession, error) {
+	ctx, cancel := context.WithTimeout(c, fu.contextTimeout)
+	defer cancel()
+
+	plan, err := fu.fitnessPlanRepository.GetByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	if plan.UserID.Hex() != userID {
+		return nil, errors.New("unauthorized access to fitness plan")
+	}
+
+	if week < 1 || week > plan.DurationWeeks {
+		return nil, domain.ErrInvalidPlanWeek
+	}
+
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := timeutil.Today(timeutil.Location(user.TimeZone))
+
+	schedule, err := planSchedule(plan, user)
+	if err != nil {
+		return nil, errors.New("invalid start date format")
+	}
+
+	sessions := []domain.PlanSession{}
+	for _, session := range schedule.Sessions("", schedule.EndDate(), today) {
+		if session.Week == week {
+			sessions = append(sessions, session)
+		}
+	}
+
+	if err := fu.applyProgressions(ctx, user, plan, schedule, sessions); err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+// planWeekdays 校验创建计划时指定的训练星期，未指定时返回 nil 使用用户偏好
+func planWeekdays(weekdays []int) ([]int, error) {
+	if len(weekdays) == 0 {
+		return nil, nil
+	}
+	return sortedWeekdays(weekdays)
+}
+
+// linkRecord 将训练记录改为关联到计划日，记录原来关联的计划日和计划累计数据在同一事务中更新
+func (fu *fitnessPlanUsecase) linkRecord(ctx context.Context, userID, planID string, dayNumber int, recordID string) (map[string]interface{}, error) {
+	record, err := fu.trainingRecordRepository.GetByID(ctx, recordID)
+	if err != nil {
+		return nil, err
+	}
+	if record.UserID.Hex() != userID {
+		return nil, domain.ErrTrainingRecordNotFound
+	}
+
+	before := record
+	record.PlanID = planID
+	record.PlanDayID = &dayNumber
+
+	err = fu.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := fu.trainingRecordRepository.Update(ctx, recordID, &record); err != nil {
+			return err
+		}
+		return fu.planDayLinker.sync(ctx, userID, &before, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	updatedPlan, err := fu.fitnessPlanRepository.GetByID(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"completionRate":     updatedPlan.CompletionRate,
+		"totalCompletedDays": updatedPlan.TotalCompletedDays,
+	}, nil
+}
+
 func (fu *fitnessPlanUsecase) Delete(c context.Context, userID, planID string) error {
 	ctx, cancel := context.WithTimeout(c, fu.contextTimeout)
 	defer cancel()
@@ -371,7 +573,7 @@ func (fu *fitnessPlanUsecase) GetProgress(c context.Context, userID, planID stri
 	}
 
 	// Calculate total training days (non-rest days)
-	totalDays := plan.DurationWeeks * plan.TrainingDaysPerWeek
+	totalDays := plan.TotalSessions()
 	completedDays := len(plan.CompletedDays)
 	skippedDays := len(plan.SkippedDays)
 
@@ -382,31 +584,30 @@ func (fu *fitnessPlanUsecase) GetProgress(c context.Context, userID, planID stri
 		completionRate = (completedDays * 100) / effectiveTotalDays
 	}
 
-	// Calculate next training date
-	nextTrainingDate := ""
-	if plan.Status == "进行中" {
-		startDate, err := time.Parse("2006-01-02", plan.StartDate)
-		if err == nil {
-			// Find next uncompleted day
-			nextDayNum := completedDays + skippedDays + 1
-			// Calculate the date for the next day
-			nextDate := startDate.AddDate(0, 0, nextDayNum-1)
-			nextTrainingDate = nextDate.Format("2006-01-02")
-		}
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.PlanProgress{}, err
 	}
+	today := timeutil.Today(timeutil.Location(user.TimeZone))
 
-	// Calculate current week and day
+	// 按用户时区的今天计算进行到第几天，下次训练取日程中今天起第一次未完成也未跳过的训练
 	currentWeek := 1
 	currentDay := 1
-	if plan.Status == "进行中" {
-		startDate, err := time.Parse("2006-01-02", plan.StartDate)
-		if err == nil {
-			daysSinceStart := int(time.Since(startDate).Hours() / 24)
-			if daysSinceStart >= 0 {
-				currentWeek = (daysSinceStart / 7) + 1
-				currentDay = (daysSinceStart % 7) + 1
+	nextTrainingDate := ""
+	var phases []domain.PhaseProgress
+	schedule, err := planSchedule(plan, user)
+	if err == nil {
+		currentWeek, currentDay = schedule.Position(today)
+		if plan.Status == "进行中" {
+			if next, ok := schedule.Next(today); ok {
+				nextTrainingDate = next.Date
 			}
 		}
+		phases = phaseProgress(plan, schedule)
+	}
+	currentPhase := ""
+	if phase := plan.PhaseAt(currentWeek); phase != nil {
+		currentPhase = phase.Name
 	}
 
 	progress := domain.PlanProgress{
@@ -418,6 +619,8 @@ func (fu *fitnessPlanUsecase) GetProgress(c context.Context, userID, planID stri
 		CurrentWeek:      currentWeek,
 		CurrentDay:       currentDay,
 		NextTrainingDate: nextTrainingDate,
+		CurrentPhase:     currentPhase,
+		Phases:           phases,
 		TotalDuration:    plan.TotalDuration,
 		TotalWeight:      plan.TotalWeight,
 		TotalCalories:    plan.TotalCalories,
@@ -440,6 +643,10 @@ func (fu *fitnessPlanUsecase) SkipDay(c context.Context, userID, planID string,
 		return nil, errors.New("unauthorized access to fitness plan")
 	}
 
+	if !validPlanDay(plan, dayNumber) {
+		return nil, domain.ErrInvalidPlanDay
+	}
+
 	// Check if day is already completed
 	for _, completedDay := range plan.CompletedDays {
 		if completedDay == dayNumber {
@@ -455,7 +662,7 @@ func (fu *fitnessPlanUsecase) SkipDay(c context.Context, userID, planID string,
 	}
 
 	// Skip the day
-	err = fu.fitnessPlanRepository.SkipPlanDay(ctx, planID, dayNumber)
+	err = fu.fitnessPlanRepository.SkipPlanDay(ctx, planID, dayNumber, reason)
 	if err != nil {
 		return nil, err
 	}
@@ -487,10 +694,160 @@ func (fu *fitnessPlanUsecase) AdjustDay(c context.Context, userID, planID string
 	}
 
 	// Validate day number
-	totalDays := plan.DurationWeeks * 7
-	if dayNumber < 1 || dayNumber > totalDays {
+	if !validPlanDay(plan, dayNumber) {
 		return errors.New("invalid day number")
 	}
 
+	if err := validateProgressions(exercises); err != nil {
+		return err
+	}
+
+	if err := fu.exerciseResolver.Resolve(ctx, exercises); err != nil {
+		return err
+	}
+
 	return fu.fitnessPlanRepository.UpdateTrainingDay(ctx, planID, dayNumber, exercises, notes)
 }
+
+func (fu *fitnessPlanUsecase) ShiftDays(c context.Context, userID, planID string, request *domain.ShiftDaysRequest) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(c, fu.contextTimeout)
+	defer cancel()
+
+	if request.Days < 1 || request.Days > maxShiftDays {
+		return nil, domain.ErrInvalidShiftDays
+	}
+
+	return fu.reschedule(ctx, userID, planID, func(plan domain.FitnessPlan, schedule scheduleutil.Schedule, today string) (domain.PlanReschedule, error) {
+		fromDay := request.FromDay
+		if fromDay == 0 {
+			fromDay = shiftStartDay(plan, schedule, today)
+		}
+		if !validPlanDay(plan, fromDay) {
+			return domain.PlanReschedule{}, domain.ErrInvalidPlanDay
+		}
+
+		// 已完成的训练和关联的训练记录保持原来的计划日
+		for _, completedDay := range plan.CompletedDays {
+			if completedDay >= fromDay {
+				return domain.PlanReschedule{}, domain.ErrPlanDayCompleted
+			}
+		}
+
+		return domain.PlanReschedule{
+			Action:    domain.RescheduleShift,
+			DayNumber: fromDay,
+			Days:      request.Days,
+			Reason:    request.Reason,
+		}, nil
+	})
+}
+
+func (fu *fitnessPlanUsecase) SwapDays(c context.Context, userID, planID string, request *domain.SwapDaysRequest) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(c, fu.contextTimeout)
+	defer cancel()
+
+	return fu.reschedule(ctx, userID, planID, func(plan domain.FitnessPlan, schedule scheduleutil.Schedule, today string) (domain.PlanReschedule, error) {
+		if request.DayNumber == request.TargetDay {
+			return domain.PlanReschedule{}, domain.ErrInvalidPlanDay
+		}
+		for _, dayNumber := range []int{request.DayNumber, request.TargetDay} {
+			if err := reschedulableDay(plan, schedule, dayNumber, today); err != nil {
+				return domain.PlanReschedule{}, err
+			}
+		}
+
+		return domain.PlanReschedule{
+			Action:    domain.RescheduleSwap,
+			DayNumber: request.DayNumber,
+			TargetDay: request.TargetDay,
+			Reason:    request.Reason,
+		}, nil
+	})
+}
+
+func (fu *fitnessPlanUsecase) MoveDay(c context.Context, userID, planID string, request *domain.MoveDayRequest) (map[string]interface{}, error) {
+	ctx, cancel := context.WithTimeout(c, fu.contextTimeout)
+	defer cancel()
+
+	return fu.reschedule(ctx, userID, planID, func(plan domain.FitnessPlan, schedule scheduleutil.Schedule, today string) (domain.PlanReschedule, error) {
+		if err := reschedulableDay(plan, schedule, request.DayNumber, today); err != nil {
+			return domain.PlanReschedule{}, err
+		}
+
+		// 只能移动到今天及之后、计划范围内的日期
+		elapsed, err := timeutil.DaysBetween(plan.StartDate, request.Date)
+		if err != nil || request.Date < today || !validPlanDay(plan, elapsed+1) {
+			return domain.PlanReschedule{}, domain.ErrInvalidPlanDay
+		}
+		targetDay := elapsed + 1
+		if _, ok := schedule.Day(targetDay, today); ok {
+			return domain.PlanReschedule{}, domain.ErrPlanDayOccupied
+		}
+
+		return domain.PlanReschedule{
+			Action:    domain.RescheduleMove,
+			DayNumber: request.DayNumber,
+			TargetDay: targetDay,
+			Reason:    request.Reason,
+		}, nil
+	})
+}
+
+// reschedule 在同一事务中读取计划、生成日程调整记录并重新编号计划日，
+// 并发的完成、跳过操作与调整冲突时由事务重试
+func (fu *fitnessPlanUsecase) reschedule(ctx context.Context, userID, planID string, prepare func(plan domain.FitnessPlan, schedule scheduleutil.Schedule, today string) (domain.PlanReschedule, error)) (map[string]interface{}, error) {
+	user, err := fu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := timeutil.Today(timeutil.Location(user.TimeZone))
+
+	var plan domain.FitnessPlan
+	err = fu.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		plan, err = fu.fitnessPlanRepository.GetByID(ctx, planID)
+		if err != nil {
+			return err
+		}
+		if plan.UserID.Hex() != userID {
+			return errors.New("unauthorized access to fitness plan")
+		}
+
+		schedule, err := planSchedule(plan, user)
+		if err != nil {
+			return errors.New("invalid start date format")
+		}
+
+		change, err := prepare(plan, schedule, today)
+		if err != nil {
+			return err
+		}
+		change.CreatedAt = primitive.NewDateTimeFromTime(time.Now())
+		applyReschedule(&plan, change)
+
+		return fu.fitnessPlanRepository.UpdateSchedule(ctx, planID, &plan)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"endDate":        plan.EndDate,
+		"completionRate": plan.CompletionRate,
+	}, nil
+}
+
+// reschedulableDay 交换或移动的计划日必须在计划范围内、安排了训练且尚未完成
+func reschedulableDay(plan domain.FitnessPlan, schedule scheduleutil.Schedule, dayNumber int, today string) error {
+	if !validPlanDay(plan, dayNumber) {
+		return domain.ErrInvalidPlanDay
+	}
+	session, ok := schedule.Day(dayNumber, today)
+	if !ok {
+		return domain.ErrPlanDayNotScheduled
+	}
+	if session.Status == domain.SessionStatusCompleted {
+		return domain.ErrPlanDayCompleted
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type loginAttemptUsecase struct {
+	loginAttemptRepository domain.LoginAttemptRepository
+	userRepository         domain.UserRepository
+	policy                 domain.LoginLockoutPolicy
+	contextTimeout         time.Duration
+}
+
+func NewLoginAttemptUsecase(loginAttemptRepository domain.LoginAttemptRepository, userRepository domain.UserRepository, policy domain.LoginLockoutPolicy, timeout time.Duration) domain.LoginAttemptUsecase {
+	return &loginAttemptUsecase{
+		loginAttemptRepository: loginAttemptRepository,
+		userRepository:         userRepository,
+		policy:                 policy,
+		contextTimeout:         timeout,
+	}
+}
+
+// attemptKey 失败记录的键，按用户名和IP分别计数
+func attemptKey(scope, value string) string {
+	return scope + ":" + value
+}
+
+// userAttemptKeys 可能属于该用户的失败记录键：用户名，以及未匹配到账号时按登录标识本身记录的邮箱和手机号
+func userAttemptKeys(user domain.User) []string {
+	keys := []string{attemptKey(domain.LoginAttemptScopeUsername, user.Username)}
+	for _, identifier := range []string{user.Email, user.Phone} {
+		if identifier != "" {
+			keys = append(keys, attemptKey(domain.LoginAttemptScopeUsername, identifier))
+		}
+	}
+	return keys
+}
+
+func (lu *loginAttemptUsecase) CheckLocked(c context.Context, usernames []string, ip string) (domain.LoginLockStatus, error) {
+	ctx, cancel := context.WithTimeout(c, lu.contextTimeout)
+	defer cancel()
+
+	status := domain.LoginLockStatus{RemainingAttempts: lu.policy.MaxAttempts}
+	now := time.Now()
+
+	for _, key := range lu.keys(usernames, ip) {
+		attempt, err := lu.loginAttemptRepository.GetByKey(ctx, key)
+		if err != nil {
+			return status, err
+		}
+		if attempt.LockedUntil != nil && attempt.LockedUntil.Time().After(now) {
+			status.Locked = true
+			if attempt.LockedUntil.Time().After(status.LockedUntil) {
+				status.LockedUntil = attempt.LockedUntil.Time()
+			}
+		}
+	}
+
+	if status.Locked {
+		status.RemainingAttempts = 0
+	}
+
+	return status, nil
+}
+
+func (lu *loginAttemptUsecase) RecordFailure(c context.Context, usernames []string, ip string) (domain.LoginLockStatus, error) {
+	ctx, cancel := context.WithTimeout(c, lu.contextTimeout)
+	defer cancel()
+
+	now := time.Now()
+	expiresAt := primitive.NewDateTimeFromTime(now.Add(lu.policy.Window))
+	status := domain.LoginLockStatus{RemainingAttempts: lu.policy.MaxAttempts}
+
+	for _, key := range lu.keys(usernames, ip) {
+		maxAttempts := lu.policy.MaxAttempts
+		if key == attemptKey(domain.LoginAttemptScopeIP, ip) {
+			maxAttempts = lu.policy.MaxIPAttempts
+		}
+
+		attempt, err := lu.loginAttemptRepository.IncrementFailures(ctx, key, expiresAt)
+		if err != nil {
+			return status, err
+		}
+
+		if attempt.Failures >= maxAttempts {
+			lockedUntil := now.Add(lu.lockoutDuration(attempt.Lockouts))
+			err = lu.loginAttemptRepository.Lock(ctx, key, primitive.NewDateTimeFromTime(lockedUntil))
+			if err != nil {
+				return status, err
+			}
+			status.Locked = true
+			if lockedUntil.After(status.LockedUntil) {
+				status.LockedUntil = lockedUntil
+			}
+			continue
+		}
+
+		if remaining := maxAttempts - attempt.Failures; remaining < status.RemainingAttempts {
+			status.RemainingAttempts = remaining
+		}
+	}
+
+	if status.Locked {
+		status.RemainingAttempts = 0
+	}
+
+	return status, nil
+}
+
+func (lu *loginAttemptUsecase) RecordSuccess(c context.Context, username string) error {
+	ctx, cancel := context.WithTimeout(c, lu.contextTimeout)
+	defer cancel()
+
+	// IP 计数不在成功时清除，避免用一个有效账号掩护对其它账号的尝试
+	return lu.loginAttemptRepository.DeleteByKey(ctx, attemptKey(domain.LoginAttemptScopeUsername, username))
+}
+
+func (lu *loginAttemptUsecase) UnlockUser(c context.Context, userID string) error {
+	ctx, cancel := context.WithTimeout(c, lu.contextTimeout)
+	defer cancel()
+
+	user, err := lu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// IP 维度的锁定不随账号解锁清除，否则解锁会同时解除攻击来源的限流
+	return lu.loginAttemptRepository.DeleteByKeys(ctx, userAttemptKeys(user))
+}
+
+func (lu *loginAttemptUsecase) keys(usernames []string, ip string) []string {
+	keys := []string{}
+	for _, username := range usernames {
+		keys = append(keys, attemptKey(domain.LoginAttemptScopeUsername, username))
+	}
+	if ip != "" {
+		keys = append(keys, attemptKey(domain.LoginAttemptScopeIP, ip))
+	}
+	return keys
+}
+
+// lockoutDuration 第 n 次锁定(从0开始)的时长：BaseLockout * 2^n，不超过 MaxLockout
+func (lu *loginAttemptUsecase) lockoutDuration(lockouts int) time.Duration {
+	duration := lu.policy.BaseLockout
+	for i := 0; i < lockouts && duration < lu.policy.MaxLockout; i++ {
+		duration *= 2
+	}
+	if duration > lu.policy.MaxLockout {
+		duration = lu.policy.MaxLockout
+	}
+	return duration
+}
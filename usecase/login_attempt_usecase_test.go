@@ -0,0 +1,141 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testIP = "203.0.113.10"
+
+var testLockoutPolicy = domain.LoginLockoutPolicy{
+	MaxAttempts:   5,
+	MaxIPAttempts: 20,
+	BaseLockout:   15 * time.Minute,
+	MaxLockout:    4 * time.Hour,
+	Window:        time.Hour,
+}
+
+func newLoginAttemptUsecase(t *testing.T) (domain.LoginAttemptUsecase, *mocks.LoginAttemptRepository, *mocks.UserRepository) {
+	attempts := mocks.NewLoginAttemptRepository(t)
+	users := mocks.NewUserRepository(t)
+	return usecase.NewLoginAttemptUsecase(attempts, users, testLockoutPolicy, time.Second), attempts, users
+}
+
+func TestRecordFailureBackoff(t *testing.T) {
+	tests := []struct {
+		lockouts int
+		want     time.Duration
+	}{
+		{0, 15 * time.Minute},
+		{1, 30 * time.Minute},
+		{2, time.Hour},
+		{3, 2 * time.Hour},
+		{4, 4 * time.Hour},
+		{5, 4 * time.Hour},
+		{10, 4 * time.Hour},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want.String(), func(t *testing.T) {
+			uc, attempts, _ := newLoginAttemptUsecase(t)
+			attempts.On("IncrementFailures", mock.Anything, "username:alice", mock.AnythingOfType("primitive.DateTime")).
+				Return(domain.LoginAttempt{Failures: 5, Lockouts: tt.lockouts}, nil).Once()
+			attempts.On("IncrementFailures", mock.Anything, "ip:"+testIP, mock.AnythingOfType("primitive.DateTime")).
+				Return(domain.LoginAttempt{Failures: 1}, nil).Once()
+
+			var lockedUntil primitive.DateTime
+			attempts.On("Lock", mock.Anything, "username:alice", mock.AnythingOfType("primitive.DateTime")).
+				Run(func(args mock.Arguments) { lockedUntil = args.Get(2).(primitive.DateTime) }).
+				Return(nil).Once()
+
+			status, err := uc.RecordFailure(context.Background(), []string{"alice"}, testIP)
+			require.NoError(t, err)
+			assert.True(t, status.Locked)
+			assert.Zero(t, status.RemainingAttempts)
+			assert.WithinDuration(t, time.Now().Add(tt.want), lockedUntil.Time(), time.Second)
+			assert.WithinDuration(t, lockedUntil.Time(), status.LockedUntil, time.Millisecond)
+		})
+	}
+}
+
+func TestRecordFailureRemainingAttempts(t *testing.T) {
+	uc, attempts, _ := newLoginAttemptUsecase(t)
+	attempts.On("IncrementFailures", mock.Anything, "username:alice", mock.Anything).
+		Return(domain.LoginAttempt{Failures: 3}, nil).Once()
+	attempts.On("IncrementFailures", mock.Anything, "username:bob", mock.Anything).
+		Return(domain.LoginAttempt{Failures: 1}, nil).Once()
+	attempts.On("IncrementFailures", mock.Anything, "ip:"+testIP, mock.Anything).
+		Return(domain.LoginAttempt{Failures: 12}, nil).Once()
+
+	// 剩余次数取所有计数中最少的
+	status, err := uc.RecordFailure(context.Background(), []string{"alice", "bob"}, testIP)
+	require.NoError(t, err)
+	assert.False(t, status.Locked)
+	assert.Equal(t, 2, status.RemainingAttempts)
+}
+
+func TestRecordFailureLocksIP(t *testing.T) {
+	uc, attempts, _ := newLoginAttemptUsecase(t)
+	attempts.On("IncrementFailures", mock.Anything, "username:alice", mock.Anything).
+		Return(domain.LoginAttempt{Failures: 1}, nil).Once()
+	attempts.On("IncrementFailures", mock.Anything, "ip:"+testIP, mock.Anything).
+		Return(domain.LoginAttempt{Failures: 20, Lockouts: 1}, nil).Once()
+	attempts.On("Lock", mock.Anything, "ip:"+testIP, mock.Anything).Return(nil).Once()
+
+	status, err := uc.RecordFailure(context.Background(), []string{"alice"}, testIP)
+	require.NoError(t, err)
+	assert.True(t, status.Locked)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), status.LockedUntil, time.Second)
+}
+
+func TestCheckLocked(t *testing.T) {
+	lockedUntil := primitive.NewDateTimeFromTime(time.Now().Add(10 * time.Minute))
+	expired := primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute))
+
+	t.Run("locked", func(t *testing.T) {
+		uc, attempts, _ := newLoginAttemptUsecase(t)
+		attempts.On("GetByKey", mock.Anything, "username:alice").Return(domain.LoginAttempt{LockedUntil: &lockedUntil}, nil).Once()
+		attempts.On("GetByKey", mock.Anything, "ip:"+testIP).Return(domain.LoginAttempt{}, nil).Once()
+
+		status, err := uc.CheckLocked(context.Background(), []string{"alice"}, testIP)
+		require.NoError(t, err)
+		assert.True(t, status.Locked)
+		assert.Zero(t, status.RemainingAttempts)
+		assert.WithinDuration(t, lockedUntil.Time(), status.LockedUntil, time.Millisecond)
+	})
+
+	t.Run("lock expired", func(t *testing.T) {
+		uc, attempts, _ := newLoginAttemptUsecase(t)
+		attempts.On("GetByKey", mock.Anything, "username:alice").Return(domain.LoginAttempt{LockedUntil: &expired}, nil).Once()
+		attempts.On("GetByKey", mock.Anything, "ip:"+testIP).Return(domain.LoginAttempt{}, nil).Once()
+
+		status, err := uc.CheckLocked(context.Background(), []string{"alice"}, testIP)
+		require.NoError(t, err)
+		assert.False(t, status.Locked)
+		assert.Equal(t, testLockoutPolicy.MaxAttempts, status.RemainingAttempts)
+	})
+}
+
+func TestUnlockUser(t *testing.T) {
+	uc, attempts, users := newLoginAttemptUsecase(t)
+	user := domain.User{ID: primitive.NewObjectID(), Username: "alice", Email: "alice@example.com", Phone: "13800000000"}
+	users.On("GetByID", mock.Anything, user.ID.Hex()).Return(user, nil).Once()
+
+	// 只删除用户名、邮箱、手机号的记录，IP 维度的锁定保持不变
+	attempts.On("DeleteByKeys", mock.Anything, []string{
+		"username:alice",
+		"username:alice@example.com",
+		"username:13800000000",
+	}).Return(nil).Once()
+
+	assert.NoError(t, uc.UnlockUser(context.Background(), user.ID.Hex()))
+}
@@ -6,30 +6,99 @@ import (
 
 	"github.com/zhengshui/flow-link-server/domain"
 	"github.com/zhengshui/flow-link-server/internal/tokenutil"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type loginUsecase struct {
 	userRepository domain.UserRepository
+	roleRepository domain.RoleRepository
 	contextTimeout time.Duration
 }
 
-func NewLoginUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.LoginUsecase {
+func NewLoginUsecase(userRepository domain.UserRepository, roleRepository domain.RoleRepository, timeout time.Duration) domain.LoginUsecase {
 	return &loginUsecase{
 		userRepository: userRepository,
+		roleRepository: roleRepository,
 		contextTimeout: timeout,
 	}
 }
 
-func (lu *loginUsecase) GetUserByUsername(c context.Context, username string) (domain.User, error) {
+func (lu *loginUsecase) ResolveUser(c context.Context, identifier string) ([]domain.LoginCandidate, error) {
 	ctx, cancel := context.WithTimeout(c, lu.contextTimeout)
 	defer cancel()
-	return lu.userRepository.GetByUsername(ctx, username)
+
+	if identifier == "" {
+		return nil, domain.ErrUserNotFound
+	}
+
+	// 一个标识最多命中用户名、邮箱、手机号各一个账号
+	users, err := lu.userRepository.FindByIdentifier(ctx, identifier, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	var candidates []domain.LoginCandidate
+	for _, user := range users {
+		matchedBy := matchedIdentifier(&user, identifier)
+		// 用户名是主标识，精确匹配时不受其他账号邮箱/手机号的干扰
+		if matchedBy == domain.LoginIdentifierUsername {
+			return []domain.LoginCandidate{{User: user, MatchedBy: matchedBy}}, nil
+		}
+		candidates = append(candidates, domain.LoginCandidate{User: user, MatchedBy: matchedBy})
+	}
+
+	if len(candidates) == 0 {
+		return nil, domain.ErrUserNotFound
+	}
+	return candidates, nil
 }
 
-func (lu *loginUsecase) CreateAccessToken(user *domain.User, secret string, expiry int) (accessToken string, err error) {
-	return tokenutil.CreateAccessToken(user, secret, expiry)
+func (lu *loginUsecase) Authenticate(candidates []domain.LoginCandidate, password string) (domain.LoginCandidate, error) {
+	// 先校验密码再判断是否有歧义，避免向他人暴露哪些邮箱/手机号对应多个账号
+	var matched []domain.LoginCandidate
+	for _, candidate := range candidates {
+		if bcrypt.CompareHashAndPassword([]byte(candidate.User.Password), []byte(password)) == nil {
+			matched = append(matched, candidate)
+		}
+	}
+	if len(matched) == 0 {
+		return domain.LoginCandidate{}, domain.ErrPasswordIncorrect
+	}
+	if len(matched) > 1 {
+		return domain.LoginCandidate{}, domain.ErrLoginIdentifierAmbiguous
+	}
+
+	// 密码正确后才提示邮箱未验证，避免向他人暴露邮箱的验证状态
+	if err := matched[0].CheckVerified(); err != nil {
+		return domain.LoginCandidate{}, err
+	}
+	return matched[0], nil
+}
+
+// matchedIdentifier 判断登录标识命中了用户的哪个字段
+func matchedIdentifier(user *domain.User, identifier string) string {
+	switch {
+	case user.Username == identifier:
+		return domain.LoginIdentifierUsername
+	case user.Email != "" && user.Email == domain.NormalizeEmail(identifier):
+		return domain.LoginIdentifierEmail
+	default:
+		return domain.LoginIdentifierPhone
+	}
+}
+
+func (lu *loginUsecase) CreateAccessToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (accessToken string, err error) {
+	ctx, cancel := context.WithTimeout(c, lu.contextTimeout)
+	defer cancel()
+
+	permissions, err := permissionsForUser(ctx, lu.roleRepository, user)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenutil.CreateAccessToken(user, sessionID, permissions, keys, expiry)
 }
 
-func (lu *loginUsecase) CreateRefreshToken(user *domain.User, secret string, expiry int) (refreshToken string, err error) {
-	return tokenutil.CreateRefreshToken(user, secret, expiry)
+func (lu *loginUsecase) CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (refreshToken string, err error) {
+	return tokenutil.CreateRefreshToken(user, keys, expiry)
 }
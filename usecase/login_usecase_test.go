@@ -0,0 +1,171 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestResolveUser(t *testing.T) {
+	alice := domain.User{ID: primitive.NewObjectID(), Username: "alice", Email: "alice@example.com", Phone: "13800000000"}
+	// bob 的用户名恰好是 alice 的手机号
+	bob := domain.User{ID: primitive.NewObjectID(), Username: "13800000000", Email: "bob@example.com"}
+	carol := domain.User{ID: primitive.NewObjectID(), Username: "carol", Phone: "alice@example.com"}
+
+	tests := []struct {
+		name       string
+		identifier string
+		found      []domain.User
+		want       []domain.LoginCandidate
+	}{
+		{
+			name:       "username",
+			identifier: "alice",
+			found:      []domain.User{alice},
+			want:       []domain.LoginCandidate{{User: alice, MatchedBy: domain.LoginIdentifierUsername}},
+		},
+		{
+			name:       "username wins over another account's phone",
+			identifier: "13800000000",
+			found:      []domain.User{alice, bob},
+			want:       []domain.LoginCandidate{{User: bob, MatchedBy: domain.LoginIdentifierUsername}},
+		},
+		{
+			name:       "email is normalized",
+			identifier: " Alice@Example.com",
+			found:      []domain.User{alice},
+			want:       []domain.LoginCandidate{{User: alice, MatchedBy: domain.LoginIdentifierEmail}},
+		},
+		{
+			name:       "phone",
+			identifier: "13800000000",
+			found:      []domain.User{alice},
+			want:       []domain.LoginCandidate{{User: alice, MatchedBy: domain.LoginIdentifierPhone}},
+		},
+		{
+			name:       "email and phone of different accounts",
+			identifier: "alice@example.com",
+			found:      []domain.User{alice, carol},
+			want: []domain.LoginCandidate{
+				{User: alice, MatchedBy: domain.LoginIdentifierEmail},
+				{User: carol, MatchedBy: domain.LoginIdentifierPhone},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			users := mocks.NewUserRepository(t)
+			users.On("FindByIdentifier", mock.Anything, tt.identifier, 3).Return(tt.found, nil).Once()
+
+			candidates, err := usecase.NewLoginUsecase(users, nil, time.Second).ResolveUser(context.Background(), tt.identifier)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, candidates)
+		})
+	}
+}
+
+func TestResolveUserNotFound(t *testing.T) {
+	users := mocks.NewUserRepository(t)
+	users.On("FindByIdentifier", mock.Anything, "nobody", 3).Return([]domain.User{}, nil).Once()
+	lu := usecase.NewLoginUsecase(users, nil, time.Second)
+
+	_, err := lu.ResolveUser(context.Background(), "nobody")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+
+	// 空标识不查询数据库
+	_, err = lu.ResolveUser(context.Background(), "")
+	assert.ErrorIs(t, err, domain.ErrUserNotFound)
+}
+
+func TestLoginCandidateCheckVerified(t *testing.T) {
+	unverified := domain.User{Email: "alice@example.com"}
+	verified := domain.User{Email: "alice@example.com", EmailVerified: true}
+
+	assert.ErrorIs(t, domain.LoginCandidate{User: unverified, MatchedBy: domain.LoginIdentifierEmail}.CheckVerified(), domain.ErrLoginIdentifierUnverified)
+	assert.NoError(t, domain.LoginCandidate{User: verified, MatchedBy: domain.LoginIdentifierEmail}.CheckVerified())
+	assert.NoError(t, domain.LoginCandidate{User: unverified, MatchedBy: domain.LoginIdentifierUsername}.CheckVerified())
+	assert.NoError(t, domain.LoginCandidate{User: unverified, MatchedBy: domain.LoginIdentifierPhone}.CheckVerified())
+}
+
+func TestAuthenticate(t *testing.T) {
+	hash := func(password string) string {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+		require.NoError(t, err)
+		return string(hashed)
+	}
+	alice := domain.User{Username: "alice", Email: "shared@example.com", EmailVerified: true, Password: hash("alice-password")}
+	bob := domain.User{Username: "bob", Phone: "shared@example.com", Password: hash("bob-password")}
+	carol := domain.User{Username: "carol", Phone: "shared@example.com", Password: hash("alice-password")}
+	unverified := domain.User{Username: "dave", Email: "dave@example.com", Password: hash("dave-password")}
+
+	tests := []struct {
+		name       string
+		candidates []domain.LoginCandidate
+		password   string
+		want       string
+		wantErr    error
+	}{
+		{
+			name:       "password picks the account",
+			candidates: []domain.LoginCandidate{{User: alice, MatchedBy: domain.LoginIdentifierEmail}, {User: bob, MatchedBy: domain.LoginIdentifierPhone}},
+			password:   "bob-password",
+			want:       "bob",
+		},
+		{
+			name:       "wrong password",
+			candidates: []domain.LoginCandidate{{User: alice, MatchedBy: domain.LoginIdentifierEmail}, {User: bob, MatchedBy: domain.LoginIdentifierPhone}},
+			password:   "wrong",
+			wantErr:    domain.ErrPasswordIncorrect,
+		},
+		{
+			name:       "several accounts share the password",
+			candidates: []domain.LoginCandidate{{User: alice, MatchedBy: domain.LoginIdentifierEmail}, {User: carol, MatchedBy: domain.LoginIdentifierPhone}},
+			password:   "alice-password",
+			wantErr:    domain.ErrLoginIdentifierAmbiguous,
+		},
+		{
+			name:       "unverified email",
+			candidates: []domain.LoginCandidate{{User: unverified, MatchedBy: domain.LoginIdentifierEmail}},
+			password:   "dave-password",
+			wantErr:    domain.ErrLoginIdentifierUnverified,
+		},
+		{
+			name:       "unverified email with wrong password",
+			candidates: []domain.LoginCandidate{{User: unverified, MatchedBy: domain.LoginIdentifierEmail}},
+			password:   "wrong",
+			wantErr:    domain.ErrPasswordIncorrect,
+		},
+	}
+
+	lu := usecase.NewLoginUsecase(nil, nil, time.Second)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			candidate, err := lu.Authenticate(tt.candidates, tt.password)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, candidate.User.Username)
+		})
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	for _, username := range []string{"alice", "alice_01", "1234abc"} {
+		assert.NoError(t, domain.ValidateUsername(username), username)
+	}
+	for _, username := range []string{"13800000000", "+8613800000000", "138-0000-0000", "alice@example.com"} {
+		assert.ErrorIs(t, domain.ValidateUsername(username), domain.ErrUsernameInvalid, username)
+	}
+}
@@ -0,0 +1,163 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type passwordUsecase struct {
+	userRepository          domain.UserRepository
+	passwordResetRepository domain.PasswordResetRepository
+	refreshTokenRepository  domain.RefreshTokenRepository
+	sessionRepository       domain.SessionRepository
+	notifier                domain.Notifier
+	contextTimeout          time.Duration
+}
+
+func NewPasswordUsecase(
+	userRepository domain.UserRepository,
+	passwordResetRepository domain.PasswordResetRepository,
+	refreshTokenRepository domain.RefreshTokenRepository,
+	sessionRepository domain.SessionRepository,
+	notifier domain.Notifier,
+	timeout time.Duration,
+) domain.PasswordUsecase {
+	return &passwordUsecase{
+		userRepository:          userRepository,
+		passwordResetRepository: passwordResetRepository,
+		refreshTokenRepository:  refreshTokenRepository,
+		sessionRepository:       sessionRepository,
+		notifier:                notifier,
+		contextTimeout:          timeout,
+	}
+}
+
+func (pu *passwordUsecase) ChangePassword(c context.Context, userID string, request *domain.ChangePasswordRequest) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(request.OldPassword)) != nil {
+		return domain.ErrPasswordIncorrect
+	}
+
+	return pu.updatePassword(ctx, userID, request.NewPassword)
+}
+
+func (pu *passwordUsecase) RequestPasswordReset(c context.Context, account string, expiryMinute int) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByUsername(ctx, account)
+	if err != nil {
+		user, err = pu.userRepository.GetByEmail(ctx, domain.NormalizeEmail(account))
+		if err != nil {
+			// Don't reveal whether the account exists
+			return nil
+		}
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return err
+	}
+
+	// 同一用户只保留最新的一个重置令牌
+	err = pu.passwordResetRepository.InvalidateByUserID(ctx, user.ID.Hex())
+	if err != nil {
+		return err
+	}
+
+	reset := domain.PasswordReset{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		TokenHash: hashResetToken(token),
+		ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(time.Minute * time.Duration(expiryMinute))),
+	}
+	err = pu.passwordResetRepository.Create(ctx, &reset)
+	if err != nil {
+		return err
+	}
+
+	to := user.Email
+	if to == "" {
+		to = user.Username
+	}
+
+	return pu.notifier.Send(ctx, domain.Notification{
+		To:      to,
+		Subject: "重置密码",
+		Body:    fmt.Sprintf("您的密码重置令牌为：%s\n%d 分钟内有效，仅可使用一次。如非本人操作请忽略。", token, expiryMinute),
+	})
+}
+
+func (pu *passwordUsecase) ResetPassword(c context.Context, request *domain.ResetPasswordRequest) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	reset, err := pu.passwordResetRepository.GetByTokenHash(ctx, hashResetToken(request.Token))
+	if err != nil {
+		return domain.ErrPasswordResetTokenInvalid
+	}
+
+	if reset.UsedAt != nil || reset.ExpiresAt.Time().Before(time.Now()) {
+		return domain.ErrPasswordResetTokenInvalid
+	}
+
+	consumed, err := pu.passwordResetRepository.MarkUsed(ctx, reset.ID.Hex())
+	if err != nil {
+		return err
+	}
+	if !consumed {
+		return domain.ErrPasswordResetTokenInvalid
+	}
+
+	userID := reset.UserID.Hex()
+	err = pu.updatePassword(ctx, userID, request.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	// 密码被重置说明旧凭据可能已泄露，吊销所有设备上的登录
+	err = pu.refreshTokenRepository.RevokeByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return pu.sessionRepository.RevokeByUserID(ctx, userID)
+}
+
+func (pu *passwordUsecase) updatePassword(ctx context.Context, userID string, password string) error {
+	encryptedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	return pu.userRepository.UpdatePassword(ctx, userID, string(encryptedPassword))
+}
+
+// generateResetToken 生成随机重置令牌，明文只发给用户，服务端只保存摘要
+func generateResetToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,147 @@
+package usecase_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type passwordFixture struct {
+	user     domain.User
+	users    *mocks.UserRepository
+	resets   *mocks.PasswordResetRepository
+	tokens   *mocks.RefreshTokenRepository
+	sessions *mocks.SessionRepository
+	notifier *mocks.Notifier
+	usecase  domain.PasswordUsecase
+}
+
+func newPasswordFixture(t *testing.T) *passwordFixture {
+	f := &passwordFixture{
+		user:     domain.User{ID: primitive.NewObjectID(), Username: "alice", Email: "alice@example.com"},
+		users:    mocks.NewUserRepository(t),
+		resets:   mocks.NewPasswordResetRepository(t),
+		tokens:   mocks.NewRefreshTokenRepository(t),
+		sessions: mocks.NewSessionRepository(t),
+		notifier: mocks.NewNotifier(t),
+	}
+	f.usecase = usecase.NewPasswordUsecase(f.users, f.resets, f.tokens, f.sessions, f.notifier, time.Second)
+	return f
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRequestPasswordReset(t *testing.T) {
+	f := newPasswordFixture(t)
+	f.users.On("GetByUsername", mock.Anything, "alice").Return(f.user, nil).Once()
+	f.resets.On("InvalidateByUserID", mock.Anything, f.user.ID.Hex()).Return(nil).Once()
+
+	var stored *domain.PasswordReset
+	f.resets.On("Create", mock.Anything, mock.AnythingOfType("*domain.PasswordReset")).
+		Run(func(args mock.Arguments) { stored = args.Get(1).(*domain.PasswordReset) }).
+		Return(nil).Once()
+
+	var sent domain.Notification
+	f.notifier.On("Send", mock.Anything, mock.AnythingOfType("domain.Notification")).
+		Run(func(args mock.Arguments) { sent = args.Get(1).(domain.Notification) }).
+		Return(nil).Once()
+
+	require.NoError(t, f.usecase.RequestPasswordReset(context.Background(), "alice", 30))
+	assert.Equal(t, f.user.Email, sent.To)
+
+	// 明文令牌只出现在通知中，服务端只保存摘要
+	token := regexp.MustCompile(`[0-9a-f]{64}`).FindString(sent.Body)
+	require.NotEmpty(t, token)
+	require.NotNil(t, stored)
+	assert.Equal(t, f.user.ID, stored.UserID)
+	assert.Equal(t, hashToken(token), stored.TokenHash)
+	assert.WithinDuration(t, time.Now().Add(30*time.Minute), stored.ExpiresAt.Time(), time.Minute)
+}
+
+func TestRequestPasswordResetUnknownAccount(t *testing.T) {
+	f := newPasswordFixture(t)
+	f.users.On("GetByUsername", mock.Anything, "nobody@example.com").Return(domain.User{}, domain.ErrUserNotFound).Once()
+	f.users.On("GetByEmail", mock.Anything, "nobody@example.com").Return(domain.User{}, domain.ErrUserNotFound).Once()
+
+	// 账号不存在时同样返回成功，不泄露账号是否存在
+	assert.NoError(t, f.usecase.RequestPasswordReset(context.Background(), "nobody@example.com", 30))
+}
+
+func TestResetPassword(t *testing.T) {
+	const token = "reset-token"
+	request := &domain.ResetPasswordRequest{Token: token, NewPassword: "new-password"}
+
+	validReset := func(f *passwordFixture) domain.PasswordReset {
+		return domain.PasswordReset{
+			ID:        primitive.NewObjectID(),
+			UserID:    f.user.ID,
+			TokenHash: hashToken(token),
+			ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(time.Hour)),
+		}
+	}
+
+	t.Run("success revokes all sessions", func(t *testing.T) {
+		f := newPasswordFixture(t)
+		reset := validReset(f)
+		userID := f.user.ID.Hex()
+		f.resets.On("GetByTokenHash", mock.Anything, hashToken(token)).Return(reset, nil).Once()
+		f.resets.On("MarkUsed", mock.Anything, reset.ID.Hex()).Return(true, nil).Once()
+		f.users.On("UpdatePassword", mock.Anything, userID, mock.MatchedBy(func(hashed string) bool {
+			return bcrypt.CompareHashAndPassword([]byte(hashed), []byte(request.NewPassword)) == nil
+		})).Return(nil).Once()
+		f.tokens.On("RevokeByUserID", mock.Anything, userID).Return(nil).Once()
+		f.sessions.On("RevokeByUserID", mock.Anything, userID).Return(nil).Once()
+
+		assert.NoError(t, f.usecase.ResetPassword(context.Background(), request))
+	})
+
+	t.Run("used token", func(t *testing.T) {
+		f := newPasswordFixture(t)
+		reset := validReset(f)
+		usedAt := primitive.NewDateTimeFromTime(time.Now())
+		reset.UsedAt = &usedAt
+		f.resets.On("GetByTokenHash", mock.Anything, hashToken(token)).Return(reset, nil).Once()
+
+		assert.ErrorIs(t, f.usecase.ResetPassword(context.Background(), request), domain.ErrPasswordResetTokenInvalid)
+	})
+
+	t.Run("token consumed concurrently", func(t *testing.T) {
+		f := newPasswordFixture(t)
+		reset := validReset(f)
+		f.resets.On("GetByTokenHash", mock.Anything, hashToken(token)).Return(reset, nil).Once()
+		f.resets.On("MarkUsed", mock.Anything, reset.ID.Hex()).Return(false, nil).Once()
+
+		assert.ErrorIs(t, f.usecase.ResetPassword(context.Background(), request), domain.ErrPasswordResetTokenInvalid)
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		f := newPasswordFixture(t)
+		reset := validReset(f)
+		reset.ExpiresAt = primitive.NewDateTimeFromTime(time.Now().Add(-time.Minute))
+		f.resets.On("GetByTokenHash", mock.Anything, hashToken(token)).Return(reset, nil).Once()
+
+		assert.ErrorIs(t, f.usecase.ResetPassword(context.Background(), request), domain.ErrPasswordResetTokenInvalid)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		f := newPasswordFixture(t)
+		f.resets.On("GetByTokenHash", mock.Anything, hashToken(token)).Return(domain.PasswordReset{}, assert.AnError).Once()
+
+		assert.ErrorIs(t, f.usecase.ResetPassword(context.Background(), request), domain.ErrPasswordResetTokenInvalid)
+	})
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+)
+
+// planDayLinker 按训练记录维护计划日的完成状态和计划累计数据：
+// 记录关联计划日时该日标记为完成，计划日不再有关联记录时取消完成
+type planDayLinker struct {
+	fitnessPlanRepository    domain.FitnessPlanRepository
+	trainingRecordRepository domain.TrainingRecordRepository
+}
+
+// validate 校验记录新关联的计划属于该用户且计划日在计划范围内，关联没有变化时不校验
+func (pl planDayLinker) validate(ctx context.Context, userID string, before, after *domain.TrainingRecord) error {
+	if after == nil || after.PlanID == "" || samePlanLink(before, after) {
+		return nil
+	}
+
+	plan, err := pl.fitnessPlanRepository.GetByID(ctx, after.PlanID)
+	if err != nil {
+		return err
+	}
+	if plan.UserID.Hex() != userID {
+		return domain.ErrFitnessPlanNotFound
+	}
+	if after.PlanDayID != nil && !validPlanDay(plan, *after.PlanDayID) {
+		return domain.ErrInvalidPlanDay
+	}
+	return nil
+}
+
+// sync 在记录保存或删除之后、同一事务中调用，before/after 为变更前后的记录，不存在时为 nil
+func (pl planDayLinker) sync(ctx context.Context, userID string, before, after *domain.TrainingRecord) error {
+	planIDs := []string{}
+	for _, record := range []*domain.TrainingRecord{before, after} {
+		if record != nil && record.PlanID != "" && !slices.Contains(planIDs, record.PlanID) {
+			planIDs = append(planIDs, record.PlanID)
+		}
+	}
+
+	for _, planID := range planIDs {
+		_, err := pl.fitnessPlanRepository.GetByID(ctx, planID)
+		if errors.Is(err, domain.ErrFitnessPlanNotFound) {
+			// 计划已删除，记录上保留的关联不再处理
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		newDay, linked := planDay(after, planID)
+		if linked {
+			if err := pl.fitnessPlanRepository.CompletePlanDay(ctx, planID, newDay); err != nil {
+				return err
+			}
+		}
+		if oldDay, ok := planDay(before, planID); ok && (!linked || oldDay != newDay) {
+			if err := pl.releaseDay(ctx, planID, oldDay); err != nil {
+				return err
+			}
+		}
+
+		if err := pl.refreshTotals(ctx, userID, planID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// releaseDay 计划日已没有关联的训练记录时取消完成
+func (pl planDayLinker) releaseDay(ctx context.Context, planID string, dayNumber int) error {
+	remaining, err := pl.trainingRecordRepository.CountByPlanDay(ctx, planID, dayNumber)
+	if err != nil || remaining > 0 {
+		return err
+	}
+	return pl.fitnessPlanRepository.UncompletePlanDay(ctx, planID, dayNumber)
+}
+
+// refreshTotals 按计划关联的全部训练记录重新汇总累计数据
+func (pl planDayLinker) refreshTotals(ctx context.Context, userID, planID string) error {
+	totals, err := pl.trainingRecordRepository.SumByPlan(ctx, userID, planID, time.Time{}, time.Time{})
+	if err != nil {
+		return err
+	}
+	return pl.fitnessPlanRepository.SetTotals(ctx, planID, totals)
+}
+
+// planDay 记录关联到 planID 的计划日
+func planDay(record *domain.TrainingRecord, planID string) (int, bool) {
+	if record == nil || record.PlanID != planID || record.PlanDayID == nil {
+		return 0, false
+	}
+	return *record.PlanDayID, true
+}
+
+func samePlanLink(before, after *domain.TrainingRecord) bool {
+	if before == nil || before.PlanID != after.PlanID {
+		return false
+	}
+	oldDay, oldLinked := planDay(before, before.PlanID)
+	newDay, newLinked := planDay(after, after.PlanID)
+	return oldLinked == newLinked && oldDay == newDay
+}
+
+// validPlanDay 计划日按计划开始后的第几天计，范围为整个计划周期（含顺延的天数）
+func validPlanDay(plan domain.FitnessPlan, dayNumber int) bool {
+	return dayNumber >= 1 && dayNumber <= scheduleutil.TotalDays(plan)
+}
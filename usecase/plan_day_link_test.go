@@ -0,0 +1,123 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTrainingRecordLinksPlanDay(t *testing.T) {
+	db := testDatabase(t)
+	ctx := context.Background()
+	timeout := 10 * time.Second
+
+	tr := repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord)
+	fp := repository.NewFitnessPlanRepository(db, domain.CollectionFitnessPlan)
+	pr := repository.NewPersonalRecordRepository(db, domain.CollectionPersonalRecord)
+	rr := repository.NewTrainingRollupRepository(db, domain.CollectionTrainingRollup)
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+	er := repository.NewExerciseCatalogRepository(db, domain.CollectionExerciseCatalog)
+	transactor := repository.NewTransactor(db, true)
+
+	userID := primitive.NewObjectID()
+	t.Cleanup(func() {
+		for _, collection := range []string{domain.CollectionTrainingRecord, domain.CollectionFitnessPlan, domain.CollectionPersonalRecord, domain.CollectionTrainingRollup} {
+			db.Collection(collection).DeleteMany(ctx, bson.M{"userId": userID})
+		}
+		db.Collection(domain.CollectionUser).DeleteOne(ctx, bson.M{"_id": userID})
+	})
+
+	require.NoError(t, ur.Create(ctx, &domain.User{
+		ID:       userID,
+		Username: "plan_link_" + userID.Hex(),
+		TimeZone: testLocation.String(),
+	}))
+	plan := domain.FitnessPlan{
+		ID:                  primitive.NewObjectID(),
+		UserID:              userID,
+		Name:                "关联测试计划",
+		DurationWeeks:       2,
+		TrainingDaysPerWeek: 3,
+		StartDate:           time.Now().Format("2006-01-02"),
+		Status:              "进行中",
+		CompletedDays:       []int{},
+		SkippedDays:         []int{2},
+	}
+	require.NoError(t, fp.Create(ctx, &plan))
+
+	rollups := usecase.NewTrainingRollupUsecase(tr, rr, ur, timeout)
+	records := usecase.NewTrainingRecordUsecase(tr, pr, fp, ur, rollups, usecase.NewExerciseCatalogUsecase(er, timeout), transactor, domain.OneRMFormulaEpley, timeout)
+	plans := usecase.NewFitnessPlanUsecase(fp, nil, tr, pr, ur, usecase.NewExerciseCatalogUsecase(er, timeout), transactor, timeout)
+
+	planID := plan.ID.Hex()
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+	create := func(day int, duration int, weight float64) string {
+		result, err := records.Create(ctx, userID.Hex(), &domain.CreateTrainingRecordRequest{
+			Title:          "计划训练",
+			Duration:       intPtr(duration),
+			TotalWeight:    floatPtr(weight),
+			CaloriesBurned: intPtr(duration * 5),
+			PlanID:         &planID,
+			PlanDayID:      intPtr(day),
+		})
+		require.NoError(t, err)
+		return result["id"].(string)
+	}
+	assertPlan := func(completedDays []int, duration int, weight float64) {
+		t.Helper()
+		current, err := fp.GetByID(ctx, planID)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, completedDays, current.CompletedDays)
+		assert.Equal(t, len(completedDays), current.TotalCompletedDays)
+		assert.Equal(t, duration, current.TotalDuration)
+		assert.Equal(t, duration*5, current.TotalCalories)
+		assert.InDelta(t, weight, current.TotalWeight, 0.001)
+	}
+
+	first := create(1, 40, 1000)
+	second := create(1, 20, 500)
+	third := create(2, 30, 800)
+	assertPlan([]int{1, 2}, 90, 2300)
+
+	current, err := fp.GetByID(ctx, planID)
+	require.NoError(t, err)
+	assert.Empty(t, current.SkippedDays, "完成的天不再算作跳过")
+
+	// 计划日还有其他关联记录时保持完成
+	require.NoError(t, records.Delete(ctx, userID.Hex(), first))
+	assertPlan([]int{1, 2}, 50, 1300)
+
+	// 记录换到别的计划日，旧计划日不再有关联记录
+	_, err = records.Update(ctx, userID.Hex(), second, &domain.UpdateTrainingRecordRequest{PlanDayID: intPtr(3)})
+	require.NoError(t, err)
+	assertPlan([]int{2, 3}, 50, 1300)
+
+	require.NoError(t, records.Delete(ctx, userID.Hex(), third))
+	assertPlan([]int{3}, 20, 500)
+
+	// 不属于计划范围的计划日
+	_, err = records.Update(ctx, userID.Hex(), second, &domain.UpdateTrainingRecordRequest{PlanDayID: intPtr(15)})
+	assert.ErrorIs(t, err, domain.ErrInvalidPlanDay)
+
+	// 通过完成训练日接口关联没有计划的记录
+	result, err := records.Create(ctx, userID.Hex(), &domain.CreateTrainingRecordRequest{Title: "自由训练", Duration: intPtr(10), CaloriesBurned: intPtr(50)})
+	require.NoError(t, err)
+	_, err = plans.CompleteDay(ctx, userID.Hex(), planID, 5, result["id"].(string))
+	require.NoError(t, err)
+	assertPlan([]int{3, 5}, 30, 500)
+
+	record, err := tr.GetByID(ctx, result["id"].(string))
+	require.NoError(t, err)
+	assert.Equal(t, planID, record.PlanID)
+	require.NotNil(t, record.PlanDayID)
+	assert.Equal(t, 5, *record.PlanDayID)
+}
@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+)
+
+// validatePhases 校验计划阶段按开始周排序后从第 1 周起连续覆盖 durationWeeks 周，且每个阶段每周有 1-7 个训练日
+func validatePhases(phases []domain.PlanPhase, durationWeeks int) error {
+	if len(phases) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(phases, func(i, j int) bool {
+		return phases[i].StartWeek < phases[j].StartWeek
+	})
+
+	next := 1
+	for _, phase := range phases {
+		sessions := phase.TrainingSessions()
+		if phase.StartWeek != next || phase.EndWeek < phase.StartWeek || sessions == 0 || sessions > 7 {
+			return domain.ErrInvalidPlanPhases
+		}
+		next = phase.EndWeek + 1
+	}
+	if next != durationWeeks+1 {
+		return domain.ErrInvalidPlanPhases
+	}
+	return nil
+}
+
+// resolvePhases 校验计划阶段并关联各阶段训练日中的动作
+func resolvePhases(c context.Context, resolver domain.ExerciseResolver, phases []domain.PlanPhase, durationWeeks int) error {
+	if err := validatePhases(phases, durationWeeks); err != nil {
+		return err
+	}
+	for i := range phases {
+		if err := resolveTrainingDays(c, resolver, phases[i].TrainingDays); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// phaseProgress 按已完成训练日所属的计划周统计各阶段的完成情况，计划没有阶段时返回 nil
+func phaseProgress(plan domain.FitnessPlan, schedule scheduleutil.Schedule) []domain.PhaseProgress {
+	if len(plan.Phases) == 0 {
+		return nil
+	}
+
+	progress := make([]domain.PhaseProgress, len(plan.Phases))
+	for i, phase := range plan.Phases {
+		progress[i] = domain.PhaseProgress{
+			Name:          phase.Name,
+			StartWeek:     phase.StartWeek,
+			EndWeek:       phase.EndWeek,
+			Deload:        phase.Deload,
+			TotalSessions: (phase.EndWeek - phase.StartWeek + 1) * phase.TrainingSessions(),
+		}
+	}
+	for _, day := range plan.CompletedDays {
+		week := schedule.Week(day)
+		for i := range progress {
+			if week >= progress[i].StartWeek && week <= progress[i].EndWeek {
+				progress[i].CompletedSessions++
+				break
+			}
+		}
+	}
+	return progress
+}
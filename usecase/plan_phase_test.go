@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+)
+
+func phaseDays(names ...string) []domain.TrainingDay {
+	days := []domain.TrainingDay{}
+	for i, name := range names {
+		days = append(days, domain.TrainingDay{DayNumber: i + 1, DayName: name, IsRestDay: name == "休息"})
+	}
+	return days
+}
+
+// phasedPlan 2025-11-05（周三）开始的四周计划：前两周增肌期每周 3 练，第 3 周力量期每周 2 练，第 4 周减载
+func phasedPlan() domain.FitnessPlan {
+	return domain.FitnessPlan{
+		DurationWeeks:    4,
+		TrainingWeekdays: []int{1, 3, 5},
+		StartDate:        "2025-11-05",
+		Phases: []domain.PlanPhase{
+			{Name: "增肌期", StartWeek: 1, EndWeek: 2, TrainingDays: phaseDays("A", "休息", "B", "C")},
+			{Name: "力量期", StartWeek: 3, EndWeek: 3, TrainingDays: phaseDays("D", "E")},
+			{Name: "减载周", StartWeek: 4, EndWeek: 4, Deload: true, TrainingDays: phaseDays("F")},
+		},
+	}
+}
+
+func TestValidatePhases(t *testing.T) {
+	tests := []struct {
+		name   string
+		phases []domain.PlanPhase
+		weeks  int
+		valid  bool
+	}{
+		{"no phases", nil, 4, true},
+		{"contiguous", phasedPlan().Phases, 4, true},
+		{"unsorted", []domain.PlanPhase{
+			{StartWeek: 3, EndWeek: 4, TrainingDays: phaseDays("B")},
+			{StartWeek: 1, EndWeek: 2, TrainingDays: phaseDays("A")},
+		}, 4, true},
+		{"single week deload", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 1, Deload: true, TrainingDays: phaseDays("A")},
+		}, 1, true},
+		{"not starting at week 1", []domain.PlanPhase{
+			{StartWeek: 2, EndWeek: 4, TrainingDays: phaseDays("A")},
+		}, 4, false},
+		{"gap", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 1, TrainingDays: phaseDays("A")},
+			{StartWeek: 3, EndWeek: 4, TrainingDays: phaseDays("B")},
+		}, 4, false},
+		{"overlap", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 2, TrainingDays: phaseDays("A")},
+			{StartWeek: 2, EndWeek: 4, TrainingDays: phaseDays("B")},
+		}, 4, false},
+		{"shorter than the plan", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 3, TrainingDays: phaseDays("A")},
+		}, 4, false},
+		{"longer than the plan", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 5, TrainingDays: phaseDays("A")},
+		}, 4, false},
+		{"end before start", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 0, TrainingDays: phaseDays("A")},
+		}, 0, false},
+		{"rest days only", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 4, TrainingDays: phaseDays("休息")},
+		}, 4, false},
+		{"more than 7 sessions a week", []domain.PlanPhase{
+			{StartWeek: 1, EndWeek: 4, TrainingDays: phaseDays("A", "B", "C", "D", "E", "F", "G", "H")},
+		}, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validatePhases(tt.phases, tt.weeks)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, domain.ErrInvalidPlanPhases)
+			}
+		})
+	}
+}
+
+func TestPhaseAt(t *testing.T) {
+	plan := phasedPlan()
+	for week, want := range map[int]string{1: "增肌期", 2: "增肌期", 3: "力量期", 4: "减载周"} {
+		phase := plan.PhaseAt(week)
+		require.NotNil(t, phase, week)
+		assert.Equal(t, want, phase.Name, week)
+	}
+	assert.Nil(t, plan.PhaseAt(0))
+	assert.Nil(t, plan.PhaseAt(5))
+	assert.Nil(t, domain.FitnessPlan{DurationWeeks: 4}.PhaseAt(1))
+
+	// 2 周 × 3 练 + 2 练 + 1 练，休息日不计
+	assert.Equal(t, 9, plan.TotalSessions())
+	assert.Equal(t, 12, domain.FitnessPlan{DurationWeeks: 4, TrainingDaysPerWeek: 3}.TotalSessions())
+}
+
+func TestPhaseProgress(t *testing.T) {
+	plan := phasedPlan()
+	// 第 1 周训练在第 1、3、6 天，第 2 周在第 8、10、13 天，第 3 周在第 15、17 天，第 4 周减载在第 22 天；
+	// 减载训练顺延到第 24 天后完成，仍计入减载周
+	plan.Reschedules = []domain.PlanReschedule{{Action: domain.RescheduleShift, DayNumber: 22, Days: 2}}
+	plan.CompletedDays = []int{1, 3, 6, 13, 15, 24}
+
+	schedule, err := scheduleutil.New(plan, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.PhaseProgress{
+		{Name: "增肌期", StartWeek: 1, EndWeek: 2, TotalSessions: 6, CompletedSessions: 4},
+		{Name: "力量期", StartWeek: 3, EndWeek: 3, TotalSessions: 2, CompletedSessions: 1},
+		{Name: "减载周", StartWeek: 4, EndWeek: 4, Deload: true, TotalSessions: 1, CompletedSessions: 1},
+	}, phaseProgress(plan, schedule))
+
+	assert.Nil(t, phaseProgress(domain.FitnessPlan{StartDate: plan.StartDate}, schedule))
+}
+
+func TestWeekQualifiedPlanDay(t *testing.T) {
+	tests := []struct {
+		name      string
+		week      int
+		dayNumber int
+		want      int
+	}{
+		{"plain plan day", 0, 9, 9},
+		{"first day of week 1", 1, 1, 1},
+		{"last day of week 1", 1, 7, 7},
+		{"first day of week 2", 2, 1, 8},
+		{"deload week", 4, 3, 24},
+		{"day 0 of a week", 2, 0, 0},
+		{"day 8 of a week", 2, 8, 0},
+		{"negative day", 1, -1, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, (&domain.CompleteDayRequest{Week: tt.week, DayNumber: tt.dayNumber}).PlanDay())
+			assert.Equal(t, tt.want, (&domain.UncompleteDayRequest{Week: tt.week, DayNumber: tt.dayNumber}).PlanDay())
+			assert.Equal(t, tt.want, (&domain.SkipDayRequest{Week: tt.week, DayNumber: tt.dayNumber}).PlanDay())
+		})
+	}
+
+	// 换算结果为 0 或超出计划天数时按无效计划日拒绝
+	plan := phasedPlan()
+	assert.False(t, validPlanDay(plan, (&domain.CompleteDayRequest{Week: 2, DayNumber: 8}).PlanDay()))
+	assert.False(t, validPlanDay(plan, (&domain.CompleteDayRequest{Week: 5, DayNumber: 1}).PlanDay()))
+	assert.True(t, validPlanDay(plan, (&domain.CompleteDayRequest{Week: 4, DayNumber: 7}).PlanDay()))
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/progressutil"
+	"github.com/zhengshui/flow-link-server/internal/prutil"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+)
+
+// validateProgressions 校验动作的进阶规则
+func validateProgressions(exercises []domain.Exercise) error {
+	for _, exercise := range exercises {
+		if exercise.Progression != nil && !progressutil.Valid(*exercise.Progression) {
+			return domain.ErrInvalidProgression
+		}
+	}
+	return nil
+}
+
+// applyProgressions 将训练安排中带进阶规则的动作替换为所在计划周的目标，
+// 起点取用户在该计划中的成绩，百分比周期使用动作的估算 1RM
+func (fu *fitnessPlanUsecase) applyProgressions(ctx context.Context, user domain.User, plan domain.FitnessPlan, schedule scheduleutil.Schedule, sessions []domain.PlanSession) error {
+	keys := []string{}
+	for _, session := range sessions {
+		for _, exercise := range session.Exercises {
+			if exercise.Progression != nil {
+				keys = append(keys, prutil.ExerciseKey(exercise))
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	userID := user.ID.Hex()
+	records := []domain.TrainingRecord{}
+	err := eachRecord(ctx, fu.trainingRecordRepository, userID, time.Time{}, time.Time{}, plan.ID.Hex(), func(record *domain.TrainingRecord) {
+		records = append(records, *record)
+	})
+	if err != nil {
+		return err
+	}
+	history := progressutil.History(records, schedule.Week, timeutil.Location(user.TimeZone))
+
+	events, err := fu.personalRecordRepository.GetByExerciseKeys(ctx, userID, keys, "")
+	if err != nil {
+		return err
+	}
+	oneRMs := make(map[string]float64)
+	for _, event := range events {
+		if event.Type == domain.PRTypeEstimatedOneRM && event.Value > oneRMs[event.ExerciseKey] {
+			oneRMs[event.ExerciseKey] = event.Value
+		}
+	}
+
+	for i := range sessions {
+		exercises := make([]domain.Exercise, len(sessions[i].Exercises))
+		for j, exercise := range sessions[i].Exercises {
+			key := prutil.ExerciseKey(exercise)
+			target, deload := progressutil.Target(exercise, sessions[i].Week, history[key], oneRMs[key])
+			exercises[j] = target
+			sessions[i].Deload = sessions[i].Deload || deload
+		}
+		sessions[i].Exercises = exercises
+	}
+	return nil
+}
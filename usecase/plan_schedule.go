@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+)
+
+const (
+	// maxUpcomingDays 近期训练安排最多查看的天数
+	maxUpcomingDays = 60
+	// maxActivePlans 排期时读取的进行中计划数上限
+	maxActivePlans = 100
+	// maxShiftDays 一次顺延的最多天数
+	maxShiftDays = 90
+	// maxPlanTrendSessions 计划统计趋势最多包含的训练次数
+	maxPlanTrendSessions = 30
+)
+
+// planSchedule 按计划或用户偏好的训练星期将计划排到日历上
+func planSchedule(plan domain.FitnessPlan, user domain.User) (scheduleutil.Schedule, error) {
+	return scheduleutil.New(plan, user.TrainingWeekdays)
+}
+
+// scheduledSessions 计划日程中的全部训练，开始日期无效时返回 nil，没有训练日时为空
+func scheduledSessions(plan domain.FitnessPlan, user domain.User, today string) []domain.PlanSession {
+	schedule, err := planSchedule(plan, user)
+	if err != nil {
+		return nil
+	}
+	return schedule.Sessions("", schedule.EndDate(), today)
+}
+
+// applyPlanPosition 按用户时区的今天重新计算计划进行到第几周第几天，开始日期无效时保持原值
+func applyPlanPosition(plan *domain.FitnessPlan, user domain.User, today string) {
+	schedule, err := planSchedule(*plan, user)
+	if err != nil {
+		return
+	}
+	plan.CurrentWeek, plan.CurrentDay = schedule.Position(today)
+}
+
+// sortedWeekdays 校验训练星期并按周一到周日排序
+func sortedWeekdays(weekdays []int) ([]int, error) {
+	if !scheduleutil.ValidWeekdays(weekdays) {
+		return nil, domain.ErrInvalidTrainingWeekdays
+	}
+	sorted := append([]int{}, weekdays...)
+	sort.Ints(sorted)
+	return sorted, nil
+}
+
+// shiftStartDay 顺延默认的起始计划日：最近一次完成之后最早错过的训练，没有错过时从今天开始
+func shiftStartDay(plan domain.FitnessPlan, schedule scheduleutil.Schedule, today string) int {
+	lastCompleted := 0
+	if len(plan.CompletedDays) > 0 {
+		lastCompleted = slices.Max(plan.CompletedDays)
+	}
+	for _, session := range schedule.Sessions("", today, today) {
+		if session.Status == domain.SessionStatusMissed && session.DayNumber > lastCompleted {
+			return session.DayNumber
+		}
+	}
+
+	day, _ := timeutil.DaysBetween(plan.StartDate, today)
+	return max(day+1, lastCompleted+1)
+}
+
+// applyReschedule 追加日程调整记录，并按同一规则重新编号完成、跳过的计划日、跳过记录和临时调整，结束日期随计划总天数更新；
+// 重新编号可能合并完成记录，完成天数和完成率随之重新计算
+func applyReschedule(plan *domain.FitnessPlan, change domain.PlanReschedule) {
+	remap := func(day int) int {
+		return scheduleutil.Remap(change, day)
+	}
+
+	plan.CompletedDays = remapDays(plan.CompletedDays, remap)
+	plan.SkippedDays = remapDays(plan.SkippedDays, remap)
+	for i := range plan.SkipLog {
+		plan.SkipLog[i].DayNumber = remap(plan.SkipLog[i].DayNumber)
+	}
+
+	// 临时调整跟随训练移动，目标日上原有的临时调整被覆盖
+	moved := []domain.TrainingDay{}
+	kept := []domain.TrainingDay{}
+	for _, day := range plan.TrainingDaysOverride {
+		if next := remap(day.DayNumber); next != day.DayNumber {
+			day.DayNumber = next
+			moved = append(moved, day)
+		} else {
+			kept = append(kept, day)
+		}
+	}
+	overrides := moved
+	for _, day := range kept {
+		if !slices.ContainsFunc(moved, func(m domain.TrainingDay) bool { return m.DayNumber == day.DayNumber }) {
+			overrides = append(overrides, day)
+		}
+	}
+	if plan.TrainingDaysOverride != nil {
+		plan.TrainingDaysOverride = overrides
+	}
+
+	plan.Reschedules = append(plan.Reschedules, change)
+	plan.EndDate = addDays(plan.StartDate, scheduleutil.TotalDays(*plan)-1)
+
+	plan.TotalCompletedDays = len(plan.CompletedDays)
+	plan.CompletionRate = 0
+	if totalDays := plan.TotalSessions(); totalDays > 0 {
+		plan.CompletionRate = (plan.TotalCompletedDays * 100) / totalDays
+	}
+}
+
+// remapDays 重新编号计划日并去重
+func remapDays(days []int, remap func(int) int) []int {
+	result := make([]int, 0, len(days))
+	for _, day := range days {
+		if next := remap(day); !slices.Contains(result, next) {
+			result = append(result, next)
+		}
+	}
+	return result
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/scheduleutil"
+)
+
+// schedulePlan 2025-11-05（周三）开始的两周计划，每周一、三、五训练：
+// 训练安排在第 1、3、6、8、10、13 天
+func schedulePlan() domain.FitnessPlan {
+	return domain.FitnessPlan{
+		DurationWeeks:       2,
+		TrainingDaysPerWeek: 3,
+		TrainingWeekdays:    []int{1, 3, 5},
+		StartDate:           "2025-11-05",
+		EndDate:             "2025-11-18",
+		TrainingDays: []domain.TrainingDay{
+			{DayNumber: 1, DayName: "A"},
+			{DayNumber: 2, DayName: "B"},
+			{DayNumber: 3, DayName: "C"},
+		},
+	}
+}
+
+func TestApplyReschedule(t *testing.T) {
+	tests := []struct {
+		name   string
+		change domain.PlanReschedule
+		before func(plan *domain.FitnessPlan)
+		check  func(t *testing.T, plan domain.FitnessPlan)
+	}{
+		{
+			name:   "shift",
+			change: domain.PlanReschedule{Action: domain.RescheduleShift, DayNumber: 6, Days: 2},
+			before: func(plan *domain.FitnessPlan) {
+				plan.CompletedDays = []int{1, 3}
+				plan.SkippedDays = []int{6}
+				plan.SkipLog = []domain.PlanSkip{{DayNumber: 6, Reason: "出差"}}
+				plan.TrainingDaysOverride = []domain.TrainingDay{{DayNumber: 8, Notes: "临时调整"}}
+			},
+			check: func(t *testing.T, plan domain.FitnessPlan) {
+				assert.Equal(t, []int{1, 3}, plan.CompletedDays)
+				assert.Equal(t, []int{8}, plan.SkippedDays)
+				assert.Equal(t, 8, plan.SkipLog[0].DayNumber)
+				assert.Equal(t, []domain.TrainingDay{{DayNumber: 10, Notes: "临时调整"}}, plan.TrainingDaysOverride)
+				assert.Equal(t, "2025-11-20", plan.EndDate)
+				assert.Equal(t, 2, plan.TotalCompletedDays)
+				assert.Equal(t, 2*100/6, plan.CompletionRate)
+			},
+		},
+		{
+			name:   "swap exchanges overrides",
+			change: domain.PlanReschedule{Action: domain.RescheduleSwap, DayNumber: 8, TargetDay: 10},
+			before: func(plan *domain.FitnessPlan) {
+				plan.CompletedDays = []int{1}
+				plan.SkippedDays = []int{8}
+				plan.TrainingDaysOverride = []domain.TrainingDay{{DayNumber: 8, Notes: "八"}, {DayNumber: 10, Notes: "十"}}
+			},
+			check: func(t *testing.T, plan domain.FitnessPlan) {
+				assert.Equal(t, []int{1}, plan.CompletedDays)
+				assert.Equal(t, []int{10}, plan.SkippedDays)
+				assert.ElementsMatch(t, []domain.TrainingDay{{DayNumber: 10, Notes: "八"}, {DayNumber: 8, Notes: "十"}}, plan.TrainingDaysOverride)
+				assert.Equal(t, "2025-11-18", plan.EndDate)
+			},
+		},
+		{
+			name:   "move replaces the target override",
+			change: domain.PlanReschedule{Action: domain.RescheduleMove, DayNumber: 10, TargetDay: 11},
+			before: func(plan *domain.FitnessPlan) {
+				plan.TrainingDaysOverride = []domain.TrainingDay{{DayNumber: 10, Notes: "移动的训练"}, {DayNumber: 11, Notes: "被覆盖"}, {DayNumber: 13, Notes: "不受影响"}}
+			},
+			check: func(t *testing.T, plan domain.FitnessPlan) {
+				assert.Equal(t, []domain.TrainingDay{{DayNumber: 11, Notes: "移动的训练"}, {DayNumber: 13, Notes: "不受影响"}}, plan.TrainingDaysOverride)
+				assert.Empty(t, plan.CompletedDays)
+				assert.Zero(t, plan.CompletionRate)
+			},
+		},
+		{
+			name:   "remapped completions are deduplicated",
+			change: domain.PlanReschedule{Action: domain.RescheduleMove, DayNumber: 1, TargetDay: 3},
+			before: func(plan *domain.FitnessPlan) {
+				plan.CompletedDays = []int{1, 3, 6}
+				plan.SkippedDays = []int{1, 3}
+				plan.TotalCompletedDays = 3
+				plan.CompletionRate = 50
+			},
+			check: func(t *testing.T, plan domain.FitnessPlan) {
+				assert.Equal(t, []int{3, 6}, plan.CompletedDays)
+				assert.Equal(t, []int{3}, plan.SkippedDays)
+				assert.Equal(t, 2, plan.TotalCompletedDays)
+				assert.Equal(t, 2*100/6, plan.CompletionRate)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := schedulePlan()
+			tt.before(&plan)
+
+			applyReschedule(&plan, tt.change)
+			assert.Equal(t, []domain.PlanReschedule{tt.change}, plan.Reschedules)
+			tt.check(t, plan)
+		})
+	}
+}
+
+func TestShiftStartDay(t *testing.T) {
+	tests := []struct {
+		name      string
+		completed []int
+		skipped   []int
+		today     string
+		want      int
+	}{
+		{name: "earliest missed session", today: "2025-11-10", want: 1},
+		{name: "missed after the last completion", completed: []int{1, 3}, today: "2025-11-12", want: 6},
+		{name: "skipped sessions are not missed", completed: []int{1, 3}, skipped: []int{6}, today: "2025-11-12", want: 8},
+		{name: "nothing missed starts today", completed: []int{1, 3, 6}, today: "2025-11-12", want: 8},
+		{name: "after a completion ahead of today", completed: []int{1, 3, 6, 8, 10}, today: "2025-11-12", want: 11},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := schedulePlan()
+			plan.CompletedDays = tt.completed
+			plan.SkippedDays = tt.skipped
+			schedule, err := scheduleutil.New(plan, nil)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.want, shiftStartDay(plan, schedule, tt.today))
+		})
+	}
+}
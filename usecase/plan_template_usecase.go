@@ -11,12 +11,14 @@ import (
 
 type planTemplateUsecase struct {
 	planTemplateRepository domain.PlanTemplateRepository
+	exerciseResolver       domain.ExerciseResolver
 	contextTimeout         time.Duration
 }
 
-func NewPlanTemplateUsecase(planTemplateRepository domain.PlanTemplateRepository, timeout time.Duration) domain.PlanTemplateUsecase {
+func NewPlanTemplateUsecase(planTemplateRepository domain.PlanTemplateRepository, exerciseResolver domain.ExerciseResolver, timeout time.Duration) domain.PlanTemplateUsecase {
 	return &planTemplateUsecase{
 		planTemplateRepository: planTemplateRepository,
+		exerciseResolver:       exerciseResolver,
 		contextTimeout:         timeout,
 	}
 }
@@ -60,6 +62,15 @@ func (ptu *planTemplateUsecase) CreateCustom(c context.Context, userID string, r
 		trainingDays = []domain.TrainingDay{}
 	}
 
+	// Link exercises to the catalog
+	if err := resolveTrainingDays(ctx, ptu.exerciseResolver, trainingDays); err != nil {
+		return nil, err
+	}
+
+	if err := resolvePhases(ctx, ptu.exerciseResolver, request.Phases, request.DurationWeeks); err != nil {
+		return nil, err
+	}
+
 	tags := request.Tags
 	if tags == nil {
 		tags = []string{}
@@ -77,6 +88,7 @@ func (ptu *planTemplateUsecase) CreateCustom(c context.Context, userID string, r
 		DurationWeeks:        request.DurationWeeks,
 		TrainingDaysPerWeek:  request.TrainingDaysPerWeek,
 		TrainingDays:         trainingDays,
+		Phases:               request.Phases,
 		Tags:                 tags,
 		ImageUrl:             request.ImageUrl,
 		RecommendedIntensity: request.RecommendedIntensity,
@@ -105,6 +117,15 @@ func (ptu *planTemplateUsecase) CreateOfficial(c context.Context, request *domai
 		trainingDays = []domain.TrainingDay{}
 	}
 
+	// Link exercises to the catalog
+	if err := resolveTrainingDays(ctx, ptu.exerciseResolver, trainingDays); err != nil {
+		return nil, err
+	}
+
+	if err := resolvePhases(ctx, ptu.exerciseResolver, request.Phases, request.DurationWeeks); err != nil {
+		return nil, err
+	}
+
 	tags := request.Tags
 	if tags == nil {
 		tags = []string{}
@@ -128,6 +149,7 @@ func (ptu *planTemplateUsecase) CreateOfficial(c context.Context, request *domai
 		DurationWeeks:        request.DurationWeeks,
 		TrainingDaysPerWeek:  request.TrainingDaysPerWeek,
 		TrainingDays:         trainingDays,
+		Phases:               request.Phases,
 		Tags:                 tags,
 		ImageUrl:             request.ImageUrl,
 		RecommendedIntensity: request.RecommendedIntensity,
@@ -183,6 +205,7 @@ func (ptu *planTemplateUsecase) Duplicate(c context.Context, userID, templateID
 		DurationWeeks:        original.DurationWeeks,
 		TrainingDaysPerWeek:  original.TrainingDaysPerWeek,
 		TrainingDays:         trainingDays,
+		Phases:               original.Phases,
 		Tags:                 tags,
 		ImageUrl:             original.ImageUrl,
 		RecommendedIntensity: original.RecommendedIntensity,
@@ -247,8 +270,19 @@ func (ptu *planTemplateUsecase) Update(c context.Context, userID, templateID str
 		template.TrainingDaysPerWeek = *request.TrainingDaysPerWeek
 	}
 	if request.TrainingDays != nil {
+		if err := resolveTrainingDays(ctx, ptu.exerciseResolver, request.TrainingDays); err != nil {
+			return err
+		}
 		template.TrainingDays = request.TrainingDays
 	}
+	if request.Phases != nil {
+		template.Phases = request.Phases
+	}
+	if request.Phases != nil || request.DurationWeeks != nil {
+		if err := resolvePhases(ctx, ptu.exerciseResolver, template.Phases, template.DurationWeeks); err != nil {
+			return err
+		}
+	}
 	if request.Tags != nil {
 		template.Tags = request.Tags
 	}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/imageutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// progressPhotoThumbnailSize 进度照片缩略图的长边(px)
+const progressPhotoThumbnailSize = 320
+
+type progressPhotoUsecase struct {
+	progressPhotoRepository domain.ProgressPhotoRepository
+	userRepository          domain.UserRepository
+	blobStore               domain.BlobStore
+	maxUploadSize           int64
+	contextTimeout          time.Duration
+}
+
+func NewProgressPhotoUsecase(
+	progressPhotoRepository domain.ProgressPhotoRepository,
+	userRepository domain.UserRepository,
+	blobStore domain.BlobStore,
+	maxUploadSize int64,
+	timeout time.Duration,
+) domain.ProgressPhotoUsecase {
+	return &progressPhotoUsecase{
+		progressPhotoRepository: progressPhotoRepository,
+		userRepository:          userRepository,
+		blobStore:               blobStore,
+		maxUploadSize:           maxUploadSize,
+		contextTimeout:          timeout,
+	}
+}
+
+func (pu *progressPhotoUsecase) Upload(c context.Context, userID, date string, notes *string, file io.Reader) (domain.ProgressPhoto, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	user, err := pu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.ProgressPhoto{}, err
+	}
+
+	// 未指定日期时记在用户时区的今天
+	if date == "" {
+		date = timeutil.Today(timeutil.Location(user.TimeZone))
+	}
+	if !validDate(date) {
+		return domain.ProgressPhoto{}, domain.ErrInvalidProgressPhoto
+	}
+
+	picture, err := readImage(file, pu.maxUploadSize)
+	if err != nil {
+		return domain.ProgressPhoto{}, err
+	}
+	original, err := picture.Encode()
+	if err != nil {
+		return domain.ProgressPhoto{}, err
+	}
+	thumbnail, err := picture.Thumbnail(progressPhotoThumbnailSize, false)
+	if err != nil {
+		return domain.ProgressPhoto{}, err
+	}
+
+	photo := domain.ProgressPhoto{
+		ID:          primitive.NewObjectID(),
+		UserID:      user.ID,
+		Date:        date,
+		ContentType: original.ContentType,
+		Size:        int64(len(original.Data)),
+		Width:       original.Width,
+		Height:      original.Height,
+		Notes:       notes,
+	}
+	keyPrefix := userBlobPrefix(userID) + "photos/" + photo.ID.Hex()
+	photo.Key = keyPrefix + imageExtension(original.ContentType)
+	photo.ThumbnailKey = keyPrefix + "_thumb.jpg"
+
+	err = pu.blobStore.Put(ctx, photo.Key, original.Data, original.ContentType)
+	if err != nil {
+		return domain.ProgressPhoto{}, err
+	}
+	err = pu.blobStore.Put(ctx, photo.ThumbnailKey, thumbnail.Data, thumbnail.ContentType)
+	if err == nil {
+		err = pu.progressPhotoRepository.Create(ctx, &photo)
+	}
+	if err != nil {
+		// 记录没有保存成功时清理已上传的文件
+		pu.blobStore.Delete(ctx, photo.Key)
+		pu.blobStore.Delete(ctx, photo.ThumbnailKey)
+		return domain.ProgressPhoto{}, err
+	}
+
+	return withPhotoURLs(photo), nil
+}
+
+func (pu *progressPhotoUsecase) GetByID(c context.Context, userID, id string) (domain.ProgressPhoto, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	photo, err := pu.getOwned(ctx, userID, id)
+	if err != nil {
+		return photo, err
+	}
+	return withPhotoURLs(photo), nil
+}
+
+func (pu *progressPhotoUsecase) GetList(c context.Context, userID, startDate, endDate string) ([]domain.ProgressPhoto, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	if (startDate != "" && !validDate(startDate)) || (endDate != "" && !validDate(endDate)) {
+		return nil, domain.ErrInvalidProgressPhoto
+	}
+
+	photos, err := pu.progressPhotoRepository.GetByUserID(ctx, userID, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	for i := range photos {
+		photos[i] = withPhotoURLs(photos[i])
+	}
+	return photos, nil
+}
+
+func (pu *progressPhotoUsecase) Open(c context.Context, userID, id string, thumbnail bool) (io.ReadCloser, domain.BlobInfo, error) {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	photo, err := pu.getOwned(ctx, userID, id)
+	if err != nil {
+		return nil, domain.BlobInfo{}, err
+	}
+
+	key := photo.Key
+	if thumbnail {
+		key = photo.ThumbnailKey
+	}
+	// 内容在返回后才读取，不能使用本方法结束时就取消的 ctx
+	return pu.blobStore.Get(c, key)
+}
+
+func (pu *progressPhotoUsecase) Delete(c context.Context, userID, id string) error {
+	ctx, cancel := context.WithTimeout(c, pu.contextTimeout)
+	defer cancel()
+
+	photo, err := pu.getOwned(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	err = pu.progressPhotoRepository.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = pu.blobStore.Delete(ctx, photo.Key)
+	if err != nil {
+		return err
+	}
+	return pu.blobStore.Delete(ctx, photo.ThumbnailKey)
+}
+
+// getOwned 读取属于该用户的照片，不属于该用户时按不存在处理
+func (pu *progressPhotoUsecase) getOwned(ctx context.Context, userID, id string) (domain.ProgressPhoto, error) {
+	photo, err := pu.progressPhotoRepository.GetByID(ctx, id)
+	if err != nil {
+		return photo, err
+	}
+	if photo.UserID.Hex() != userID {
+		return domain.ProgressPhoto{}, domain.ErrProgressPhotoNotFound
+	}
+	return photo, nil
+}
+
+// withPhotoURLs 填充需要登录访问的照片下载地址
+func withPhotoURLs(photo domain.ProgressPhoto) domain.ProgressPhoto {
+	photo.URL = "/api/progress-photos/" + photo.ID.Hex() + "/file"
+	photo.ThumbnailURL = "/api/progress-photos/" + photo.ID.Hex() + "/thumbnail"
+	return photo
+}
+
+// readImage 读取并解码上传的图片，超过 maxSize 字节时返回 ErrImageTooLarge
+func readImage(file io.Reader, maxSize int64) (imageutil.Picture, error) {
+	data, err := io.ReadAll(io.LimitReader(file, maxSize+1))
+	if err != nil {
+		return imageutil.Picture{}, err
+	}
+	if int64(len(data)) > maxSize {
+		return imageutil.Picture{}, domain.ErrImageTooLarge
+	}
+	return imageutil.Decode(data)
+}
+
+// userBlobPrefix 用户上传文件在存储中的公共前缀，注销时按前缀整体删除
+func userBlobPrefix(userID string) string {
+	return "users/" + userID + "/"
+}
+
+func imageExtension(contentType string) string {
+	if contentType == imageutil.ContentTypePNG {
+		return ".png"
+	}
+	return ".jpg"
+}
@@ -6,17 +6,24 @@ import (
 
 	"github.com/zhengshui/flow-link-server/domain"
 	"github.com/zhengshui/flow-link-server/internal/tokenutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type refreshTokenUsecase struct {
-	userRepository domain.UserRepository
-	contextTimeout time.Duration
+	userRepository         domain.UserRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	sessionRepository      domain.SessionRepository
+	roleRepository         domain.RoleRepository
+	contextTimeout         time.Duration
 }
 
-func NewRefreshTokenUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.RefreshTokenUsecase {
+func NewRefreshTokenUsecase(userRepository domain.UserRepository, refreshTokenRepository domain.RefreshTokenRepository, sessionRepository domain.SessionRepository, roleRepository domain.RoleRepository, timeout time.Duration) domain.RefreshTokenUsecase {
 	return &refreshTokenUsecase{
-		userRepository: userRepository,
-		contextTimeout: timeout,
+		userRepository:         userRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		sessionRepository:      sessionRepository,
+		roleRepository:         roleRepository,
+		contextTimeout:         timeout,
 	}
 }
 
@@ -26,14 +33,157 @@ func (rtu *refreshTokenUsecase) GetUserByID(c context.Context, email string) (do
 	return rtu.userRepository.GetByID(ctx, email)
 }
 
-func (rtu *refreshTokenUsecase) CreateAccessToken(user *domain.User, secret string, expiry int) (accessToken string, err error) {
-	return tokenutil.CreateAccessToken(user, secret, expiry)
+func (rtu *refreshTokenUsecase) CreateAccessToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (accessToken string, err error) {
+	ctx, cancel := context.WithTimeout(c, rtu.contextTimeout)
+	defer cancel()
+
+	permissions, err := permissionsForUser(ctx, rtu.roleRepository, user)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenutil.CreateAccessToken(user, sessionID, permissions, keys, expiry)
+}
+
+func (rtu *refreshTokenUsecase) CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (refreshToken string, err error) {
+	return tokenutil.CreateRefreshToken(user, keys, expiry)
+}
+
+func (rtu *refreshTokenUsecase) ExtractIDFromToken(requestToken string, keys domain.JwtKeySet) (string, error) {
+	return tokenutil.ExtractIDFromToken(requestToken, keys)
 }
 
-func (rtu *refreshTokenUsecase) CreateRefreshToken(user *domain.User, secret string, expiry int) (refreshToken string, err error) {
-	return tokenutil.CreateRefreshToken(user, secret, expiry)
+func (rtu *refreshTokenUsecase) IssueRefreshToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (refreshToken string, err error) {
+	ctx, cancel := context.WithTimeout(c, rtu.contextTimeout)
+	defer cancel()
+
+	familyID, err := primitive.ObjectIDFromHex(sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	return rtu.issue(ctx, user, primitive.NewObjectID(), familyID, keys, expiry)
 }
 
-func (rtu *refreshTokenUsecase) ExtractIDFromToken(requestToken string, secret string) (string, error) {
-	return tokenutil.ExtractIDFromToken(requestToken, secret)
+func (rtu *refreshTokenUsecase) RotateRefreshToken(c context.Context, requestToken string, keys domain.JwtKeySet, expiry int) (domain.RotatedRefreshToken, error) {
+	ctx, cancel := context.WithTimeout(c, rtu.contextTimeout)
+	defer cancel()
+
+	claims, err := tokenutil.ParseRefreshToken(requestToken, keys)
+	if err != nil {
+		return domain.RotatedRefreshToken{}, err
+	}
+
+	// 没有 jti 的旧版无状态令牌不再接受
+	if claims.Id == "" {
+		return domain.RotatedRefreshToken{}, domain.ErrRefreshTokenInvalid
+	}
+
+	stored, err := rtu.refreshTokenRepository.GetByID(ctx, claims.Id)
+	if err != nil {
+		return domain.RotatedRefreshToken{}, domain.ErrRefreshTokenInvalid
+	}
+
+	if stored.UserID.Hex() != claims.ID || stored.FamilyID.Hex() != claims.FamilyID {
+		return domain.RotatedRefreshToken{}, domain.ErrRefreshTokenInvalid
+	}
+
+	if stored.RevokedAt != nil {
+		return domain.RotatedRefreshToken{}, domain.ErrRefreshTokenRevoked
+	}
+
+	// 已轮换过的令牌再次出现，说明令牌可能已泄露，吊销整个家族
+	if stored.UsedAt != nil {
+		return domain.RotatedRefreshToken{}, rtu.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+
+	user, err := rtu.userRepository.GetByID(ctx, claims.ID)
+	if err != nil {
+		return domain.RotatedRefreshToken{}, err
+	}
+
+	if user.Disabled {
+		return domain.RotatedRefreshToken{}, domain.ErrUserDisabled
+	}
+
+	newTokenID := primitive.NewObjectID()
+	consumed, err := rtu.refreshTokenRepository.MarkUsed(ctx, stored.ID.Hex(), newTokenID)
+	if err != nil {
+		return domain.RotatedRefreshToken{}, err
+	}
+
+	// 并发请求抢先消费了同一个令牌，同样视为重放
+	if !consumed {
+		return domain.RotatedRefreshToken{}, rtu.revokeReusedFamily(ctx, stored.FamilyID)
+	}
+
+	refreshToken, err := rtu.issue(ctx, &user, newTokenID, stored.FamilyID, keys, expiry)
+	if err != nil {
+		return domain.RotatedRefreshToken{}, err
+	}
+
+	// 刷新即视为会话活跃，会话有效期随刷新令牌顺延
+	sessionID := stored.FamilyID.Hex()
+	expiresAt := primitive.NewDateTimeFromTime(time.Now().Add(time.Hour * time.Duration(expiry)))
+	err = rtu.sessionRepository.Touch(ctx, sessionID, expiresAt)
+	if err != nil {
+		return domain.RotatedRefreshToken{}, err
+	}
+
+	return domain.RotatedRefreshToken{
+		User:         user,
+		SessionID:    sessionID,
+		RefreshToken: refreshToken,
+	}, nil
+}
+
+func (rtu *refreshTokenUsecase) RevokeRefreshToken(c context.Context, requestToken string, keys domain.JwtKeySet) error {
+	ctx, cancel := context.WithTimeout(c, rtu.contextTimeout)
+	defer cancel()
+
+	claims, err := tokenutil.ParseRefreshToken(requestToken, keys)
+	if err != nil {
+		return err
+	}
+
+	if claims.FamilyID == "" {
+		return domain.ErrRefreshTokenInvalid
+	}
+
+	err = rtu.refreshTokenRepository.RevokeFamily(ctx, claims.FamilyID)
+	if err != nil {
+		return err
+	}
+
+	return rtu.sessionRepository.Revoke(ctx, claims.FamilyID)
+}
+
+// issue 保存令牌状态并签发对应的 JWT
+func (rtu *refreshTokenUsecase) issue(ctx context.Context, user *domain.User, tokenID, familyID primitive.ObjectID, keys domain.JwtKeySet, expiry int) (string, error) {
+	expiresAt := time.Now().Add(time.Hour * time.Duration(expiry))
+
+	token := &domain.RefreshToken{
+		ID:        tokenID,
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		ExpiresAt: primitive.NewDateTimeFromTime(expiresAt),
+	}
+
+	err := rtu.refreshTokenRepository.Create(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenutil.CreateRefreshTokenWithID(user, tokenID.Hex(), familyID.Hex(), keys, expiry)
+}
+
+func (rtu *refreshTokenUsecase) revokeReusedFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	if err := rtu.refreshTokenRepository.RevokeFamily(ctx, familyID.Hex()); err != nil {
+		return err
+	}
+	// 会话同时失效，已签发的访问令牌也随之被拒绝
+	if err := rtu.sessionRepository.Revoke(ctx, familyID.Hex()); err != nil {
+		return err
+	}
+	return domain.ErrRefreshTokenReused
 }
@@ -0,0 +1,157 @@
+package usecase_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/internal/tokenutil"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type refreshTokenFixture struct {
+	user     domain.User
+	stored   domain.RefreshToken
+	token    string
+	keys     domain.JwtKeySet
+	users    *mocks.UserRepository
+	tokens   *mocks.RefreshTokenRepository
+	sessions *mocks.SessionRepository
+	usecase  domain.RefreshTokenUsecase
+}
+
+// newRefreshTokenFixture 一个家族中尚未使用的刷新令牌
+func newRefreshTokenFixture(t *testing.T) *refreshTokenFixture {
+	f := &refreshTokenFixture{
+		user:     domain.User{ID: primitive.NewObjectID(), Username: "alice"},
+		keys:     tokenutil.NewHMACKeySet("refresh-secret"),
+		users:    mocks.NewUserRepository(t),
+		tokens:   mocks.NewRefreshTokenRepository(t),
+		sessions: mocks.NewSessionRepository(t),
+	}
+	f.stored = domain.RefreshToken{ID: primitive.NewObjectID(), UserID: f.user.ID, FamilyID: primitive.NewObjectID()}
+
+	token, err := tokenutil.CreateRefreshTokenWithID(&f.user, f.stored.ID.Hex(), f.stored.FamilyID.Hex(), f.keys, 1)
+	require.NoError(t, err)
+	f.token = token
+
+	f.usecase = usecase.NewRefreshTokenUsecase(f.users, f.tokens, f.sessions, nil, time.Second)
+	return f
+}
+
+// expectFamilyRevoked 令牌家族和对应的会话都被吊销
+func (f *refreshTokenFixture) expectFamilyRevoked() {
+	f.tokens.On("RevokeFamily", mock.Anything, f.stored.FamilyID.Hex()).Return(nil).Once()
+	f.sessions.On("Revoke", mock.Anything, f.stored.FamilyID.Hex()).Return(nil).Once()
+}
+
+func TestRotateRefreshToken(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(f.stored, nil).Once()
+		f.users.On("GetByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil).Once()
+
+		var replacedBy primitive.ObjectID
+		f.tokens.On("MarkUsed", mock.Anything, f.stored.ID.Hex(), mock.AnythingOfType("primitive.ObjectID")).
+			Run(func(args mock.Arguments) { replacedBy = args.Get(2).(primitive.ObjectID) }).
+			Return(true, nil).Once()
+		f.tokens.On("Create", mock.Anything, mock.MatchedBy(func(token *domain.RefreshToken) bool {
+			return token.ID == replacedBy && token.UserID == f.user.ID && token.FamilyID == f.stored.FamilyID
+		})).Return(nil).Once()
+		f.sessions.On("Touch", mock.Anything, f.stored.FamilyID.Hex(), mock.AnythingOfType("primitive.DateTime")).Return(nil).Once()
+
+		rotated, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		require.NoError(t, err)
+		assert.Equal(t, f.user.ID, rotated.User.ID)
+		assert.Equal(t, f.stored.FamilyID.Hex(), rotated.SessionID)
+
+		// 新令牌留在同一家族中，jti 为轮换时登记的新令牌ID
+		claims, err := tokenutil.ParseRefreshToken(rotated.RefreshToken, f.keys)
+		require.NoError(t, err)
+		assert.Equal(t, replacedBy.Hex(), claims.Id)
+		assert.NotEqual(t, f.stored.ID.Hex(), claims.Id)
+		assert.Equal(t, f.stored.FamilyID.Hex(), claims.FamilyID)
+	})
+
+	t.Run("reused token revokes the family", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		usedAt := primitive.NewDateTimeFromTime(time.Now())
+		f.stored.UsedAt = &usedAt
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(f.stored, nil).Once()
+		f.expectFamilyRevoked()
+
+		_, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+	})
+
+	t.Run("concurrent rotation revokes the family", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(f.stored, nil).Once()
+		f.users.On("GetByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil).Once()
+		f.tokens.On("MarkUsed", mock.Anything, f.stored.ID.Hex(), mock.AnythingOfType("primitive.ObjectID")).Return(false, nil).Once()
+		f.expectFamilyRevoked()
+
+		_, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenReused)
+	})
+
+	t.Run("revoked family", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		revokedAt := primitive.NewDateTimeFromTime(time.Now())
+		f.stored.RevokedAt = &revokedAt
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(f.stored, nil).Once()
+
+		_, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenRevoked)
+	})
+
+	t.Run("family mismatch", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		stored := f.stored
+		stored.FamilyID = primitive.NewObjectID()
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(stored, nil).Once()
+
+		_, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(domain.RefreshToken{}, assert.AnError).Once()
+
+		_, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+	})
+
+	t.Run("stateless token without jti", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		token, err := tokenutil.CreateRefreshToken(&f.user, f.keys, 1)
+		require.NoError(t, err)
+
+		_, err = f.usecase.RotateRefreshToken(context.Background(), token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrRefreshTokenInvalid)
+	})
+
+	t.Run("disabled user", func(t *testing.T) {
+		f := newRefreshTokenFixture(t)
+		f.user.Disabled = true
+		f.tokens.On("GetByID", mock.Anything, f.stored.ID.Hex()).Return(f.stored, nil).Once()
+		f.users.On("GetByID", mock.Anything, f.user.ID.Hex()).Return(f.user, nil).Once()
+
+		_, err := f.usecase.RotateRefreshToken(context.Background(), f.token, f.keys, 1)
+		assert.ErrorIs(t, err, domain.ErrUserDisabled)
+	})
+}
+
+func TestRevokeRefreshToken(t *testing.T) {
+	f := newRefreshTokenFixture(t)
+	f.expectFamilyRevoked()
+
+	assert.NoError(t, f.usecase.RevokeRefreshToken(context.Background(), f.token, f.keys))
+}
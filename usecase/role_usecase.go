@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+)
+
+type roleUsecase struct {
+	roleRepository         domain.RoleRepository
+	userRepository         domain.UserRepository
+	sessionRepository      domain.SessionRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	contextTimeout         time.Duration
+}
+
+func NewRoleUsecase(
+	roleRepository domain.RoleRepository,
+	userRepository domain.UserRepository,
+	sessionRepository domain.SessionRepository,
+	refreshTokenRepository domain.RefreshTokenRepository,
+	timeout time.Duration,
+) domain.RoleUsecase {
+	return &roleUsecase{
+		roleRepository:         roleRepository,
+		userRepository:         userRepository,
+		sessionRepository:      sessionRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+func (ru *roleUsecase) Fetch(c context.Context) ([]domain.Role, error) {
+	ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+	defer cancel()
+	return ru.roleRepository.Fetch(ctx)
+}
+
+func (ru *roleUsecase) Upsert(c context.Context, name string, request *domain.UpsertRoleRequest) (domain.Role, error) {
+	ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+	defer cancel()
+
+	if name == domain.RoleAdmin {
+		return domain.Role{}, domain.ErrRoleProtected
+	}
+
+	known := map[string]bool{}
+	for _, permission := range domain.AllPermissions {
+		known[permission] = true
+	}
+	for _, permission := range request.Permissions {
+		if !known[permission] {
+			return domain.Role{}, domain.ErrPermissionUnknown
+		}
+	}
+
+	role := domain.Role{
+		Name:        name,
+		Description: request.Description,
+		Permissions: request.Permissions,
+	}
+
+	err := ru.roleRepository.Upsert(ctx, &role)
+	return role, err
+}
+
+func (ru *roleUsecase) AssignRoles(c context.Context, userID string, roles []string) error {
+	ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+	defer cancel()
+
+	if _, err := ru.userRepository.GetByID(ctx, userID); err != nil {
+		return err
+	}
+
+	if len(roles) > 0 {
+		existing, err := ru.roleRepository.GetByNames(ctx, roles)
+		if err != nil {
+			return err
+		}
+		found := map[string]bool{}
+		for _, role := range existing {
+			found[role.Name] = true
+		}
+		for _, name := range roles {
+			if !found[name] {
+				return domain.ErrRoleNotFound
+			}
+		}
+	}
+
+	err := ru.userRepository.UpdateRoles(ctx, userID, roles)
+	if err != nil {
+		return err
+	}
+
+	// 权限写在访问令牌中，吊销现有登录使新的角色立即生效
+	err = ru.refreshTokenRepository.RevokeByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	return ru.sessionRepository.RevokeByUserID(ctx, userID)
+}
+
+// permissionsForUser 汇总用户所有角色的权限，用于写入访问令牌
+func permissionsForUser(ctx context.Context, roleRepository domain.RoleRepository, user *domain.User) ([]string, error) {
+	// 管理员始终拥有全部权限，不依赖数据库中角色记录是否最新
+	if domain.HasRole(user.RoleNames(), domain.RoleAdmin) {
+		permissions := append([]string{}, domain.AllPermissions...)
+		sort.Strings(permissions)
+		return permissions, nil
+	}
+
+	roles, err := roleRepository.GetByNames(ctx, user.RoleNames())
+	if err != nil {
+		return nil, err
+	}
+
+	set := map[string]bool{}
+	for _, role := range roles {
+		for _, permission := range role.Permissions {
+			set[permission] = true
+		}
+	}
+
+	permissions := make([]string, 0, len(set))
+	for permission := range set {
+		permissions = append(permissions, permission)
+	}
+	sort.Strings(permissions)
+
+	return permissions, nil
+}
@@ -0,0 +1,93 @@
+package usecase_test
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/internal/tokenutil"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// accessTokenPermissions 签发访问令牌并返回其中的权限
+func accessTokenPermissions(t *testing.T, roles domain.RoleRepository, user domain.User) []string {
+	keys := tokenutil.NewHMACKeySet("access-secret")
+	token, err := usecase.NewLoginUsecase(mocks.NewUserRepository(t), roles, time.Second).
+		CreateAccessToken(context.Background(), &user, "", keys, 1)
+	require.NoError(t, err)
+
+	claims, err := tokenutil.ParseAccessToken(token, keys)
+	require.NoError(t, err)
+	return claims.Permissions
+}
+
+func TestAccessTokenPermissions(t *testing.T) {
+	t.Run("union of role permissions", func(t *testing.T) {
+		roles := mocks.NewRoleRepository(t)
+		roles.On("GetByNames", mock.Anything, []string{domain.RoleUser, domain.RoleCoach, domain.RoleSupport}).Return([]domain.Role{
+			{Name: domain.RoleCoach, Permissions: []string{domain.PermissionUserRead}},
+			{Name: domain.RoleSupport, Permissions: []string{domain.PermissionFeedbackTriage, domain.PermissionUserRead}},
+		}, nil).Once()
+
+		user := domain.User{ID: primitive.NewObjectID(), Role: domain.RoleUser, Roles: []string{domain.RoleCoach, domain.RoleSupport}}
+		assert.Equal(t, []string{domain.PermissionFeedbackTriage, domain.PermissionUserRead}, accessTokenPermissions(t, roles, user))
+	})
+
+	t.Run("admin has every permission", func(t *testing.T) {
+		// 管理员的权限不读取角色记录，数据库中的旧记录缺少新增权限也不影响
+		want := append([]string{}, domain.AllPermissions...)
+		sort.Strings(want)
+
+		user := domain.User{ID: primitive.NewObjectID(), Role: domain.RoleUser, Roles: []string{domain.RoleAdmin}}
+		assert.Equal(t, want, accessTokenPermissions(t, mocks.NewRoleRepository(t), user))
+	})
+}
+
+func TestAssignRoles(t *testing.T) {
+	user := domain.User{ID: primitive.NewObjectID(), Role: domain.RoleAdmin, Roles: []string{domain.RoleAdmin}}
+	userID := user.ID.Hex()
+
+	newRoleUsecase := func(t *testing.T) (domain.RoleUsecase, *mocks.RoleRepository, *mocks.UserRepository, *mocks.RefreshTokenRepository, *mocks.SessionRepository) {
+		roles := mocks.NewRoleRepository(t)
+		users := mocks.NewUserRepository(t)
+		tokens := mocks.NewRefreshTokenRepository(t)
+		sessions := mocks.NewSessionRepository(t)
+		return usecase.NewRoleUsecase(roles, users, sessions, tokens, time.Second), roles, users, tokens, sessions
+	}
+
+	t.Run("demoting an admin revokes sessions", func(t *testing.T) {
+		uc, roles, users, tokens, sessions := newRoleUsecase(t)
+		users.On("GetByID", mock.Anything, userID).Return(user, nil).Once()
+		roles.On("GetByNames", mock.Anything, []string{domain.RoleCoach}).Return([]domain.Role{{Name: domain.RoleCoach}}, nil).Once()
+		users.On("UpdateRoles", mock.Anything, userID, []string{domain.RoleCoach}).Return(nil).Once()
+		tokens.On("RevokeByUserID", mock.Anything, userID).Return(nil).Once()
+		sessions.On("RevokeByUserID", mock.Anything, userID).Return(nil).Once()
+
+		assert.NoError(t, uc.AssignRoles(context.Background(), userID, []string{domain.RoleCoach}))
+	})
+
+	t.Run("unknown role", func(t *testing.T) {
+		uc, roles, users, _, _ := newRoleUsecase(t)
+		users.On("GetByID", mock.Anything, userID).Return(user, nil).Once()
+		roles.On("GetByNames", mock.Anything, []string{"ghost"}).Return([]domain.Role{}, nil).Once()
+
+		assert.ErrorIs(t, uc.AssignRoles(context.Background(), userID, []string{"ghost"}), domain.ErrRoleNotFound)
+	})
+}
+
+func TestLegacyRole(t *testing.T) {
+	// 分配角色时 role 随 roles 更新，移除 admin 后不会再通过 role 保留管理员身份
+	demoted := domain.User{Role: domain.LegacyRole([]string{domain.RoleCoach}), Roles: []string{domain.RoleCoach}}
+	assert.Equal(t, domain.RoleUser, demoted.Role)
+	assert.False(t, domain.HasRole(demoted.RoleNames(), domain.RoleAdmin))
+
+	assert.Equal(t, domain.RoleAdmin, domain.LegacyRole([]string{domain.RoleCoach, domain.RoleAdmin}))
+	assert.Equal(t, domain.RoleUser, domain.LegacyRole(nil))
+}
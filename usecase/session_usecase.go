@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type sessionUsecase struct {
+	sessionRepository      domain.SessionRepository
+	refreshTokenRepository domain.RefreshTokenRepository
+	contextTimeout         time.Duration
+}
+
+func NewSessionUsecase(sessionRepository domain.SessionRepository, refreshTokenRepository domain.RefreshTokenRepository, timeout time.Duration) domain.SessionUsecase {
+	return &sessionUsecase{
+		sessionRepository:      sessionRepository,
+		refreshTokenRepository: refreshTokenRepository,
+		contextTimeout:         timeout,
+	}
+}
+
+func (su *sessionUsecase) Create(c context.Context, userID primitive.ObjectID, userAgent, ip string, expiry int) (domain.Session, error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	session := domain.Session{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		UserAgent: userAgent,
+		IP:        ip,
+		ExpiresAt: primitive.NewDateTimeFromTime(time.Now().Add(time.Hour * time.Duration(expiry))),
+	}
+
+	err := su.sessionRepository.Create(ctx, &session)
+	return session, err
+}
+
+func (su *sessionUsecase) GetActiveSessions(c context.Context, userID string) ([]domain.Session, error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	sessions, err := su.sessionRepository.GetActiveByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize empty array to avoid null in JSON
+	if sessions == nil {
+		sessions = []domain.Session{}
+	}
+
+	return sessions, nil
+}
+
+func (su *sessionUsecase) Revoke(c context.Context, userID, sessionID string) error {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	session, err := su.sessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// Validate ownership
+	if session.UserID.Hex() != userID {
+		return errors.New("unauthorized access to session")
+	}
+
+	err = su.sessionRepository.Revoke(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	// 会话ID即刷新令牌家族ID，一并吊销使其无法再刷新
+	return su.refreshTokenRepository.RevokeFamily(ctx, sessionID)
+}
+
+func (su *sessionUsecase) IsActive(c context.Context, sessionID string) (bool, error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	session, err := su.sessionRepository.GetByID(ctx, sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	if session.RevokedAt != nil {
+		return false, nil
+	}
+
+	return session.ExpiresAt.Time().After(time.Now()), nil
+}
@@ -10,12 +10,14 @@ import (
 
 type signupUsecase struct {
 	userRepository domain.UserRepository
+	roleRepository domain.RoleRepository
 	contextTimeout time.Duration
 }
 
-func NewSignupUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.SignupUsecase {
+func NewSignupUsecase(userRepository domain.UserRepository, roleRepository domain.RoleRepository, timeout time.Duration) domain.SignupUsecase {
 	return &signupUsecase{
 		userRepository: userRepository,
+		roleRepository: roleRepository,
 		contextTimeout: timeout,
 	}
 }
@@ -32,10 +34,42 @@ func (su *signupUsecase) GetUserByUsername(c context.Context, username string) (
 	return su.userRepository.GetByUsername(ctx, username)
 }
 
-func (su *signupUsecase) CreateAccessToken(user *domain.User, secret string, expiry int) (accessToken string, err error) {
-	return tokenutil.CreateAccessToken(user, secret, expiry)
+func (su *signupUsecase) CheckContactAvailable(c context.Context, email, phone string) error {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+	return checkContactAvailable(ctx, su.userRepository, "", email, phone)
 }
 
-func (su *signupUsecase) CreateRefreshToken(user *domain.User, secret string, expiry int) (refreshToken string, err error) {
-	return tokenutil.CreateRefreshToken(user, secret, expiry)
+func (su *signupUsecase) CreateAccessToken(c context.Context, user *domain.User, sessionID string, keys domain.JwtKeySet, expiry int) (accessToken string, err error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	permissions, err := permissionsForUser(ctx, su.roleRepository, user)
+	if err != nil {
+		return "", err
+	}
+
+	return tokenutil.CreateAccessToken(user, sessionID, permissions, keys, expiry)
+}
+
+func (su *signupUsecase) CreateRefreshToken(user *domain.User, keys domain.JwtKeySet, expiry int) (refreshToken string, err error) {
+	return tokenutil.CreateRefreshToken(user, keys, expiry)
+}
+
+// checkContactAvailable 校验邮箱和手机号未被 userID 以外的账号使用，空值跳过
+// 唯一索引是最终保障，这里提前检查以便返回具体是哪个字段冲突
+func checkContactAvailable(ctx context.Context, userRepository domain.UserRepository, userID, email, phone string) error {
+	if email != "" {
+		user, err := userRepository.GetByEmail(ctx, email)
+		if err == nil && user.ID.Hex() != userID {
+			return domain.ErrEmailTaken
+		}
+	}
+	if phone != "" {
+		user, err := userRepository.GetByPhone(ctx, phone)
+		if err == nil && user.ID.Hex() != userID {
+			return domain.ErrPhoneTaken
+		}
+	}
+	return nil
 }
@@ -3,21 +3,43 @@ package usecase
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/prutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// planTrendStatus 训练安排状态在计划统计趋势中的取值
+var planTrendStatus = map[string]string{
+	domain.SessionStatusCompleted: "completed",
+	domain.SessionStatusSkipped:   "skipped",
+	domain.SessionStatusMissed:    "missed",
+	domain.SessionStatusPending:   "pending",
+}
+
 type statsUsecase struct {
 	trainingRecordRepository domain.TrainingRecordRepository
 	fitnessPlanRepository    domain.FitnessPlanRepository
+	personalRecordRepository domain.PersonalRecordRepository
+	trainingRollupRepository domain.TrainingRollupRepository
+	bodyMetricRepository     domain.BodyMetricRepository
+	userRepository           domain.UserRepository
+	oneRMFormula             string
 	contextTimeout           time.Duration
 }
 
-func NewStatsUsecase(trainingRecordRepository domain.TrainingRecordRepository, fitnessPlanRepository domain.FitnessPlanRepository, timeout time.Duration) domain.StatsUsecase {
+func NewStatsUsecase(trainingRecordRepository domain.TrainingRecordRepository, fitnessPlanRepository domain.FitnessPlanRepository, personalRecordRepository domain.PersonalRecordRepository, trainingRollupRepository domain.TrainingRollupRepository, bodyMetricRepository domain.BodyMetricRepository, userRepository domain.UserRepository, oneRMFormula string, timeout time.Duration) domain.StatsUsecase {
 	return &statsUsecase{
 		trainingRecordRepository: trainingRecordRepository,
 		fitnessPlanRepository:    fitnessPlanRepository,
+		personalRecordRepository: personalRecordRepository,
+		trainingRollupRepository: trainingRollupRepository,
+		bodyMetricRepository:     bodyMetricRepository,
+		userRepository:           userRepository,
+		oneRMFormula:             oneRMFormula,
 		contextTimeout:           timeout,
 	}
 }
@@ -26,28 +48,33 @@ func (su *statsUsecase) GetTrainingStats(c context.Context, userID string, perio
 	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
 	defer cancel()
 
+	location, err := userLocation(ctx, su.userRepository, userID)
+	if err != nil {
+		return domain.TrainingStats{}, err
+	}
+
 	// Calculate date range based on period if not provided
 	if startDate == "" || endDate == "" {
-		now := time.Now()
+		now := time.Now().In(location)
 		switch period {
 		case "week":
-			startDate = now.AddDate(0, 0, -7).Format("2006-01-02")
-			endDate = now.Format("2006-01-02")
+			startDate = now.AddDate(0, 0, -7).Format(timeutil.DateLayout)
+			endDate = now.Format(timeutil.DateLayout)
 		case "month":
-			startDate = now.AddDate(0, -1, 0).Format("2006-01-02")
-			endDate = now.Format("2006-01-02")
+			startDate = now.AddDate(0, -1, 0).Format(timeutil.DateLayout)
+			endDate = now.Format(timeutil.DateLayout)
 		case "year":
-			startDate = now.AddDate(-1, 0, 0).Format("2006-01-02")
-			endDate = now.Format("2006-01-02")
+			startDate = now.AddDate(-1, 0, 0).Format(timeutil.DateLayout)
+			endDate = now.Format(timeutil.DateLayout)
 		default:
 			period = "week"
-			startDate = now.AddDate(0, 0, -7).Format("2006-01-02")
-			endDate = now.Format("2006-01-02")
+			startDate = now.AddDate(0, 0, -7).Format(timeutil.DateLayout)
+			endDate = now.Format(timeutil.DateLayout)
 		}
 	}
 
-	// Get training records for the period
-	records, _, err := su.trainingRecordRepository.GetByUserID(ctx, userID, 1, 1000, startDate, endDate, "")
+	// 读取区间内的日汇总
+	days, err := su.trainingRollupRepository.GetRange(ctx, userID, domain.RollupPeriodDay, startDate, endDate)
 	if err != nil {
 		return domain.TrainingStats{}, err
 	}
@@ -61,119 +88,46 @@ func (su *statsUsecase) GetTrainingStats(c context.Context, userID string, perio
 		DailyStats: []domain.DailyStats{},
 	}
 
-	if len(records) == 0 {
+	if len(days) == 0 {
 		return stats, nil
 	}
 
-	// Calculate statistics
-	totalTrainingCount := len(records)
-	totalDuration := 0
-	totalWeight := 0.0
-	totalSets := 0
-	totalCalories := 0
-
-	// Maps for muscle group and exercise tracking
-	muscleGroupCount := make(map[string]int)
-	exerciseCount := make(map[string]int)
-	dailyStatsMap := make(map[string]*domain.DailyStats)
-
-	for _, record := range records {
-		// 安全地累加指针类型的值
-		if record.Duration != nil {
-			totalDuration += *record.Duration
-		}
-		if record.TotalWeight != nil {
-			totalWeight += *record.TotalWeight
-		}
-		if record.TotalSets != nil {
-			totalSets += *record.TotalSets
-		}
-		if record.CaloriesBurned != nil {
-			totalCalories += *record.CaloriesBurned
-		}
-
-		// Extract date from startTime (YYYY-MM-DD HH:mm:ss -> YYYY-MM-DD)
-		recordDate := ""
-		if record.StartTime != nil && len(*record.StartTime) >= 10 {
-			recordDate = (*record.StartTime)[:10]
-		}
-
-		// 跳过没有有效日期的记录
-		if recordDate == "" {
-			continue
-		}
-
-		// Track daily stats
-		if _, exists := dailyStatsMap[recordDate]; !exists {
-			dailyStatsMap[recordDate] = &domain.DailyStats{
-				Date: recordDate,
-			}
-		}
-		dailyStats := dailyStatsMap[recordDate]
-		dailyStats.TrainingCount++
-		if record.Duration != nil {
-			dailyStats.Duration += *record.Duration
-		}
-		if record.TotalWeight != nil {
-			dailyStats.Weight += *record.TotalWeight
-		}
-		if record.TotalSets != nil {
-			dailyStats.Sets += *record.TotalSets
-		}
-		if record.CaloriesBurned != nil {
-			dailyStats.Calories += *record.CaloriesBurned
-		}
-
-		// Track muscle groups and exercises
-		for _, exercise := range record.Exercises {
-			if exercise.MuscleGroup != nil && *exercise.MuscleGroup != "" {
-				muscleGroupCount[*exercise.MuscleGroup]++
-			}
-			if exercise.Name != "" {
-				exerciseCount[exercise.Name]++
-			}
-		}
+	for _, day := range days {
+		stats.DailyStats = append(stats.DailyStats, domain.DailyStats{
+			Date:          day.PeriodStart,
+			TrainingCount: day.TrainingCount,
+			Duration:      day.Duration,
+			Weight:        day.Weight,
+			Sets:          day.Sets,
+			Calories:      day.Calories,
+		})
 	}
 
-	// Convert daily stats map to slice
-	for _, ds := range dailyStatsMap {
-		stats.DailyStats = append(stats.DailyStats, *ds)
-	}
-
-	// Find most trained muscle group
-	maxMuscleCount := 0
-	mostTrainedMuscle := ""
-	for muscle, count := range muscleGroupCount {
-		if count > maxMuscleCount {
-			maxMuscleCount = count
-			mostTrainedMuscle = muscle
-		}
-	}
-
-	// Find favorite exercise
-	maxExerciseCount := 0
-	favoriteExercise := ""
-	for exercise, count := range exerciseCount {
-		if count > maxExerciseCount {
-			maxExerciseCount = count
-			favoriteExercise = exercise
-		}
+	// 区间合计、肌群和动作排名由数据库聚合
+	total, err := su.trainingRollupRepository.Summarize(ctx, userID, domain.RollupPeriodDay, startDate, endDate)
+	if err != nil {
+		return domain.TrainingStats{}, err
 	}
 
 	// Set calculated values
-	stats.TotalTrainingCount = totalTrainingCount
-	stats.TotalDuration = totalDuration
-	stats.TotalWeight = totalWeight
-	stats.TotalSets = totalSets
-	stats.TotalCalories = totalCalories
+	stats.TotalTrainingCount = total.TrainingCount
+	stats.TotalDuration = total.Duration
+	stats.TotalWeight = total.Weight
+	stats.TotalSets = total.Sets
+	stats.TotalCalories = total.Calories
 
-	if totalTrainingCount > 0 {
-		stats.AvgDuration = totalDuration / totalTrainingCount
-		stats.AvgWeight = totalWeight / float64(totalTrainingCount)
+	if total.TrainingCount > 0 {
+		stats.AvgDuration = total.Duration / total.TrainingCount
+		stats.AvgWeight = total.Weight / float64(total.TrainingCount)
 	}
 
-	stats.MostTrainedMuscle = mostTrainedMuscle
-	stats.FavoriteExercise = favoriteExercise
+	// 汇总已按次数从多到少排序
+	if len(total.MuscleGroups) > 0 {
+		stats.MostTrainedMuscle = total.MuscleGroups[0].MuscleGroup
+	}
+	if len(total.Exercises) > 0 {
+		stats.FavoriteExercise = total.Exercises[0].Name
+	}
 
 	return stats, nil
 }
@@ -182,143 +136,99 @@ func (su *statsUsecase) GetMuscleGroupStats(c context.Context, userID string, pe
 	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
 	defer cancel()
 
+	location, err := userLocation(ctx, su.userRepository, userID)
+	if err != nil {
+		return nil, err
+	}
+
 	// Calculate date range based on period
-	now := time.Now()
+	now := time.Now().In(location)
 	var startDate, endDate string
 
 	switch period {
 	case "week":
-		startDate = now.AddDate(0, 0, -7).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(0, 0, -7).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 	case "month":
-		startDate = now.AddDate(0, -1, 0).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(0, -1, 0).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 	case "year":
-		startDate = now.AddDate(-1, 0, 0).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(-1, 0, 0).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 	default:
-		startDate = now.AddDate(0, -1, 0).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(0, -1, 0).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 	}
 
-	// Get training records for the period
-	records, _, err := su.trainingRecordRepository.GetByUserID(ctx, userID, 1, 1000, startDate, endDate, "")
+	// 区间内各肌群的次数和重量由数据库聚合
+	total, err := su.trainingRollupRepository.Summarize(ctx, userID, domain.RollupPeriodDay, startDate, endDate)
 	if err != nil {
 		return nil, err
 	}
 
-	// Calculate muscle group statistics
-	muscleGroupData := make(map[string]*domain.MuscleGroupStats)
 	totalCount := 0
-
-	for _, record := range records {
-		for _, exercise := range record.Exercises {
-			if exercise.MuscleGroup != nil && *exercise.MuscleGroup != "" {
-				muscleGroup := *exercise.MuscleGroup
-				if _, exists := muscleGroupData[muscleGroup]; !exists {
-					muscleGroupData[muscleGroup] = &domain.MuscleGroupStats{
-						MuscleGroup: muscleGroup,
-					}
-				}
-				stats := muscleGroupData[muscleGroup]
-				stats.TrainingCount++
-				// 安全计算总重量：weight * sets * reps
-				weight := 0.0
-				sets := 0
-				reps := 0
-				if exercise.Weight != nil {
-					weight = *exercise.Weight
-				}
-				if exercise.Sets != nil {
-					sets = *exercise.Sets
-				}
-				if exercise.Reps != nil {
-					reps = *exercise.Reps
-				}
-				stats.TotalWeight += weight * float64(sets*reps)
-				totalCount++
-			}
-		}
+	for _, muscleGroup := range total.MuscleGroups {
+		totalCount += muscleGroup.TrainingCount
 	}
 
-	// Convert map to slice and calculate percentages
+	// Convert rollup to stats and calculate percentages
 	result := []domain.MuscleGroupStats{}
-	for _, stats := range muscleGroupData {
+	for _, muscleGroup := range total.MuscleGroups {
+		stats := domain.MuscleGroupStats{
+			MuscleGroup:   muscleGroup.MuscleGroup,
+			TrainingCount: muscleGroup.TrainingCount,
+			TotalWeight:   muscleGroup.TotalWeight,
+		}
 		if totalCount > 0 {
 			stats.Percentage = (stats.TrainingCount * 100) / totalCount
 		}
-		result = append(result, *stats)
-	}
-
-	// Initialize empty array to avoid null in JSON
-	if result == nil {
-		result = []domain.MuscleGroupStats{}
+		result = append(result, stats)
 	}
 
 	return result, nil
 }
 
-func (su *statsUsecase) GetPersonalRecords(c context.Context, userID string) ([]domain.PersonalRecord, error) {
+func (su *statsUsecase) GetPersonalRecords(c context.Context, userID, formula string) ([]domain.PersonalRecord, error) {
 	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
 	defer cancel()
 
-	// Get all training records for the user
-	records, _, err := su.trainingRecordRepository.GetByUserID(ctx, userID, 1, 10000, "", "", "")
+	if formula == "" {
+		formula = su.oneRMFormula
+	}
+	if !domain.ValidOneRMFormula(formula) {
+		return nil, domain.ErrOneRMFormulaUnknown
+	}
+
+	location, err := userLocation(ctx, su.userRepository, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Track personal records for each exercise
-	prMap := make(map[string]*domain.PersonalRecord)
-
-	for _, record := range records {
-		for _, exercise := range record.Exercises {
-			if exercise.Name == "" {
-				continue
-			}
+	// 逐组统计每个动作的最大重量、估算 1RM、各次数档位最佳和单次训练量最佳
+	tracker := prutil.NewTracker(formula, location)
+	err = eachRecord(ctx, su.trainingRecordRepository, userID, time.Time{}, time.Time{}, "", tracker.Add)
+	if err != nil {
+		return nil, err
+	}
 
-			// Extract date from startTime (YYYY-MM-DD HH:mm:ss -> YYYY-MM-DD)
-			recordDate := ""
-			if record.StartTime != nil && len(*record.StartTime) >= 10 {
-				recordDate = (*record.StartTime)[:10]
-			}
+	return tracker.Records(), nil
+}
 
-			// 获取重量，如果为空则跳过
-			weight := 0.0
-			if exercise.Weight != nil {
-				weight = *exercise.Weight
-			}
+func (su *statsUsecase) GetPersonalRecordHistory(c context.Context, userID, exercise string, page, pageSize int) ([]domain.PersonalRecordEvent, int64, error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
 
-			// Check if this is a new PR for this exercise
-			if pr, exists := prMap[exercise.Name]; exists {
-				if weight > pr.MaxWeight {
-					pr.MaxWeight = weight
-					pr.Date = recordDate
-					pr.RecordID = 0 // Would need to store exercise ID to populate this
-				}
-			} else {
-				prMap[exercise.Name] = &domain.PersonalRecord{
-					ExerciseName: exercise.Name,
-					MaxWeight:    weight,
-					Date:         recordDate,
-					RecordID:     0,
-				}
-			}
+	// exercise 可以是动作库ID，也可以是自由文本名称
+	exerciseKey := ""
+	if exercise != "" {
+		if _, err := primitive.ObjectIDFromHex(exercise); err == nil {
+			exerciseKey = exercise
+		} else {
+			exerciseKey = domain.NormalizeExerciseTerm(exercise)
 		}
 	}
 
-	// Convert map to slice
-	result := []domain.PersonalRecord{}
-	for _, pr := range prMap {
-		result = append(result, *pr)
-	}
-
-	// Initialize empty array to avoid null in JSON
-	if result == nil {
-		result = []domain.PersonalRecord{}
-	}
-
-	return result, nil
+	return su.personalRecordRepository.GetTimeline(ctx, userID, exerciseKey, page, pageSize)
 }
 
 func (su *statsUsecase) GetCalendar(c context.Context, userID string, year, month int) ([]domain.CalendarDay, error) {
@@ -331,59 +241,42 @@ func (su *statsUsecase) GetCalendar(c context.Context, userID string, year, mont
 	// Get last day of month
 	firstDay := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
 	lastDay := firstDay.AddDate(0, 1, -1)
-	endDate := lastDay.Format("2006-01-02")
+	endDate := lastDay.Format(timeutil.DateLayout)
 
-	// Get training records for the month
-	records, _, err := su.trainingRecordRepository.GetByUserID(ctx, userID, 1, 1000, startDate, endDate, "")
+	location, err := userLocation(ctx, su.userRepository, userID)
 	if err != nil {
 		return nil, err
 	}
-
-	// Create a map to track training by date
-	dateMap := make(map[string]*domain.CalendarDay)
-
-	// Initialize all days of the month
-	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
-		dateStr := d.Format("2006-01-02")
-		dateMap[dateStr] = &domain.CalendarDay{
-			Date:        dateStr,
-			HasTraining: false,
-		}
+	start, end, err := timeutil.DayRange(startDate, endDate, location)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fill in training data
-	for _, record := range records {
-		// Extract date from startTime (YYYY-MM-DD HH:mm:ss -> YYYY-MM-DD)
-		recordDate := ""
-		if record.StartTime != nil && len(*record.StartTime) >= 10 {
-			recordDate = (*record.StartTime)[:10]
-		}
-
-		if recordDate == "" {
-			continue
-		}
+	// 当月训练记录按用户时区的日期分组汇总
+	days, err := su.trainingRecordRepository.SumByDay(ctx, userID, start, end, location)
+	if err != nil {
+		return nil, err
+	}
 
-		if day, exists := dateMap[recordDate]; exists {
-			day.HasTraining = true
-			day.TrainingCount++
-			if record.Duration != nil {
-				day.TotalDuration += *record.Duration
-			}
-		}
+	totals := make(map[string]domain.DayTotals)
+	for _, day := range days {
+		totals[day.Date] = day
 	}
 
-	// Convert map to slice
+	// 按天填充，没有训练的日期也返回
 	result := []domain.CalendarDay{}
 	for d := firstDay; !d.After(lastDay); d = d.AddDate(0, 0, 1) {
-		dateStr := d.Format("2006-01-02")
-		if day, exists := dateMap[dateStr]; exists {
-			result = append(result, *day)
+		dateStr := d.Format(timeutil.DateLayout)
+		day := domain.CalendarDay{
+			Date:        dateStr,
+			HasTraining: false,
 		}
-	}
-
-	// Initialize empty array to avoid null in JSON
-	if result == nil {
-		result = []domain.CalendarDay{}
+		if total, exists := totals[dateStr]; exists && total.TrainingCount > 0 {
+			day.HasTraining = true
+			day.TrainingCount = total.TrainingCount
+			day.TotalDuration = total.TotalDuration
+		}
+		result = append(result, day)
 	}
 
 	return result, nil
@@ -404,30 +297,51 @@ func (su *statsUsecase) GetPlanStats(c context.Context, userID, planID, period s
 		return domain.PlanStats{}, fmt.Errorf("unauthorized access to fitness plan")
 	}
 
+	user, err := su.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.PlanStats{}, err
+	}
+	location := timeutil.Location(user.TimeZone)
+
 	// Calculate date range based on period
-	now := time.Now()
+	now := time.Now().In(location)
 	var startDate, endDate string
 
 	switch period {
 	case "week":
-		startDate = now.AddDate(0, 0, -7).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(0, 0, -7).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 	case "month":
-		startDate = now.AddDate(0, -1, 0).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(0, -1, 0).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 	case "whole":
 		startDate = plan.StartDate
 		endDate = plan.EndDate
 	default:
-		startDate = now.AddDate(0, 0, -7).Format("2006-01-02")
-		endDate = now.Format("2006-01-02")
+		startDate = now.AddDate(0, 0, -7).Format(timeutil.DateLayout)
+		endDate = now.Format(timeutil.DateLayout)
 		period = "week"
 	}
 
-	// Get training records for this plan within the period
-	// Note: We need to get records related to this plan
-	// For now, we use plan stats directly
-	totalDays := plan.DurationWeeks * plan.TrainingDaysPerWeek
+	// 时长、重量、卡路里按周期内关联该计划的训练记录汇总
+	var start, end time.Time
+	if startDate != "" && endDate != "" {
+		start, end, err = timeutil.DayRange(startDate, endDate, location)
+		if err != nil {
+			return domain.PlanStats{}, err
+		}
+	}
+	totals, err := su.trainingRecordRepository.SumByPlan(ctx, userID, planID, start, end)
+	if err != nil {
+		return domain.PlanStats{}, err
+	}
+
+	// 按日程排出的训练计算完成率和趋势，训练星期和日程调整与训练安排接口一致
+	sessions := scheduledSessions(plan, user, now.Format(timeutil.DateLayout))
+	totalDays := plan.TotalSessions()
+	if len(sessions) > 0 {
+		totalDays = len(sessions)
+	}
 	completedDays := len(plan.CompletedDays)
 	skippedDays := len(plan.SkippedDays)
 
@@ -440,37 +354,14 @@ func (su *statsUsecase) GetPlanStats(c context.Context, userID, planID, period s
 
 	// Build trend data
 	trend := []domain.DailyStats{}
-
-	// Parse start date
-	planStartDate, err := time.Parse("2006-01-02", plan.StartDate)
-	if err == nil {
-		// Build daily trend for completed and skipped days
-		for i := 0; i < totalDays && i < 30; i++ { // Limit to 30 days
-			date := planStartDate.AddDate(0, 0, i).Format("2006-01-02")
-			dayNum := i + 1
-
-			// Check if day is completed or skipped
-			status := "pending"
-			for _, d := range plan.CompletedDays {
-				if d == dayNum {
-					status = "completed"
-					break
-				}
-			}
-			if status == "pending" {
-				for _, d := range plan.SkippedDays {
-					if d == dayNum {
-						status = "skipped"
-						break
-					}
-				}
-			}
-
-			trend = append(trend, domain.DailyStats{
-				Date:             date,
-				CompletionStatus: status,
-			})
+	for _, session := range sessions {
+		if len(trend) == maxPlanTrendSessions {
+			break
 		}
+		trend = append(trend, domain.DailyStats{
+			Date:             session.Date,
+			CompletionStatus: planTrendStatus[session.Status],
+		})
 	}
 
 	stats := domain.PlanStats{
@@ -481,9 +372,9 @@ func (su *statsUsecase) GetPlanStats(c context.Context, userID, planID, period s
 		CompletionRate: completionRate,
 		CompletedDays:  completedDays,
 		SkippedDays:    skippedDays,
-		TotalDuration:  plan.TotalDuration,
-		TotalWeight:    plan.TotalWeight,
-		TotalCalories:  plan.TotalCalories,
+		TotalDuration:  totals.TotalDuration,
+		TotalWeight:    totals.TotalWeight,
+		TotalCalories:  totals.TotalCalories,
 		Trend:          trend,
 	}
 
@@ -500,10 +391,19 @@ func (su *statsUsecase) GetPlanProgressList(c context.Context, userID, status st
 		return nil, 0, err
 	}
 
+	user, err := su.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	today := timeutil.Today(timeutil.Location(user.TimeZone))
+
 	// Build progress summaries
 	result := []domain.PlanProgressSummary{}
 	for _, plan := range plans {
-		totalDays := plan.DurationWeeks * plan.TrainingDaysPerWeek
+		totalDays := plan.TotalSessions()
+		if sessions := scheduledSessions(plan, user, today); len(sessions) > 0 {
+			totalDays = len(sessions)
+		}
 		completedDays := len(plan.CompletedDays)
 		skippedDays := len(plan.SkippedDays)
 
@@ -514,27 +414,16 @@ func (su *statsUsecase) GetPlanProgressList(c context.Context, userID, status st
 			completionRate = (completedDays * 100) / effectiveTotalDays
 		}
 
-		// Calculate current week and day
-		currentWeek := plan.CurrentWeek
-		currentDay := plan.CurrentDay
-		if plan.Status == "进行中" {
-			startDate, err := time.Parse("2006-01-02", plan.StartDate)
-			if err == nil {
-				daysSinceStart := int(time.Since(startDate).Hours() / 24)
-				if daysSinceStart >= 0 {
-					currentWeek = (daysSinceStart / 7) + 1
-					currentDay = (daysSinceStart % 7) + 1
-				}
-			}
-		}
+		// 按用户时区的今天计算进行到第几天
+		applyPlanPosition(&plan, user, today)
 
 		summary := domain.PlanProgressSummary{
 			PlanID:         plan.ID.Hex(),
 			Name:           plan.Name,
 			Status:         plan.Status,
 			CompletionRate: completionRate,
-			CurrentWeek:    currentWeek,
-			CurrentDay:     currentDay,
+			CurrentWeek:    plan.CurrentWeek,
+			CurrentDay:     plan.CurrentDay,
 			EndDate:        plan.EndDate,
 		}
 		result = append(result, summary)
@@ -547,3 +436,71 @@ func (su *statsUsecase) GetPlanProgressList(c context.Context, userID, status st
 
 	return result, total, nil
 }
+
+func (su *statsUsecase) GetWeightProgress(c context.Context, userID string) (domain.WeightProgress, error) {
+	ctx, cancel := context.WithTimeout(c, su.contextTimeout)
+	defer cancel()
+
+	user, err := su.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return domain.WeightProgress{}, err
+	}
+
+	metrics, err := su.bodyMetricRepository.GetByUserID(ctx, userID, "", "")
+	if err != nil {
+		return domain.WeightProgress{}, err
+	}
+	weights := []domain.BodyMetric{}
+	for _, metric := range metrics {
+		if metric.Weight != nil {
+			weights = append(weights, metric)
+		}
+	}
+
+	// 没有身体数据记录时只能使用个人资料中的体重
+	progress := domain.WeightProgress{
+		StartWeight:   user.Weight,
+		CurrentWeight: user.Weight,
+		TargetWeight:  user.TargetWeight,
+	}
+	if len(weights) > 0 {
+		first := weights[0]
+		last := weights[len(weights)-1]
+		progress.StartWeight = *first.Weight
+		progress.StartDate = first.Date
+		progress.CurrentWeight = *last.Weight
+		progress.CurrentDate = last.Date
+
+		// 最近 4 周内最早一次记录到最近一次记录的变化速度
+		since := addDays(last.Date, -28)
+		for _, metric := range weights {
+			if metric.Date >= since {
+				progress.WeeklyRate = weeklyRate(metric.Date, *metric.Weight, last.Date, *last.Weight)
+				break
+			}
+		}
+	}
+	progress.Change = roundMetric(progress.CurrentWeight - progress.StartWeight)
+
+	if progress.TargetWeight <= 0 {
+		return progress, nil
+	}
+	progress.Remaining = roundMetric(progress.TargetWeight - progress.CurrentWeight)
+
+	// 减重和增重都按起始体重到目标体重的距离计算完成比例
+	if total := progress.TargetWeight - progress.StartWeight; total != 0 {
+		done := (progress.CurrentWeight - progress.StartWeight) / total * 100
+		progress.Progress = roundMetric(math.Max(0, math.Min(100, done)))
+	} else if progress.Remaining == 0 {
+		progress.Progress = 100
+	}
+
+	// 变化方向朝向目标时按当前速度预计达到日期
+	if progress.CurrentDate != "" && progress.Remaining != 0 && progress.WeeklyRate != 0 &&
+		(progress.Remaining > 0) == (progress.WeeklyRate > 0) {
+		days := int(math.Ceil(progress.Remaining / progress.WeeklyRate * 7))
+		progress.EstimatedDate = addDays(progress.CurrentDate, days)
+	}
+
+	return progress, nil
+}
@@ -0,0 +1,429 @@
+package usecase_test
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"github.com/zhengshui/flow-link-server/internal/prutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"github.com/zhengshui/flow-link-server/mongo"
+	"github.com/zhengshui/flow-link-server/repository"
+	"github.com/zhengshui/flow-link-server/usecase"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// 聚合管道需要真实的 MongoDB，设置 MONGO_TEST_URI 后运行，例如 mongodb://localhost:27017
+func testDatabase(t *testing.T) mongo.Database {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGO_TEST_URI not set")
+	}
+
+	client, err := mongo.NewClient(uri)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.NoError(t, client.Connect(ctx))
+	require.NoError(t, client.Ping(ctx))
+
+	t.Cleanup(func() {
+		client.Disconnect(context.Background())
+	})
+
+	return client.Database("flow_link_test")
+}
+
+// 东八区的深夜训练在 UTC 中属于前一天，用来覆盖按用户时区归属日期
+var testLocation = timeutil.Location("Asia/Shanghai")
+
+type statsFixture struct {
+	userID  primitive.ObjectID
+	plan    domain.FitnessPlan
+	records []domain.TrainingRecord
+	stats   domain.StatsUsecase
+}
+
+// seedStats 写入最近 25 天的随机训练记录并重建汇总，固定随机种子保证可复现
+func seedStats(t *testing.T, db mongo.Database) statsFixture {
+	ctx := context.Background()
+	timeout := 10 * time.Second
+
+	tr := repository.NewTrainingRecordRepository(db, domain.CollectionTrainingRecord)
+	fp := repository.NewFitnessPlanRepository(db, domain.CollectionFitnessPlan)
+	pr := repository.NewPersonalRecordRepository(db, domain.CollectionPersonalRecord)
+	rr := repository.NewTrainingRollupRepository(db, domain.CollectionTrainingRollup)
+	br := repository.NewBodyMetricRepository(db, domain.CollectionBodyMetric)
+	ur := repository.NewUserRepository(db, domain.CollectionUser)
+
+	fixture := statsFixture{userID: primitive.NewObjectID()}
+	t.Cleanup(func() {
+		for _, collection := range []string{domain.CollectionTrainingRecord, domain.CollectionFitnessPlan, domain.CollectionTrainingRollup} {
+			db.Collection(collection).DeleteMany(ctx, bson.M{"userId": fixture.userID})
+		}
+		db.Collection(domain.CollectionUser).DeleteOne(ctx, bson.M{"_id": fixture.userID})
+	})
+
+	require.NoError(t, ur.Create(ctx, &domain.User{
+		ID:       fixture.userID,
+		Username: "stats_" + fixture.userID.Hex(),
+		TimeZone: testLocation.String(),
+	}))
+
+	now := time.Now().In(testLocation)
+	fixture.plan = domain.FitnessPlan{
+		ID:                  primitive.NewObjectID(),
+		UserID:              fixture.userID,
+		Name:                "测试计划",
+		DurationWeeks:       4,
+		TrainingDaysPerWeek: 3,
+		StartDate:           now.AddDate(0, 0, -20).Format("2006-01-02"),
+		EndDate:             now.AddDate(0, 0, 8).Format("2006-01-02"),
+		Status:              "进行中",
+		CompletedDays:       []int{1, 2, 4},
+		SkippedDays:         []int{3},
+	}
+	require.NoError(t, fp.Create(ctx, &fixture.plan))
+
+	names := []string{"Bench Press", "bench press ", "Squat", "Deadlift", "Pull Up", "Plank"}
+	muscles := []string{"胸", "腿", "背", "核心", ""}
+	random := rand.New(rand.NewSource(42))
+	intPtr := func(v int) *int { return &v }
+	floatPtr := func(v float64) *float64 { return &v }
+
+	for i := 0; i < 80; i++ {
+		day := now.AddDate(0, 0, -random.Intn(25))
+		startTime := time.Date(day.Year(), day.Month(), day.Day(), random.Intn(24), random.Intn(60), 0, 0, testLocation).UTC()
+		record := domain.TrainingRecord{
+			ID:        primitive.NewObjectID(),
+			UserID:    fixture.userID,
+			Title:     fmt.Sprintf("训练 %d", i),
+			StartTime: &startTime,
+			TotalSets: intPtr(random.Intn(20)),
+			CreatedAt: primitive.NewDateTimeFromTime(now),
+			UpdatedAt: primitive.NewDateTimeFromTime(now),
+		}
+		// 部分记录缺少时长、重量、卡路里，聚合时按 0 处理
+		if random.Intn(5) > 0 {
+			record.Duration = intPtr(20 + random.Intn(70))
+			record.CaloriesBurned = intPtr(100 + random.Intn(500))
+		}
+		if random.Intn(4) > 0 {
+			record.TotalWeight = floatPtr(float64(random.Intn(8000)) + 0.5)
+		}
+		if random.Intn(3) == 0 {
+			record.PlanID = fixture.plan.ID.Hex()
+		}
+
+		for j := 0; j < 1+random.Intn(4); j++ {
+			exercise := domain.Exercise{
+				ID:     j + 1,
+				Name:   names[random.Intn(len(names))],
+				Sets:   intPtr(1 + random.Intn(5)),
+				Reps:   intPtr(1 + random.Intn(12)),
+				Weight: floatPtr(float64(random.Intn(120)) + 2.5),
+			}
+			if muscle := muscles[random.Intn(len(muscles))]; muscle != "" {
+				exercise.MuscleGroup = &muscle
+			}
+			if exercise.Name == "Squat" {
+				exercise.ExerciseID = "64b7f0c2a1b2c3d4e5f60718"
+			}
+			record.Exercises = append(record.Exercises, exercise)
+		}
+
+		require.NoError(t, tr.Create(ctx, &record))
+		fixture.records = append(fixture.records, record)
+	}
+
+	// 没有开始时间的记录不计入任何统计
+	require.NoError(t, tr.Create(ctx, &domain.TrainingRecord{
+		ID:        primitive.NewObjectID(),
+		UserID:    fixture.userID,
+		Title:     "无开始时间",
+		Duration:  intPtr(30),
+		CreatedAt: primitive.NewDateTimeFromTime(now),
+	}))
+
+	require.NoError(t, usecase.NewTrainingRollupUsecase(tr, rr, ur, timeout).Rebuild(ctx, fixture.userID.Hex()))
+	fixture.stats = usecase.NewStatsUsecase(tr, fp, pr, rr, br, ur, domain.OneRMFormulaEpley, timeout)
+
+	return fixture
+}
+
+// recordDate 记录开始时间在用户时区的日期
+func recordDate(record domain.TrainingRecord) string {
+	return timeutil.Date(*record.StartTime, testLocation)
+}
+
+// inRange 开始时间在用户时区的 [startDate, endDate] 内
+func inRange(record domain.TrainingRecord, startDate, endDate string) bool {
+	return record.StartTime != nil && recordDate(record) >= startDate && recordDate(record) <= endDate
+}
+
+func muscleWeight(exercise domain.Exercise) float64 {
+	weight := 0.0
+	sets := 0
+	reps := 0
+	if exercise.Weight != nil {
+		weight = *exercise.Weight
+	}
+	if exercise.Sets != nil {
+		sets = *exercise.Sets
+	}
+	if exercise.Reps != nil {
+		reps = *exercise.Reps
+	}
+	return weight * float64(sets*reps)
+}
+
+func TestGetTrainingStatsMatchesRecordScan(t *testing.T) {
+	fixture := seedStats(t, testDatabase(t))
+
+	now := time.Now().In(testLocation)
+	startDate := now.AddDate(0, 0, -14).Format("2006-01-02")
+	endDate := now.Format("2006-01-02")
+
+	stats, err := fixture.stats.GetTrainingStats(context.Background(), fixture.userID.Hex(), "", startDate, endDate)
+	require.NoError(t, err)
+
+	// 改用汇总前的逐条记录统计
+	count, duration, sets, calories := 0, 0, 0, 0
+	weight := 0.0
+	daily := map[string]domain.DailyStats{}
+	muscleCount := map[string]int{}
+	exerciseCount := map[string]int{}
+	exerciseNames := map[string]map[string]bool{}
+	for _, record := range fixture.records {
+		if !inRange(record, startDate, endDate) {
+			continue
+		}
+		day := daily[recordDate(record)]
+		day.Date = recordDate(record)
+		count++
+		day.TrainingCount++
+		if record.Duration != nil {
+			duration += *record.Duration
+			day.Duration += *record.Duration
+		}
+		if record.TotalWeight != nil {
+			weight += *record.TotalWeight
+			day.Weight += *record.TotalWeight
+		}
+		if record.TotalSets != nil {
+			sets += *record.TotalSets
+			day.Sets += *record.TotalSets
+		}
+		if record.CaloriesBurned != nil {
+			calories += *record.CaloriesBurned
+			day.Calories += *record.CaloriesBurned
+		}
+		daily[day.Date] = day
+
+		for _, exercise := range record.Exercises {
+			if exercise.MuscleGroup != nil && *exercise.MuscleGroup != "" {
+				muscleCount[*exercise.MuscleGroup]++
+			}
+			key := prutil.ExerciseKey(exercise)
+			exerciseCount[key]++
+			if exerciseNames[key] == nil {
+				exerciseNames[key] = map[string]bool{}
+			}
+			exerciseNames[key][exercise.Name] = true
+		}
+	}
+	require.NotZero(t, count)
+
+	assert.Equal(t, count, stats.TotalTrainingCount)
+	assert.Equal(t, duration, stats.TotalDuration)
+	assert.InDelta(t, weight, stats.TotalWeight, 1e-6)
+	assert.Equal(t, sets, stats.TotalSets)
+	assert.Equal(t, calories, stats.TotalCalories)
+	assert.Equal(t, duration/count, stats.AvgDuration)
+	assert.InDelta(t, weight/float64(count), stats.AvgWeight, 1e-6)
+
+	assert.Len(t, stats.DailyStats, len(daily))
+	for _, day := range stats.DailyStats {
+		expected := daily[day.Date]
+		assert.Equal(t, expected.TrainingCount, day.TrainingCount, day.Date)
+		assert.Equal(t, expected.Duration, day.Duration, day.Date)
+		assert.InDelta(t, expected.Weight, day.Weight, 1e-6, day.Date)
+		assert.Equal(t, expected.Sets, day.Sets, day.Date)
+		assert.Equal(t, expected.Calories, day.Calories, day.Date)
+	}
+
+	// 原实现并列时取 map 遍历到的任意一个，这里只比较次数
+	maxMuscle, maxExercise := 0, 0
+	for _, c := range muscleCount {
+		if c > maxMuscle {
+			maxMuscle = c
+		}
+	}
+	for _, c := range exerciseCount {
+		if c > maxExercise {
+			maxExercise = c
+		}
+	}
+	assert.Equal(t, maxMuscle, muscleCount[stats.MostTrainedMuscle])
+	favorite := false
+	for key, c := range exerciseCount {
+		if c == maxExercise && exerciseNames[key][stats.FavoriteExercise] {
+			favorite = true
+		}
+	}
+	assert.True(t, favorite, stats.FavoriteExercise)
+}
+
+func TestGetMuscleGroupStatsMatchesRecordScan(t *testing.T) {
+	fixture := seedStats(t, testDatabase(t))
+
+	now := time.Now().In(testLocation)
+	startDate := now.AddDate(0, -1, 0).Format("2006-01-02")
+	endDate := now.Format("2006-01-02")
+
+	result, err := fixture.stats.GetMuscleGroupStats(context.Background(), fixture.userID.Hex(), "month")
+	require.NoError(t, err)
+
+	expected := map[string]*domain.MuscleGroupStats{}
+	total := 0
+	for _, record := range fixture.records {
+		if !inRange(record, startDate, endDate) {
+			continue
+		}
+		for _, exercise := range record.Exercises {
+			if exercise.MuscleGroup == nil || *exercise.MuscleGroup == "" {
+				continue
+			}
+			if _, exists := expected[*exercise.MuscleGroup]; !exists {
+				expected[*exercise.MuscleGroup] = &domain.MuscleGroupStats{MuscleGroup: *exercise.MuscleGroup}
+			}
+			expected[*exercise.MuscleGroup].TrainingCount++
+			expected[*exercise.MuscleGroup].TotalWeight += muscleWeight(exercise)
+			total++
+		}
+	}
+
+	assert.Len(t, result, len(expected))
+	for i, stats := range result {
+		want := expected[stats.MuscleGroup]
+		require.NotNil(t, want, stats.MuscleGroup)
+		assert.Equal(t, want.TrainingCount, stats.TrainingCount, stats.MuscleGroup)
+		assert.InDelta(t, want.TotalWeight, stats.TotalWeight, 1e-6, stats.MuscleGroup)
+		assert.Equal(t, want.TrainingCount*100/total, stats.Percentage, stats.MuscleGroup)
+		if i > 0 {
+			assert.GreaterOrEqual(t, result[i-1].TrainingCount, stats.TrainingCount)
+		}
+	}
+}
+
+func TestGetCalendarMatchesRecordScan(t *testing.T) {
+	fixture := seedStats(t, testDatabase(t))
+
+	// 记录可能跨月，两个月都比较
+	now := time.Now().In(testLocation)
+	for _, month := range []time.Time{now, now.AddDate(0, 0, -24)} {
+		days, err := fixture.stats.GetCalendar(context.Background(), fixture.userID.Hex(), month.Year(), int(month.Month()))
+		require.NoError(t, err)
+
+		firstDay := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, time.UTC)
+		lastDay := firstDay.AddDate(0, 1, -1)
+		require.Len(t, days, lastDay.Day())
+
+		for _, day := range days {
+			count, duration := 0, 0
+			for _, record := range fixture.records {
+				if !inRange(record, day.Date, day.Date) {
+					continue
+				}
+				count++
+				if record.Duration != nil {
+					duration += *record.Duration
+				}
+			}
+			assert.Equal(t, count > 0, day.HasTraining, day.Date)
+			assert.Equal(t, count, day.TrainingCount, day.Date)
+			assert.Equal(t, duration, day.TotalDuration, day.Date)
+		}
+	}
+}
+
+func TestGetPlanStatsMatchesRecordScan(t *testing.T) {
+	fixture := seedStats(t, testDatabase(t))
+
+	stats, err := fixture.stats.GetPlanStats(context.Background(), fixture.userID.Hex(), fixture.plan.ID.Hex(), "whole")
+	require.NoError(t, err)
+
+	duration, calories := 0, 0
+	weight := 0.0
+	for _, record := range fixture.records {
+		if record.PlanID != fixture.plan.ID.Hex() || !inRange(record, fixture.plan.StartDate, fixture.plan.EndDate) {
+			continue
+		}
+		if record.Duration != nil {
+			duration += *record.Duration
+		}
+		if record.TotalWeight != nil {
+			weight += *record.TotalWeight
+		}
+		if record.CaloriesBurned != nil {
+			calories += *record.CaloriesBurned
+		}
+	}
+
+	assert.Equal(t, duration, stats.TotalDuration)
+	assert.InDelta(t, weight, stats.TotalWeight, 1e-6)
+	assert.Equal(t, calories, stats.TotalCalories)
+	assert.Equal(t, 3, stats.CompletedDays)
+	assert.Equal(t, 1, stats.SkippedDays)
+	assert.Equal(t, 3*100/(4*3-1), stats.CompletionRate)
+}
+
+func TestGetPlanStatsFollowsSchedule(t *testing.T) {
+	user := domain.User{ID: primitive.NewObjectID(), TimeZone: "Asia/Shanghai"}
+	// 每周训练天数为 4，但计划只在周一、三、五训练，第 12 天（周五）的训练移动到第 13 天（周六）
+	plan := domain.FitnessPlan{
+		ID:                  primitive.NewObjectID(),
+		UserID:              user.ID,
+		DurationWeeks:       2,
+		TrainingDaysPerWeek: 4,
+		TrainingWeekdays:    []int{1, 3, 5},
+		StartDate:           "2026-01-05",
+		EndDate:             "2026-01-18",
+		TrainingDays:        []domain.TrainingDay{{DayNumber: 1, DayName: "A"}, {DayNumber: 2, DayName: "B"}},
+		Reschedules:         []domain.PlanReschedule{{Action: domain.RescheduleMove, DayNumber: 12, TargetDay: 13}},
+		CompletedDays:       []int{1, 3},
+		SkippedDays:         []int{5},
+	}
+
+	plans := mocks.NewFitnessPlanRepository(t)
+	plans.On("GetByID", mock.Anything, plan.ID.Hex()).Return(plan, nil).Once()
+	users := mocks.NewUserRepository(t)
+	users.On("GetByID", mock.Anything, user.ID.Hex()).Return(user, nil).Once()
+	records := mocks.NewTrainingRecordRepository(t)
+	records.On("SumByPlan", mock.Anything, user.ID.Hex(), plan.ID.Hex(), mock.Anything, mock.Anything).Return(domain.PlanTotals{}, nil).Once()
+
+	su := usecase.NewStatsUsecase(records, plans, nil, nil, nil, users, domain.OneRMFormulaEpley, time.Second)
+	stats, err := su.GetPlanStats(context.Background(), user.ID.Hex(), plan.ID.Hex(), "whole")
+	require.NoError(t, err)
+
+	assert.Equal(t, []domain.DailyStats{
+		{Date: "2026-01-05", CompletionStatus: "completed"},
+		{Date: "2026-01-07", CompletionStatus: "completed"},
+		{Date: "2026-01-09", CompletionStatus: "skipped"},
+		{Date: "2026-01-12", CompletionStatus: "missed"},
+		{Date: "2026-01-14", CompletionStatus: "missed"},
+		{Date: "2026-01-17", CompletionStatus: "missed"},
+	}, stats.Trend)
+	// 日程中共 6 次训练，跳过 1 次
+	assert.Equal(t, 2*100/(6-1), stats.CompletionRate)
+}
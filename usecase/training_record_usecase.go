@@ -6,18 +6,38 @@ import (
 	"time"
 
 	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/calorieutil"
+	"github.com/zhengshui/flow-link-server/internal/prutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type trainingRecordUsecase struct {
 	trainingRecordRepository domain.TrainingRecordRepository
+	personalRecordRepository domain.PersonalRecordRepository
+	userRepository           domain.UserRepository
+	trainingRollupUsecase    domain.TrainingRollupUsecase
+	exerciseResolver         domain.ExerciseResolver
+	planDayLinker            planDayLinker
+	transactor               domain.Transactor
+	oneRMFormula             string
 	contextTimeout           time.Duration
 }
 
-func NewTrainingRecordUsecase(trainingRecordRepository domain.TrainingRecordRepository, timeout time.Duration) domain.TrainingRecordUsecase {
+func NewTrainingRecordUsecase(trainingRecordRepository domain.TrainingRecordRepository, personalRecordRepository domain.PersonalRecordRepository, fitnessPlanRepository domain.FitnessPlanRepository, userRepository domain.UserRepository, trainingRollupUsecase domain.TrainingRollupUsecase, exerciseResolver domain.ExerciseResolver, transactor domain.Transactor, oneRMFormula string, timeout time.Duration) domain.TrainingRecordUsecase {
 	return &trainingRecordUsecase{
 		trainingRecordRepository: trainingRecordRepository,
-		contextTimeout:           timeout,
+		personalRecordRepository: personalRecordRepository,
+		userRepository:           userRepository,
+		trainingRollupUsecase:    trainingRollupUsecase,
+		exerciseResolver:         exerciseResolver,
+		planDayLinker: planDayLinker{
+			fitnessPlanRepository:    fitnessPlanRepository,
+			trainingRecordRepository: trainingRecordRepository,
+		},
+		transactor:     transactor,
+		oneRMFormula:   oneRMFormula,
+		contextTimeout: timeout,
 	}
 }
 
@@ -31,12 +51,32 @@ func (tu *trainingRecordUsecase) Create(c context.Context, userID string, reques
 		return nil, errors.New("invalid user ID")
 	}
 
+	// 不带时区的时间按用户时区解释，体重等用于估算卡路里
+	user, err := tu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	location := timeutil.Location(user.TimeZone)
+	startTime, err := parseTrainingTime(request.StartTime, location)
+	if err != nil {
+		return nil, err
+	}
+	endTime, err := parseTrainingTime(request.EndTime, location)
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize exercises array to avoid null in JSON
 	exercises := request.Exercises
 	if exercises == nil {
 		exercises = []domain.Exercise{}
 	}
 
+	// Link exercises to the catalog
+	if err := tu.exerciseResolver.Resolve(ctx, exercises); err != nil {
+		return nil, err
+	}
+
 	// Use PlanID directly if provided
 	var planID string
 	if request.PlanID != nil {
@@ -48,8 +88,8 @@ func (tu *trainingRecordUsecase) Create(c context.Context, userID string, reques
 		ID:               primitive.NewObjectID(),
 		UserID:           userObjectID,
 		Title:            request.Title,
-		StartTime:        request.StartTime,
-		EndTime:          request.EndTime,
+		StartTime:        startTime,
+		EndTime:          endTime,
 		Duration:         request.Duration,
 		Exercises:        exercises,
 		TotalWeight:      request.TotalWeight,
@@ -63,15 +103,45 @@ func (tu *trainingRecordUsecase) Create(c context.Context, userID string, reques
 		CreatedAt:        primitive.NewDateTimeFromTime(now),
 		UpdatedAt:        primitive.NewDateTimeFromTime(now),
 	}
+	prutil.ApplyVolume(record)
+	if record.CaloriesBurned == nil {
+		estimateCalories(user, record)
+	}
+
+	err = tu.planDayLinker.validate(ctx, userID, nil, record)
+	if err != nil {
+		return nil, err
+	}
+
+	// 先与历史比较，保存记录后再写入新的个人记录
+	events, err := tu.detectPersonalRecords(ctx, userID, record, "", location)
+	if err != nil {
+		return nil, err
+	}
 
-	err = tu.trainingRecordRepository.Create(ctx, record)
+	// 记录、个人记录和关联计划日的完成状态一起提交
+	err = tu.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := tu.trainingRecordRepository.Create(ctx, record); err != nil {
+			return err
+		}
+		if err := tu.personalRecordRepository.CreateMany(ctx, events); err != nil {
+			return err
+		}
+		return tu.planDayLinker.sync(ctx, userID, nil, record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.trainingRollupUsecase.Refresh(ctx, userID, rollupDates(record, location))
 	if err != nil {
 		return nil, err
 	}
 
 	return map[string]interface{}{
-		"id":        record.ID.Hex(),
-		"createdAt": record.CreatedAt,
+		"id":                 record.ID.Hex(),
+		"createdAt":          record.CreatedAt,
+		"newPersonalRecords": brokenPersonalRecords(events),
 	}, nil
 }
 
@@ -89,6 +159,12 @@ func (tu *trainingRecordUsecase) GetByID(c context.Context, userID, recordID str
 		return domain.TrainingRecord{}, errors.New("unauthorized access to training record")
 	}
 
+	location, err := userLocation(ctx, tu.userRepository, userID)
+	if err != nil {
+		return domain.TrainingRecord{}, err
+	}
+	localizeRecord(&record, location)
+
 	return record, nil
 }
 
@@ -96,7 +172,21 @@ func (tu *trainingRecordUsecase) GetList(c context.Context, userID string, page,
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
 
-	records, total, err := tu.trainingRecordRepository.GetByUserID(ctx, userID, page, pageSize, startDate, endDate, planID)
+	location, err := userLocation(ctx, tu.userRepository, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// 日期按用户时区的自然日筛选
+	var start, end time.Time
+	if startDate != "" && endDate != "" {
+		start, end, err = timeutil.DayRange(startDate, endDate, location)
+		if err != nil {
+			return nil, 0, domain.ErrInvalidTrainingTime
+		}
+	}
+
+	records, total, err := tu.trainingRecordRepository.GetByUserID(ctx, userID, page, pageSize, start, end, planID)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -105,38 +195,60 @@ func (tu *trainingRecordUsecase) GetList(c context.Context, userID string, page,
 	if records == nil {
 		records = []domain.TrainingRecord{}
 	}
+	for i := range records {
+		localizeRecord(&records[i], location)
+	}
 
 	return records, total, nil
 }
 
-func (tu *trainingRecordUsecase) Update(c context.Context, userID, recordID string, request *domain.UpdateTrainingRecordRequest) error {
+func (tu *trainingRecordUsecase) Update(c context.Context, userID, recordID string, request *domain.UpdateTrainingRecordRequest) ([]domain.PersonalRecordEvent, error) {
 	ctx, cancel := context.WithTimeout(c, tu.contextTimeout)
 	defer cancel()
 
 	// Get existing record and validate ownership
 	record, err := tu.trainingRecordRepository.GetByID(ctx, recordID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if record.UserID.Hex() != userID {
-		return errors.New("unauthorized access to training record")
+		return nil, errors.New("unauthorized access to training record")
 	}
 
+	user, err := tu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	location := timeutil.Location(user.TimeZone)
+
+	// 修改开始时间会让记录换到别的日期，新旧日期的汇总都要刷新
+	dates := rollupDates(&record, location)
+	before := record
+
 	// Update fields if provided (指针不为nil时更新)
 	if request.Title != nil {
 		record.Title = *request.Title
 	}
 	if request.StartTime != nil {
-		record.StartTime = request.StartTime
+		record.StartTime, err = parseTrainingTime(request.StartTime, location)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if request.EndTime != nil {
-		record.EndTime = request.EndTime
+		record.EndTime, err = parseTrainingTime(request.EndTime, location)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if request.Duration != nil {
 		record.Duration = request.Duration
 	}
 	if request.Exercises != nil {
+		if err := tu.exerciseResolver.Resolve(ctx, request.Exercises); err != nil {
+			return nil, err
+		}
 		record.Exercises = request.Exercises
 	}
 	if request.TotalWeight != nil {
@@ -147,6 +259,7 @@ func (tu *trainingRecordUsecase) Update(c context.Context, userID, recordID stri
 	}
 	if request.CaloriesBurned != nil {
 		record.CaloriesBurned = request.CaloriesBurned
+		record.CaloriesEstimated = false
 	}
 	if request.Notes != nil {
 		record.Notes = request.Notes
@@ -165,8 +278,46 @@ func (tu *trainingRecordUsecase) Update(c context.Context, userID, recordID stri
 	}
 
 	record.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+	prutil.ApplyVolume(&record)
+	// 用户提交过的卡路里保持不变，估算值随动作和时长的修改重新计算
+	if record.CaloriesBurned == nil || record.CaloriesEstimated {
+		estimateCalories(user, &record)
+	}
+
+	err = tu.planDayLinker.validate(ctx, userID, &before, &record)
+	if err != nil {
+		return nil, err
+	}
+
+	// 本记录之前产生的个人记录不参与比较，保存后按新的组数据重新写入
+	events, err := tu.detectPersonalRecords(ctx, userID, &record, recordID, location)
+	if err != nil {
+		return nil, err
+	}
+
+	// 换到别的计划日时，旧计划日和新计划日的完成状态与记录一起提交
+	err = tu.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := tu.trainingRecordRepository.Update(ctx, recordID, &record); err != nil {
+			return err
+		}
+		if err := tu.personalRecordRepository.DeleteByRecordID(ctx, recordID); err != nil {
+			return err
+		}
+		if err := tu.personalRecordRepository.CreateMany(ctx, events); err != nil {
+			return err
+		}
+		return tu.planDayLinker.sync(ctx, userID, &before, &record)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.trainingRollupUsecase.Refresh(ctx, userID, append(dates, rollupDates(&record, location)...))
+	if err != nil {
+		return nil, err
+	}
 
-	return tu.trainingRecordRepository.Update(ctx, recordID, &record)
+	return brokenPersonalRecords(events), nil
 }
 
 func (tu *trainingRecordUsecase) Delete(c context.Context, userID, recordID string) error {
@@ -183,5 +334,171 @@ func (tu *trainingRecordUsecase) Delete(c context.Context, userID, recordID stri
 		return errors.New("unauthorized access to training record")
 	}
 
-	return tu.trainingRecordRepository.Delete(ctx, recordID)
+	// 删除计划日唯一的关联记录时，该日恢复为未完成
+	err = tu.transactor.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := tu.trainingRecordRepository.Delete(ctx, recordID); err != nil {
+			return err
+		}
+		if err := tu.personalRecordRepository.DeleteByRecordID(ctx, recordID); err != nil {
+			return err
+		}
+		return tu.planDayLinker.sync(ctx, userID, &record, nil)
+	})
+	if err != nil {
+		return err
+	}
+
+	location, err := userLocation(ctx, tu.userRepository, userID)
+	if err != nil {
+		return err
+	}
+
+	return tu.trainingRollupUsecase.Refresh(ctx, userID, rollupDates(&record, location))
+}
+
+// estimateCalories 按 MET 估算消耗的卡路里，缺少体重或时长无法估算时保持为空
+func estimateCalories(user domain.User, record *domain.TrainingRecord) {
+	calories, ok := calorieutil.Estimate(user, *record)
+	if !ok {
+		record.CaloriesBurned = nil
+		record.CaloriesEstimated = false
+		return
+	}
+	record.CaloriesBurned = &calories
+	record.CaloriesEstimated = true
+}
+
+// detectPersonalRecords 将记录中的成绩与用户的个人记录历史比较，返回需要写入历史的条目。
+// 用户还没有任何历史时，先用已有训练记录（排除 excludeRecordID）建立基线。
+func (tu *trainingRecordUsecase) detectPersonalRecords(ctx context.Context, userID string, record *domain.TrainingRecord, excludeRecordID string, location *time.Location) ([]domain.PersonalRecordEvent, error) {
+	tracker := prutil.NewTracker(tu.oneRMFormula, location)
+	tracker.Add(record)
+
+	var candidates []domain.PersonalRecordEvent
+	for _, pr := range tracker.Records() {
+		candidates = append(candidates, prutil.Events(pr)...)
+	}
+	if len(candidates) == 0 {
+		return []domain.PersonalRecordEvent{}, nil
+	}
+
+	count, err := tu.personalRecordRepository.CountByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []domain.PersonalRecordEvent
+	if count == 0 {
+		history, err = tu.seedPersonalRecords(ctx, userID, excludeRecordID, location)
+	} else {
+		keys := []string{}
+		for _, candidate := range candidates {
+			keys = append(keys, candidate.ExerciseKey)
+		}
+		history, err = tu.personalRecordRepository.GetByExerciseKeys(ctx, userID, keys, excludeRecordID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	best := make(map[string]float64)
+	for _, event := range history {
+		key := prutil.EventKey(event)
+		if value, exists := best[key]; !exists || event.Value > value {
+			best[key] = event.Value
+		}
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	events := []domain.PersonalRecordEvent{}
+	for _, candidate := range candidates {
+		previous, exists := best[prutil.EventKey(candidate)]
+		if exists && candidate.Value <= previous {
+			continue
+		}
+		if exists {
+			candidate.PreviousValue = &previous
+		}
+		candidate.ID = primitive.NewObjectID()
+		candidate.UserID = record.UserID
+		candidate.CreatedAt = now
+		events = append(events, candidate)
+	}
+
+	return events, nil
+}
+
+// seedPersonalRecords 根据用户已有的训练记录写入每项当前最佳作为历史基线
+func (tu *trainingRecordUsecase) seedPersonalRecords(ctx context.Context, userID, excludeRecordID string, location *time.Location) ([]domain.PersonalRecordEvent, error) {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	tracker := prutil.NewTracker(tu.oneRMFormula, location)
+	err = eachRecord(ctx, tu.trainingRecordRepository, userID, time.Time{}, time.Time{}, "", func(record *domain.TrainingRecord) {
+		if record.ID.Hex() != excludeRecordID {
+			tracker.Add(record)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := primitive.NewDateTimeFromTime(time.Now())
+	seeds := []domain.PersonalRecordEvent{}
+	for _, pr := range tracker.Records() {
+		for _, event := range prutil.Events(pr) {
+			event.ID = primitive.NewObjectID()
+			event.UserID = userObjectID
+			event.CreatedAt = now
+			seeds = append(seeds, event)
+		}
+	}
+
+	err = tu.personalRecordRepository.CreateMany(ctx, seeds)
+	return seeds, err
+}
+
+// brokenPersonalRecords 只返回打破旧成绩的条目，第一次做的动作只写入历史
+func brokenPersonalRecords(events []domain.PersonalRecordEvent) []domain.PersonalRecordEvent {
+	broken := []domain.PersonalRecordEvent{}
+	for _, event := range events {
+		if event.PreviousValue != nil {
+			broken = append(broken, event)
+		}
+	}
+	return broken
+}
+
+// rollupDates 记录在用户时区所属的汇总日期，没有开始时间时为空
+func rollupDates(record *domain.TrainingRecord, location *time.Location) []string {
+	if date, ok := rollupDate(record, location); ok {
+		return []string{date}
+	}
+	return []string{}
+}
+
+// parseTrainingTime 解析请求中的训练时间，空字符串表示不设置
+func parseTrainingTime(value *string, location *time.Location) (*time.Time, error) {
+	if value == nil || *value == "" {
+		return nil, nil
+	}
+	t, err := timeutil.ParseDateTime(*value, location)
+	if err != nil {
+		return nil, domain.ErrInvalidTrainingTime
+	}
+	return &t, nil
+}
+
+// localizeRecord 将记录的时间转换到用户时区，JSON 输出带对应的偏移
+func localizeRecord(record *domain.TrainingRecord, location *time.Location) {
+	if record.StartTime != nil {
+		startTime := record.StartTime.In(location)
+		record.StartTime = &startTime
+	}
+	if record.EndTime != nil {
+		endTime := record.EndTime.In(location)
+		record.EndTime = &endTime
+	}
 }
@@ -0,0 +1,63 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/domain/mocks"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDetectPersonalRecords(t *testing.T) {
+	userID := primitive.NewObjectID()
+	sets, reps, weight := 1, 1, 110.0
+	record := &domain.TrainingRecord{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		Exercises: []domain.Exercise{{Name: "Squat", Sets: &sets, Reps: &reps, Weight: &weight}},
+	}
+
+	personalRecords := mocks.NewPersonalRecordRepository(t)
+	personalRecords.On("CountByUserID", mock.Anything, userID.Hex()).Return(int64(3), nil).Once()
+	// 更新记录时排除该记录自己产生的历史
+	personalRecords.On("GetByExerciseKeys", mock.Anything, userID.Hex(), mock.Anything, record.ID.Hex()).Return([]domain.PersonalRecordEvent{
+		{ExerciseKey: "squat", Type: domain.PRTypeMaxWeight, Value: 90},
+		{ExerciseKey: "squat", Type: domain.PRTypeMaxWeight, Value: 100},
+		{ExerciseKey: "squat", Type: domain.PRTypeRepMax, Target: 1, Value: 120},
+		{ExerciseKey: "squat", Type: domain.PRTypeSessionVolume, Value: 1000},
+	}, nil).Once()
+
+	tu := &trainingRecordUsecase{personalRecordRepository: personalRecords, oneRMFormula: domain.OneRMFormulaEpley}
+	events, err := tu.detectPersonalRecords(context.Background(), userID.Hex(), record, record.ID.Hex(), time.UTC)
+	require.NoError(t, err)
+
+	// 最大重量打破旧成绩，估算 1RM 第一次有成绩，次数档位和训练量未超过历史
+	require.Len(t, events, 2)
+	assert.Equal(t, domain.PRTypeMaxWeight, events[0].Type)
+	assert.Equal(t, 110.0, events[0].Value)
+	assert.Equal(t, 100.0, *events[0].PreviousValue)
+	assert.Equal(t, userID, events[0].UserID)
+	assert.Equal(t, record.ID, events[0].RecordID)
+	assert.Equal(t, domain.PRTypeEstimatedOneRM, events[1].Type)
+	assert.Nil(t, events[1].PreviousValue)
+
+	// 响应只列出打破旧成绩的条目
+	broken := brokenPersonalRecords(events)
+	require.Len(t, broken, 1)
+	assert.Equal(t, domain.PRTypeMaxWeight, broken[0].Type)
+}
+
+func TestDetectPersonalRecordsWithoutCompletedSets(t *testing.T) {
+	// 没有完成的组时不查询历史
+	tu := &trainingRecordUsecase{personalRecordRepository: mocks.NewPersonalRecordRepository(t), oneRMFormula: domain.OneRMFormulaEpley}
+	record := &domain.TrainingRecord{ID: primitive.NewObjectID(), Exercises: []domain.Exercise{{Name: "Squat"}}}
+
+	events, err := tu.detectPersonalRecords(context.Background(), primitive.NewObjectID().Hex(), record, "", time.UTC)
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
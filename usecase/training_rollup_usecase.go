@@ -0,0 +1,327 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/prutil"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordPageSize 分页读取训练记录的页大小
+const recordPageSize = 500
+
+// eachRecord 分页读取用户在时间范围内（可按计划过滤）的全部训练记录并逐条交给 fn，不设条数上限
+func eachRecord(ctx context.Context, trainingRecordRepository domain.TrainingRecordRepository, userID string, start, end time.Time, planID string, fn func(record *domain.TrainingRecord)) error {
+	for page := 1; ; page++ {
+		records, total, err := trainingRecordRepository.GetByUserID(ctx, userID, page, recordPageSize, start, end, planID)
+		if err != nil {
+			return err
+		}
+		for i := range records {
+			fn(&records[i])
+		}
+		if len(records) == 0 || int64(page*recordPageSize) >= total {
+			return nil
+		}
+	}
+}
+
+type trainingRollupUsecase struct {
+	trainingRecordRepository domain.TrainingRecordRepository
+	trainingRollupRepository domain.TrainingRollupRepository
+	userRepository           domain.UserRepository
+	contextTimeout           time.Duration
+}
+
+func NewTrainingRollupUsecase(trainingRecordRepository domain.TrainingRecordRepository, trainingRollupRepository domain.TrainingRollupRepository, userRepository domain.UserRepository, timeout time.Duration) domain.TrainingRollupUsecase {
+	return &trainingRollupUsecase{
+		trainingRecordRepository: trainingRecordRepository,
+		trainingRollupRepository: trainingRollupRepository,
+		userRepository:           userRepository,
+		contextTimeout:           timeout,
+	}
+}
+
+func (ru *trainingRollupUsecase) Refresh(c context.Context, userID string, dates []string) error {
+	ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+	defer cancel()
+
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	location, err := userLocation(ctx, ru.userRepository, userID)
+	if err != nil {
+		return err
+	}
+
+	weeks := map[string]bool{}
+	for _, date := range uniqueDates(dates) {
+		start, end, err := timeutil.DayRange(date, date, location)
+		if err != nil {
+			return err
+		}
+		day := newRollup(userObjectID, domain.RollupPeriodDay, date)
+		err = eachRecord(ctx, ru.trainingRecordRepository, userID, start, end, "", func(record *domain.TrainingRecord) {
+			addRecordToRollup(&day, record)
+		})
+		if err != nil {
+			return err
+		}
+		if err := ru.save(ctx, userID, &day); err != nil {
+			return err
+		}
+		weeks[weekStart(date)] = true
+	}
+
+	// 周汇总由当周的日汇总合并得到
+	for start := range weeks {
+		days, err := ru.trainingRollupRepository.GetRange(ctx, userID, domain.RollupPeriodDay, start, addDays(start, 6))
+		if err != nil {
+			return err
+		}
+
+		week := newRollup(userObjectID, domain.RollupPeriodWeek, start)
+		for _, day := range days {
+			mergeRollup(&week, day)
+		}
+		if err := ru.save(ctx, userID, &week); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (ru *trainingRollupUsecase) Rebuild(c context.Context, userID string) error {
+	userObjectID, err := primitive.ObjectIDFromHex(userID)
+	if err != nil {
+		return err
+	}
+
+	// 全量重建可能超过单次请求的超时时间，每次读写单独计时
+	ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+	location, err := userLocation(ctx, ru.userRepository, userID)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	days := map[string]*domain.TrainingRollup{}
+	for page := 1; ; page++ {
+		ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+		records, total, err := ru.trainingRecordRepository.GetByUserID(ctx, userID, page, recordPageSize, time.Time{}, time.Time{}, "")
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		for i := range records {
+			date, ok := rollupDate(&records[i], location)
+			if !ok {
+				continue
+			}
+			if _, exists := days[date]; !exists {
+				day := newRollup(userObjectID, domain.RollupPeriodDay, date)
+				days[date] = &day
+			}
+			addRecordToRollup(days[date], &records[i])
+		}
+
+		if len(records) == 0 || int64(page*recordPageSize) >= total {
+			break
+		}
+	}
+
+	weeks := map[string]*domain.TrainingRollup{}
+	for date, day := range days {
+		start := weekStart(date)
+		if _, exists := weeks[start]; !exists {
+			week := newRollup(userObjectID, domain.RollupPeriodWeek, start)
+			weeks[start] = &week
+		}
+		mergeRollup(weeks[start], *day)
+	}
+
+	ctx, cancel = context.WithTimeout(c, ru.contextTimeout)
+	err = ru.trainingRollupRepository.DeleteByUserID(ctx, userID)
+	cancel()
+	if err != nil {
+		return err
+	}
+
+	for _, rollups := range []map[string]*domain.TrainingRollup{days, weeks} {
+		for _, rollup := range rollups {
+			ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+			err := ru.trainingRollupRepository.Upsert(ctx, rollup)
+			cancel()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (ru *trainingRollupUsecase) RebuildAll(c context.Context) (int, error) {
+	ctx, cancel := context.WithTimeout(c, ru.contextTimeout)
+	users, err := ru.userRepository.Fetch(ctx)
+	cancel()
+	if err != nil {
+		return 0, err
+	}
+
+	for i, user := range users {
+		if err := ru.Rebuild(c, user.ID.Hex()); err != nil {
+			return i, err
+		}
+	}
+
+	return len(users), nil
+}
+
+// save 没有训练的周期删除汇总，避免留下空文档
+func (ru *trainingRollupUsecase) save(ctx context.Context, userID string, rollup *domain.TrainingRollup) error {
+	if rollup.TrainingCount == 0 {
+		return ru.trainingRollupRepository.Delete(ctx, userID, rollup.Period, rollup.PeriodStart)
+	}
+	sortRollup(rollup)
+	return ru.trainingRollupRepository.Upsert(ctx, rollup)
+}
+
+func newRollup(userID primitive.ObjectID, period, periodStart string) domain.TrainingRollup {
+	return domain.TrainingRollup{
+		UserID:       userID,
+		Period:       period,
+		PeriodStart:  periodStart,
+		MuscleGroups: []domain.MuscleGroupRollup{},
+		Exercises:    []domain.ExerciseRollup{},
+	}
+}
+
+// addRecordToRollup 将一条训练记录计入汇总
+func addRecordToRollup(rollup *domain.TrainingRollup, record *domain.TrainingRecord) {
+	rollup.TrainingCount++
+	if record.Duration != nil {
+		rollup.Duration += *record.Duration
+	}
+	if record.TotalWeight != nil {
+		rollup.Weight += *record.TotalWeight
+	}
+	if record.TotalSets != nil {
+		rollup.Sets += *record.TotalSets
+	}
+	if record.CaloriesBurned != nil {
+		rollup.Calories += *record.CaloriesBurned
+	}
+
+	for _, exercise := range record.Exercises {
+		if exercise.MuscleGroup != nil && *exercise.MuscleGroup != "" {
+			addMuscleGroup(rollup, domain.MuscleGroupRollup{
+				MuscleGroup:   *exercise.MuscleGroup,
+				TrainingCount: 1,
+				TotalWeight:   prutil.ExerciseVolume(exercise),
+			})
+		}
+		if key := prutil.ExerciseKey(exercise); key != "" {
+			addExercise(rollup, domain.ExerciseRollup{Key: key, Name: exercise.Name, Count: 1})
+		}
+	}
+}
+
+// mergeRollup 将 other 的数据累加到 rollup
+func mergeRollup(rollup *domain.TrainingRollup, other domain.TrainingRollup) {
+	rollup.TrainingCount += other.TrainingCount
+	rollup.Duration += other.Duration
+	rollup.Weight += other.Weight
+	rollup.Sets += other.Sets
+	rollup.Calories += other.Calories
+	for _, muscleGroup := range other.MuscleGroups {
+		addMuscleGroup(rollup, muscleGroup)
+	}
+	for _, exercise := range other.Exercises {
+		addExercise(rollup, exercise)
+	}
+}
+
+func addMuscleGroup(rollup *domain.TrainingRollup, muscleGroup domain.MuscleGroupRollup) {
+	for i := range rollup.MuscleGroups {
+		if rollup.MuscleGroups[i].MuscleGroup == muscleGroup.MuscleGroup {
+			rollup.MuscleGroups[i].TrainingCount += muscleGroup.TrainingCount
+			rollup.MuscleGroups[i].TotalWeight += muscleGroup.TotalWeight
+			return
+		}
+	}
+	rollup.MuscleGroups = append(rollup.MuscleGroups, muscleGroup)
+}
+
+func addExercise(rollup *domain.TrainingRollup, exercise domain.ExerciseRollup) {
+	for i := range rollup.Exercises {
+		if rollup.Exercises[i].Key == exercise.Key {
+			rollup.Exercises[i].Count += exercise.Count
+			return
+		}
+	}
+	rollup.Exercises = append(rollup.Exercises, exercise)
+}
+
+// sortRollup 按次数从多到少排列，便于直接取最常训练的肌群和动作
+func sortRollup(rollup *domain.TrainingRollup) {
+	sort.SliceStable(rollup.MuscleGroups, func(i, j int) bool {
+		if rollup.MuscleGroups[i].TrainingCount != rollup.MuscleGroups[j].TrainingCount {
+			return rollup.MuscleGroups[i].TrainingCount > rollup.MuscleGroups[j].TrainingCount
+		}
+		return rollup.MuscleGroups[i].MuscleGroup < rollup.MuscleGroups[j].MuscleGroup
+	})
+	sort.SliceStable(rollup.Exercises, func(i, j int) bool {
+		if rollup.Exercises[i].Count != rollup.Exercises[j].Count {
+			return rollup.Exercises[i].Count > rollup.Exercises[j].Count
+		}
+		return rollup.Exercises[i].Key < rollup.Exercises[j].Key
+	})
+}
+
+// rollupDate 训练记录开始时间在用户时区的日期，没有开始时间的记录不计入汇总
+func rollupDate(record *domain.TrainingRecord, location *time.Location) (string, bool) {
+	if record.StartTime == nil {
+		return "", false
+	}
+	return timeutil.Date(*record.StartTime, location), true
+}
+
+// weekStart 日期所在自然周的周一
+func weekStart(date string) string {
+	day, err := time.Parse(timeutil.DateLayout, date)
+	if err != nil {
+		return date
+	}
+	offset := (int(day.Weekday()) + 6) % 7
+	return day.AddDate(0, 0, -offset).Format(timeutil.DateLayout)
+}
+
+func addDays(date string, days int) string {
+	day, err := time.Parse(timeutil.DateLayout, date)
+	if err != nil {
+		return date
+	}
+	return day.AddDate(0, 0, days).Format(timeutil.DateLayout)
+}
+
+func uniqueDates(dates []string) []string {
+	seen := map[string]bool{}
+	result := []string{}
+	for _, date := range dates {
+		if date == "" || seen[date] {
+			continue
+		}
+		seen[date] = true
+		result = append(result, date)
+	}
+	return result
+}
@@ -2,21 +2,42 @@ package usecase
 
 import (
 	"context"
+	"io"
+	"strings"
 	"time"
 
 	"github.com/zhengshui/flow-link-server/domain"
+	"github.com/zhengshui/flow-link-server/internal/timeutil"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// avatarSize 头像裁成正方形后的边长(px)
+const avatarSize = 256
+
 type userInfoUsecase struct {
-	userRepository domain.UserRepository
-	contextTimeout time.Duration
+	userRepository        domain.UserRepository
+	bodyMetricRepository  domain.BodyMetricRepository
+	trainingRollupUsecase domain.TrainingRollupUsecase
+	blobStore             domain.BlobStore
+	maxUploadSize         int64
+	contextTimeout        time.Duration
 }
 
-func NewUserInfoUsecase(userRepository domain.UserRepository, timeout time.Duration) domain.UserInfoUsecase {
+func NewUserInfoUsecase(
+	userRepository domain.UserRepository,
+	bodyMetricRepository domain.BodyMetricRepository,
+	trainingRollupUsecase domain.TrainingRollupUsecase,
+	blobStore domain.BlobStore,
+	maxUploadSize int64,
+	timeout time.Duration,
+) domain.UserInfoUsecase {
 	return &userInfoUsecase{
-		userRepository: userRepository,
-		contextTimeout: timeout,
+		userRepository:        userRepository,
+		bodyMetricRepository:  bodyMetricRepository,
+		trainingRollupUsecase: trainingRollupUsecase,
+		blobStore:             blobStore,
+		maxUploadSize:         maxUploadSize,
+		contextTimeout:        timeout,
 	}
 }
 
@@ -40,14 +61,26 @@ func (uu *userInfoUsecase) UpdateUserInfo(c context.Context, userID string, requ
 	if request.Nickname != "" {
 		user.Nickname = request.Nickname
 	}
-	if request.AvatarUrl != "" {
+	// 改用外部头像地址后不再需要已上传的头像
+	oldAvatarKey := ""
+	if request.AvatarUrl != "" && request.AvatarUrl != user.AvatarUrl {
 		user.AvatarUrl = request.AvatarUrl
+		oldAvatarKey = user.AvatarKey
+		user.AvatarKey = ""
+	}
+	email := domain.NormalizeEmail(request.Email)
+	phone := strings.TrimSpace(request.Phone)
+	err = checkContactAvailable(ctx, uu.userRepository, userID, email, phone)
+	if err != nil {
+		return err
 	}
-	if request.Email != "" {
-		user.Email = request.Email
+	if email != "" && email != user.Email {
+		user.Email = email
+		// 更换邮箱后需要重新验证
+		user.EmailVerified = false
 	}
-	if request.Phone != "" {
-		user.Phone = request.Phone
+	if phone != "" {
+		user.Phone = phone
 	}
 	if request.Gender != "" {
 		user.Gender = request.Gender
@@ -58,8 +91,10 @@ func (uu *userInfoUsecase) UpdateUserInfo(c context.Context, userID string, requ
 	if request.Height > 0 {
 		user.Height = request.Height
 	}
-	if request.Weight > 0 {
+	weightChanged := false
+	if request.Weight > 0 && request.Weight != user.Weight {
 		user.Weight = request.Weight
+		weightChanged = true
 	}
 	if request.TargetWeight > 0 {
 		user.TargetWeight = request.TargetWeight
@@ -67,8 +102,117 @@ func (uu *userInfoUsecase) UpdateUserInfo(c context.Context, userID string, requ
 	if request.FitnessGoal != "" {
 		user.FitnessGoal = request.FitnessGoal
 	}
+	timeZoneChanged := false
+	if request.TimeZone != "" && request.TimeZone != user.TimeZone {
+		if !timeutil.ValidLocation(request.TimeZone) {
+			return domain.ErrInvalidTimeZone
+		}
+		user.TimeZone = request.TimeZone
+		timeZoneChanged = true
+	}
+	if request.TrainingWeekdays != nil {
+		user.TrainingWeekdays, err = sortedWeekdays(request.TrainingWeekdays)
+		if err != nil {
+			return err
+		}
+	}
+
+	user.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
+
+	err = uu.userRepository.Update(ctx, userID, &user)
+	if err != nil {
+		return err
+	}
+
+	if oldAvatarKey != "" {
+		err = uu.blobStore.Delete(ctx, oldAvatarKey)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 修改体重同时记入当天的身体数据，保留历史
+	if weightChanged {
+		err = uu.bodyMetricRepository.UpsertWeight(ctx, userID, timeutil.Today(timeutil.Location(user.TimeZone)), user.Weight)
+		if err != nil {
+			return err
+		}
+	}
+
+	// 训练汇总按用户时区的日期归属，时区变化后重新计算
+	if timeZoneChanged {
+		return uu.trainingRollupUsecase.Rebuild(c, userID)
+	}
+
+	return nil
+}
+
+func (uu *userInfoUsecase) UploadAvatar(c context.Context, userID string, file io.Reader) (string, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
 
+	picture, err := readImage(file, uu.maxUploadSize)
+	if err != nil {
+		return "", err
+	}
+	avatar, err := picture.Thumbnail(avatarSize, true)
+	if err != nil {
+		return "", err
+	}
+
+	// 每次上传使用新的 key，头像地址随之变化，客户端缓存不会显示旧头像
+	version := primitive.NewObjectID().Hex()
+	key := userBlobPrefix(userID) + "avatar/" + version + ".jpg"
+	err = uu.blobStore.Put(ctx, key, avatar.Data, avatar.ContentType)
+	if err != nil {
+		return "", err
+	}
+
+	oldAvatarKey := user.AvatarKey
+	user.AvatarKey = key
+	user.AvatarUrl = "/api/avatars/" + userID + "?v=" + version
 	user.UpdatedAt = primitive.NewDateTimeFromTime(time.Now())
 
-	return uu.userRepository.Update(ctx, userID, &user)
+	err = uu.userRepository.Update(ctx, userID, &user)
+	if err != nil {
+		uu.blobStore.Delete(ctx, key)
+		return "", err
+	}
+
+	if oldAvatarKey != "" {
+		err = uu.blobStore.Delete(ctx, oldAvatarKey)
+		if err != nil {
+			return "", err
+		}
+	}
+	return user.AvatarUrl, nil
+}
+
+func (uu *userInfoUsecase) OpenAvatar(c context.Context, userID string) (io.ReadCloser, domain.BlobInfo, error) {
+	ctx, cancel := context.WithTimeout(c, uu.contextTimeout)
+	defer cancel()
+
+	user, err := uu.userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, domain.BlobInfo{}, err
+	}
+	if user.AvatarKey == "" {
+		return nil, domain.BlobInfo{}, domain.ErrBlobNotFound
+	}
+	// 内容在返回后才读取，不能使用本方法结束时就取消的 ctx
+	return uu.blobStore.Get(c, user.AvatarKey)
+}
+
+// userLocation 用户设置的时区，未设置时为服务默认时区
+func userLocation(ctx context.Context, userRepository domain.UserRepository, userID string) (*time.Location, error) {
+	user, err := userRepository.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return timeutil.Location(user.TimeZone), nil
 }